@@ -58,6 +58,42 @@ func (a *API) RUN() error {
 		api.DELETE("/product/delete/:id", a.producthandler.DeleteProduct)
 		api.GET("/products", a.producthandler.GetAllProducts)
 		api.GET("/product/:id", a.producthandler.GetProduct)
+		api.PUT("/product/:id/status", a.producthandler.ChangeProductStatus)
+		api.GET("/product/:id/revisions", a.producthandler.ListProductRevisions)
+		api.POST("/product/:id/revert", a.producthandler.RevertProduct)
+		api.POST("/products/import", a.producthandler.ImportProducts)
+		api.GET("/products/imports", a.producthandler.ListProductImports)
+		api.GET("/products/imports/:id", a.producthandler.GetProductImport)
+		api.GET("/products/export", a.producthandler.ExportProducts)
+		api.GET("/product/:id/stock/movements", a.producthandler.ListStockMovements)
+		api.POST("/product/:id/stock/adjust", a.producthandler.AdjustStock)
+		api.PUT("/product/:id/stock/threshold", a.producthandler.SetLowStockThreshold)
+		api.GET("/products/low-stock", a.producthandler.ListLowStockAlerts)
+		api.POST("/products/stock/reconcile", a.producthandler.ReconcileStock)
+		api.GET("/cart", a.producthandler.GetCart)
+		api.DELETE("/cart", a.producthandler.ClearCart)
+		api.POST("/cart/items", a.producthandler.AddCartItem)
+		api.PUT("/cart/items/:product_id", a.producthandler.UpdateCartItem)
+		api.DELETE("/cart/items/:product_id", a.producthandler.RemoveCartItem)
+		api.POST("/cart/merge", a.producthandler.MergeCart)
+		api.POST("/cart/checkout", a.producthandler.CheckoutCart)
+		api.POST("/cart/coupons", a.producthandler.ApplyCartCoupon)
+		api.DELETE("/cart/coupons/:code", a.producthandler.RemoveCartCoupon)
+		api.POST("/wishlists", a.producthandler.CreateWishlist)
+		api.GET("/wishlists", a.producthandler.ListWishlists)
+		api.GET("/wishlists/:id", a.producthandler.GetWishlist)
+		api.GET("/wishlists/shared/:token", a.producthandler.GetSharedWishlist)
+		api.DELETE("/wishlists/:id", a.producthandler.DeleteWishlist)
+		api.POST("/wishlists/items", a.producthandler.AddWishlistItem)
+		api.DELETE("/wishlists/:id/items/:product_id", a.producthandler.RemoveWishlistItem)
+		api.PUT("/wishlists/:id/share", a.producthandler.ShareWishlist)
+		api.POST("/wishlists/:id/items/:product_id/move-to-cart", a.producthandler.MoveWishlistItemToCart)
+		api.GET("/products/saves", a.producthandler.GetProductSaveCounts)
+		api.GET("/notifications", a.producthandler.ListNotifications)
+		api.PUT("/notifications/read", a.producthandler.MarkNotificationsRead)
+		api.POST("/promotions", a.producthandler.CreatePromotion)
+		api.GET("/promotions", a.producthandler.ListPromotions)
+		api.PUT("/promotions/:id/active", a.producthandler.SetPromotionActive)
 		api.POST("/product/search", a.producthandler.SearchAndFilterProduct)
 		api.POST("/product/rate", a.producthandler.RateProduct)
 		api.GET("/product/ratings/:product_id", a.producthandler.GetAllRatings)
@@ -65,12 +101,44 @@ func (a *API) RUN() error {
 		api.PUT("/order/cancel", a.producthandler.CancelOrder)
 		api.PUT("/order/status", a.producthandler.ChangeOrderStatus)
 		api.GET("/order/all", a.producthandler.GetAllOrders)
+		api.GET("/order/my", a.producthandler.ListMyOrders)
 		api.GET("/order/:id", a.producthandler.ShowOrderInfo)
+		api.GET("/order/:id/timeline", a.producthandler.GetOrderTimeline)
 		api.POST("/order/pay", a.producthandler.Pay)
+		api.POST("/order/pay/confirm", a.producthandler.ConfirmPayment)
+		api.POST("/webhooks/payments", a.producthandler.PaymentWebhook)
 		api.GET("/order/payment/status/:order_id", a.producthandler.CheckPaymentStatus)
 		api.PUT("/order/shipping", a.producthandler.UpdateShippingDetails)
+		api.GET("/artisan/orders", a.producthandler.ListArtisanOrders)
+		api.PUT("/artisan/orders/status", a.producthandler.BulkUpdateArtisanOrders)
+		api.GET("/artisan/orders/packing-slips", a.producthandler.GetPackingSlips)
+		api.POST("/returns", a.producthandler.RequestReturn)
+		api.GET("/returns", a.producthandler.ListReturns)
+		api.GET("/returns/:id", a.producthandler.GetReturn)
+		api.PUT("/returns/:id/review", a.producthandler.ReviewReturn)
+		api.PUT("/returns/:id/ship", a.producthandler.ShipReturn)
+		api.PUT("/returns/:id/receive", a.producthandler.ReceiveReturn)
+		api.PUT("/returns/:id/cancel", a.producthandler.CancelReturn)
 
 		api.POST("/category", a.producthandler.AddCategory)
+		api.GET("/category/:id", a.producthandler.GetCategory)
+		api.PUT("/category/:id", a.producthandler.UpdateCategory)
+		api.DELETE("/category/:id", a.producthandler.DeleteCategory)
+		api.GET("/categories", a.producthandler.ListCategories)
+		api.GET("/categories/tree", a.producthandler.GetCategoryTree)
+		api.POST("/category/:id/attributes", a.producthandler.SetCategoryAttribute)
+		api.GET("/category/:id/attributes", a.producthandler.ListCategoryAttributes)
+		api.DELETE("/category/:id/attributes/:key", a.producthandler.DeleteCategoryAttribute)
+
+		api.POST("/product/:id/images", a.producthandler.UploadProductImage)
+		api.GET("/product/:id/images", a.producthandler.ListProductImages)
+		api.PUT("/product/:id/images/order", a.producthandler.ReorderProductImages)
+		api.PUT("/product/:id/images/:image_id/primary", a.producthandler.SetPrimaryProductImage)
+		api.DELETE("/product/:id/images/:image_id", a.producthandler.DeleteProductImage)
+
+		api.POST("/admin/exchange-rates", a.producthandler.SetExchangeRate)
+		api.GET("/admin/exchange-rates", a.producthandler.ListExchangeRates)
+		api.DELETE("/admin/exchange-rates/:base/:quote", a.producthandler.DeleteExchangeRate)
 	}
 
 	return router.Run(a.cfg.ServerAddress)
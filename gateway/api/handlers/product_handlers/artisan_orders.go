@@ -0,0 +1,145 @@
+package producthandlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// ListArtisanOrders godoc
+// @Summary List an artisan's orders
+// @Description Retrieve the artisan's parts of orders: only their own lines, with the buyer's shipping address. Filter by the part's status and when the order was placed; sort by created_at, updated_at, status or payout, with a leading - for descending (newest first by default).
+// @Tags artisan orders
+// @Produce json
+// @Param artisan_id query string true "Artisan ID"
+// @Param status query string false "Comma-separated statuses of the artisan's part"
+// @Param from query string false "Placed at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Placed before, or on when a date (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "Sort key, e.g. -payout"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all orders when omitted)"
+// @Success 200 {object} genprotos.ListArtisanOrdersResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /artisan/orders [get]
+func (h *ProductHandlers) ListArtisanOrders(ctx *gin.Context) {
+	req := genprotos.ListArtisanOrdersRequest{
+		ArtisanId: ctx.Query("artisan_id"),
+		Statuses:  queryList(ctx, "status"),
+		From:      ctx.Query("from"),
+		To:        ctx.Query("to"),
+		Sort:      ctx.Query("sort"),
+	}
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListArtisanOrders(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// BulkUpdateArtisanOrders godoc
+// @Summary Move several orders at once
+// @Description Move several of the artisan's parts of orders to a status, for example processing or shipped. Each moves on its own; results tell which failed and why.
+// @Tags artisan orders
+// @Accept json
+// @Produce json
+// @Param orders body genprotos.BulkUpdateArtisanOrdersRequest true "Artisan, fulfillments and new status"
+// @Success 200 {object} genprotos.BulkUpdateArtisanOrdersResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /artisan/orders/status [put]
+func (h *ProductHandlers) BulkUpdateArtisanOrders(ctx *gin.Context) {
+	var req genprotos.BulkUpdateArtisanOrdersRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.BulkUpdateArtisanOrders(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetPackingSlips godoc
+// @Summary Print packing slips
+// @Description Retrieve a packing slip for each of the artisan's parts of orders. With format=text the slips come as plain text ready to print, one per page.
+// @Tags artisan orders
+// @Produce json
+// @Produce plain
+// @Param artisan_id query string true "Artisan ID"
+// @Param fulfillment_ids query string true "Comma-separated fulfillment IDs"
+// @Param format query string false "json (default) or text"
+// @Success 200 {object} genprotos.GetPackingSlipsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /artisan/orders/packing-slips [get]
+func (h *ProductHandlers) GetPackingSlips(ctx *gin.Context) {
+	req := genprotos.GetPackingSlipsRequest{
+		ArtisanId:      ctx.Query("artisan_id"),
+		FulfillmentIds: queryList(ctx, "fulfillment_ids"),
+	}
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "text" {
+		ctx.JSON(400, gin.H{"error": "Invalid format parameter"})
+		return
+	}
+
+	resp, err := h.client.GetPackingSlips(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	if format == "text" {
+		pages := make([]string, 0, len(resp.Slips))
+		for _, slip := range resp.Slips {
+			pages = append(pages, slip.Text)
+		}
+		ctx.String(200, strings.Join(pages, "\f"))
+		return
+	}
+	ctx.JSON(200, resp)
+}
+
+// queryList reads a query parameter given as a comma-separated list,
+// repeated, or both.
+func queryList(ctx *gin.Context, key string) []string {
+	var values []string
+	for _, value := range ctx.QueryArray(key) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,97 @@
+package producthandlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruziba3vich/armiya-gateway/money"
+	"github.com/ruziba3vich/armiya-gateway/redact"
+)
+
+// cardFields are the fields clients sent card details in before payments
+// were tokenized.
+var cardFields = []string{"card_number", "expiry_date", "cvv"}
+
+var errCardDetails = errors.New("card details are not accepted; send the payment_token the payment provider issued for the card")
+
+// bindMoneyJSON binds the request body like ShouldBindJSON, but first lets
+// older clients keep sending the listed money fields as plain decimals in the
+// default currency. It also accepts update_mask in its string form.
+func (h *ProductHandlers) bindMoneyJSON(ctx *gin.Context, req interface{}, fields ...string) error {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	body, err = money.UpgradeLegacyJSON(body, h.cfg.DefaultCurrency, fields...)
+	if err != nil {
+		return err
+	}
+
+	body, err = upgradeFieldMask(body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, req)
+}
+
+// upgradeFieldMask rewrites a top-level "update_mask": "name,price" into the
+// {"paths": [...]} shape the generated structs expect.
+func upgradeFieldMask(body []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body, nil
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body, nil
+	}
+
+	var mask string
+	if raw, ok := doc["update_mask"]; !ok || json.Unmarshal(raw, &mask) != nil {
+		return body, nil
+	}
+
+	paths := make([]string, 0)
+	for _, path := range strings.Split(mask, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	raw, err := json.Marshal(map[string][]string{"paths": paths})
+	if err != nil {
+		return nil, err
+	}
+	doc["update_mask"] = raw
+
+	return json.Marshal(doc)
+}
+
+// bindPaymentJSON binds a payment request, refusing any body that carries
+// card details instead of a token.
+func bindPaymentJSON(ctx *gin.Context, req interface{}) error {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+	for _, field := range cardFields {
+		if _, ok := doc[field]; ok {
+			return errCardDetails
+		}
+	}
+	if redact.ContainsPAN(string(body)) {
+		return errCardDetails
+	}
+
+	return json.Unmarshal(body, req)
+}
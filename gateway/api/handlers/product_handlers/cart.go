@@ -0,0 +1,238 @@
+package producthandlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// GetCart godoc
+// @Summary Get a cart
+// @Description Retrieve a signed-in buyer's cart (user_id) or an anonymous cart (cart_id), priced with current prices and flagged where stock is short or a price changed
+// @Tags cart
+// @Produce json
+// @Param user_id query string false "Buyer ID"
+// @Param cart_id query string false "Anonymous cart ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart [get]
+func (h *ProductHandlers) GetCart(ctx *gin.Context) {
+	req := genprotos.CartRequest{
+		UserId:   ctx.Query("user_id"),
+		CartId:   ctx.Query("cart_id"),
+		Currency: requestCurrency(ctx),
+	}
+
+	resp, err := h.client.GetCart(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// AddCartItem godoc
+// @Summary Add to cart
+// @Description Add a quantity of a product to a cart. Without user_id or cart_id a new anonymous cart is created; keep its id to merge it on login.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param item body genprotos.CartItemRequest true "Item"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/items [post]
+func (h *ProductHandlers) AddCartItem(ctx *gin.Context) {
+	var req genprotos.CartItemRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.AddCartItem(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// UpdateCartItem godoc
+// @Summary Change a cart item's quantity
+// @Description Set the quantity of a product already in the cart. A quantity of 0 removes it.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param product_id path string true "Product ID"
+// @Param item body genprotos.CartItemRequest true "Cart and new quantity"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/items/{product_id} [put]
+func (h *ProductHandlers) UpdateCartItem(ctx *gin.Context) {
+	var req genprotos.CartItemRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ProductId = ctx.Param("product_id")
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.UpdateCartItem(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// RemoveCartItem godoc
+// @Summary Remove from cart
+// @Description Remove a product from a cart
+// @Tags cart
+// @Produce json
+// @Param product_id path string true "Product ID"
+// @Param user_id query string false "Buyer ID"
+// @Param cart_id query string false "Anonymous cart ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/items/{product_id} [delete]
+func (h *ProductHandlers) RemoveCartItem(ctx *gin.Context) {
+	req := genprotos.RemoveCartItemRequest{
+		ProductId: ctx.Param("product_id"),
+		UserId:    ctx.Query("user_id"),
+		CartId:    ctx.Query("cart_id"),
+		Currency:  requestCurrency(ctx),
+	}
+
+	resp, err := h.client.RemoveCartItem(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ClearCart godoc
+// @Summary Clear a cart
+// @Description Remove every item from a cart
+// @Tags cart
+// @Produce json
+// @Param user_id query string false "Buyer ID"
+// @Param cart_id query string false "Anonymous cart ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart [delete]
+func (h *ProductHandlers) ClearCart(ctx *gin.Context) {
+	req := genprotos.CartRequest{
+		UserId:   ctx.Query("user_id"),
+		CartId:   ctx.Query("cart_id"),
+		Currency: requestCurrency(ctx),
+	}
+
+	resp, err := h.client.ClearCart(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// MergeCart godoc
+// @Summary Merge an anonymous cart on login
+// @Description Move an anonymous cart into the buyer's cart, adding up quantities of products in both
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param merge body genprotos.MergeCartRequest true "Anonymous cart and buyer"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/merge [post]
+func (h *ProductHandlers) MergeCart(ctx *gin.Context) {
+	var req genprotos.MergeCartRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.MergeCart(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// CheckoutCart godoc
+// @Summary Check out a cart
+// @Description Place an order for everything in the buyer's cart, with the cart's coupon codes, and empty it. Stock is held as for any other order.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param checkout body genprotos.CheckoutCartRequest true "Buyer and shipping address"
+// @Param Idempotency-Key header string false "Key that makes retries safe: a retry gets the first response back, and reusing the key with different input fails with 409"
+// @Param currency query string false "Order currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.OrderResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/checkout [post]
+func (h *ProductHandlers) CheckoutCart(ctx *gin.Context) {
+	var req genprotos.CheckoutCartRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.CheckoutCart(idempotentContext(ctx), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
@@ -0,0 +1,94 @@
+package producthandlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// requestCurrency picks the currency a client wants prices in, from the
+// currency query parameter or, failing that, the X-Currency header.
+func requestCurrency(ctx *gin.Context) string {
+	if currency := ctx.Query("currency"); currency != "" {
+		return strings.ToUpper(currency)
+	}
+	return strings.ToUpper(ctx.GetHeader("X-Currency"))
+}
+
+// SetExchangeRate godoc
+// @Summary Set an exchange rate
+// @Description Create or replace the rate for a currency pair (1 base = rate quote)
+// @Tags exchange rates
+// @Accept json
+// @Produce json
+// @Param rate body genprotos.SetExchangeRateRequest true "Exchange rate"
+// @Success 200 {object} genprotos.ExchangeRate
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /admin/exchange-rates [post]
+func (h *ProductHandlers) SetExchangeRate(ctx *gin.Context) {
+	var req genprotos.SetExchangeRateRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.SetExchangeRate(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates
+// @Description Retrieve the stored exchange rates, optionally for one base currency
+// @Tags exchange rates
+// @Produce json
+// @Param base query string false "Base currency"
+// @Success 200 {object} genprotos.ListExchangeRatesResponse
+// @Failure 500 {object} genprotos.Message
+// @Router /admin/exchange-rates [get]
+func (h *ProductHandlers) ListExchangeRates(ctx *gin.Context) {
+	var req genprotos.ListExchangeRatesRequest
+	req.BaseCurrency = ctx.Query("base")
+
+	resp, err := h.client.ListExchangeRates(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// DeleteExchangeRate godoc
+// @Summary Delete an exchange rate
+// @Description Remove the stored rate for a currency pair
+// @Tags exchange rates
+// @Produce json
+// @Param base path string true "Base currency"
+// @Param quote path string true "Quote currency"
+// @Success 200 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /admin/exchange-rates/{base}/{quote} [delete]
+func (h *ProductHandlers) DeleteExchangeRate(ctx *gin.Context) {
+	req := genprotos.DeleteExchangeRateRequest{
+		BaseCurrency:  ctx.Param("base"),
+		QuoteCurrency: ctx.Param("quote"),
+	}
+
+	resp, err := h.client.DeleteExchangeRate(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
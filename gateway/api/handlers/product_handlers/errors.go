@@ -0,0 +1,50 @@
+package producthandlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var httpStatuses = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// respondError translates a gRPC error from product-service into the matching
+// HTTP status. Anything without a recognised code stays a 500. Field
+// violations the service attached are passed on so clients can point at
+// each bad field.
+func (h *ProductHandlers) respondError(ctx *gin.Context, err error) {
+	st := status.Convert(err)
+	code, ok := httpStatuses[st.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+
+	body := gin.H{"error": st.Message()}
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		violations := make([]gin.H, 0, len(badRequest.FieldViolations))
+		for _, violation := range badRequest.FieldViolations {
+			violations = append(violations, gin.H{"field": violation.Field, "description": violation.Description})
+		}
+		body["violations"] = violations
+	}
+	ctx.JSON(code, body)
+}
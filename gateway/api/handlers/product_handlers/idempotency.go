@@ -0,0 +1,22 @@
+package producthandlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// idempotencyHeader makes a request safe to retry: product-service replays
+// the first response sent under the same key.
+const idempotencyHeader = "Idempotency-Key"
+
+// idempotentContext forwards the request's Idempotency-Key, if any, to
+// product-service as gRPC metadata.
+func idempotentContext(ctx *gin.Context) context.Context {
+	key := ctx.GetHeader(idempotencyHeader)
+	if key == "" {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "idempotency-key", key)
+}
@@ -0,0 +1,219 @@
+package producthandlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// ImportProducts godoc
+// @Summary Import products in bulk
+// @Description Queue a CSV or JSON Lines file of products for import. Rows are upserted by sku and processed in the background; poll the returned import for per-row errors. Send the file as the "file" form field or as the raw request body.
+// @Tags product import
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param artisan_id query string true "Artisan ID"
+// @Param format query string false "csv or jsonl (guessed from the file name or Content-Type when omitted)"
+// @Param dry_run query bool false "Validate without saving"
+// @Param actor_id query string false "User making the import (defaults to the artisan)"
+// @Param file formData file false "Import file"
+// @Success 202 {object} genprotos.ProductImport
+// @Failure 400 {object} genprotos.Message
+// @Failure 413 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/import [post]
+func (h *ProductHandlers) ImportProducts(ctx *gin.Context) {
+	req := genprotos.ImportProductsRequest{
+		ArtisanId: ctx.Query("artisan_id"),
+		Format:    ctx.Query("format"),
+		DryRun:    ctx.Query("dry_run") == "true",
+		ActorId:   ctx.Query("actor_id"),
+	}
+
+	var (
+		body     io.Reader = ctx.Request.Body
+		filename string
+	)
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		file, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body, filename = f, file.Filename
+	}
+	if req.Format == "" {
+		req.Format = importFormat(filename, ctx.ContentType())
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, h.cfg.MaxImportSize+1))
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if int64(len(data)) > h.cfg.MaxImportSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds the maximum import size"})
+		return
+	}
+	req.Data = data
+
+	resp, err := h.client.ImportProducts(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, resp)
+}
+
+// GetProductImport godoc
+// @Summary Get a product import
+// @Description Retrieve the progress of an import and the rows it could not apply
+// @Tags product import
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} genprotos.ProductImport
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/imports/{id} [get]
+func (h *ProductHandlers) GetProductImport(ctx *gin.Context) {
+	var req genprotos.GetProductImportRequest
+	req.Id = ctx.Param("id")
+
+	resp, err := h.client.GetProductImport(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListProductImports godoc
+// @Summary List product imports
+// @Description Retrieve an artisan's imports, newest first
+// @Tags product import
+// @Produce json
+// @Param artisan_id query string true "Artisan ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all imports when omitted)"
+// @Success 200 {object} genprotos.ListProductImportsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/imports [get]
+func (h *ProductHandlers) ListProductImports(ctx *gin.Context) {
+	var req genprotos.ListProductImportsRequest
+	req.ArtisanId = ctx.Query("artisan_id")
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListProductImports(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ExportProducts godoc
+// @Summary Export products
+// @Description Download an artisan's catalog as CSV or JSON Lines, in the same format imports accept
+// @Tags product import
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param artisan_id query string true "Artisan ID"
+// @Param format query string false "csv (default) or jsonl"
+// @Param status query string false "Only products with this status"
+// @Success 200 {file} file
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/export [get]
+func (h *ProductHandlers) ExportProducts(ctx *gin.Context) {
+	req := genprotos.ExportProductsRequest{
+		ArtisanId: ctx.Query("artisan_id"),
+		Format:    strings.ToLower(ctx.DefaultQuery("format", "csv")),
+		Status:    ctx.Query("status"),
+	}
+
+	stream, err := h.client.ExportProducts(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	// Errors before the first chunk can still become a proper status code.
+	chunk, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		h.respondError(ctx, err)
+		return
+	}
+
+	extension, contentType := "jsonl", "application/x-ndjson"
+	if req.Format == "csv" {
+		extension, contentType = "csv", "text/csv"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, extension))
+	ctx.Status(200)
+
+	for err == nil {
+		if _, err := ctx.Writer.Write(chunk.Data); err != nil {
+			h.logger.Printf("failed to write product export: %v", err)
+			return
+		}
+		chunk, err = stream.Recv()
+	}
+	if err != io.EOF {
+		h.logger.Printf("product export stopped early: %v", err)
+	}
+}
+
+// importFormat guesses the format of an upload from its file name or content
+// type. product-service rejects anything it cannot read.
+func importFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return "csv"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	}
+	switch contentType {
+	case "text/csv", "application/csv":
+		return "csv"
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return "jsonl"
+	}
+	return ""
+}
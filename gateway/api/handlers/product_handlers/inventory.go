@@ -0,0 +1,188 @@
+package producthandlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// ListStockMovements godoc
+// @Summary List stock movements
+// @Description Retrieve a product's inventory ledger, newest first, with its current stock and the stock the ledger adds up to
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param reason query string false "initial, sale, cancellation, return, adjustment, import or correction"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all movements when omitted)"
+// @Success 200 {object} genprotos.ListStockMovementsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/stock/movements [get]
+func (h *ProductHandlers) ListStockMovements(ctx *gin.Context) {
+	var req genprotos.ListStockMovementsRequest
+	req.ProductId = ctx.Param("id")
+	req.Reason = ctx.Query("reason")
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListStockMovements(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// AdjustStock godoc
+// @Summary Adjust stock
+// @Description Add to or remove from a product's stock, e.g. after a stock count or for damaged goods. Stock cannot go below zero.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param adjustment body genprotos.AdjustStockRequest true "Signed change, reason (adjustment or return) and note"
+// @Success 200 {object} genprotos.StockMovement
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/stock/adjust [post]
+func (h *ProductHandlers) AdjustStock(ctx *gin.Context) {
+	var req genprotos.AdjustStockRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ProductId = ctx.Param("id")
+
+	resp, err := h.client.AdjustStock(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// SetLowStockThreshold godoc
+// @Summary Set a low-stock threshold
+// @Description Raise a low-stock alert whenever the product's stock is at or below the threshold. 0 turns alerts off.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param threshold body genprotos.SetLowStockThresholdRequest true "Threshold"
+// @Success 200 {object} genprotos.StockLevel
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/stock/threshold [put]
+func (h *ProductHandlers) SetLowStockThreshold(ctx *gin.Context) {
+	var req genprotos.SetLowStockThresholdRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ProductId = ctx.Param("id")
+
+	resp, err := h.client.SetLowStockThreshold(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListLowStockAlerts godoc
+// @Summary List low-stock alerts
+// @Description Retrieve an artisan's low-stock alerts, newest first. Only open alerts are listed unless include_resolved is set.
+// @Tags inventory
+// @Produce json
+// @Param artisan_id query string true "Artisan ID"
+// @Param include_resolved query bool false "Include alerts for products that were restocked"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all alerts when omitted)"
+// @Success 200 {object} genprotos.ListLowStockAlertsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/low-stock [get]
+func (h *ProductHandlers) ListLowStockAlerts(ctx *gin.Context) {
+	var req genprotos.ListLowStockAlertsRequest
+	req.ArtisanId = ctx.Query("artisan_id")
+	req.IncludeResolved = ctx.Query("include_resolved") == "true"
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListLowStockAlerts(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ReconcileStock godoc
+// @Summary Reconcile stock with the ledger
+// @Description List products whose stock no longer matches their inventory ledger. With repair set, a correction movement brings each ledger in line with the stock.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param reconcile body genprotos.ReconcileStockRequest true "Artisan or product to check"
+// @Success 200 {object} genprotos.ReconcileStockResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/stock/reconcile [post]
+func (h *ProductHandlers) ReconcileStock(ctx *gin.Context) {
+	var req genprotos.ReconcileStockRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.ReconcileStock(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
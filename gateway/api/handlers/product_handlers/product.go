@@ -2,11 +2,17 @@ package producthandlers
 
 import (
 	"context"
+	"io"
 	"log"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/k0kubun/pp"
+	"github.com/ruziba3vich/armiya-gateway/config"
 	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+	"github.com/ruziba3vich/armiya-gateway/redact"
+	"github.com/ruziba3vich/armiya-gateway/webhook"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,12 +20,17 @@ import (
 type ProductHandlers struct {
 	client genprotos.ProductServiceClient
 	logger *log.Logger
+	cfg    *config.Config
+	// webhookEvents are the payment events handled lately.
+	webhookEvents *webhook.Seen
 }
 
-func NewProductHandlers(client genprotos.ProductServiceClient, logger *log.Logger) *ProductHandlers {
+func NewProductHandlers(client genprotos.ProductServiceClient, logger *log.Logger, cfg *config.Config) *ProductHandlers {
 	return &ProductHandlers{
-		client: client,
-		logger: logger,
+		client:        client,
+		logger:        logger,
+		cfg:           cfg,
+		webhookEvents: webhook.NewSeen(recentWebhookEvents),
 	}
 }
 
@@ -37,14 +48,14 @@ func NewProductHandlers(client genprotos.ProductServiceClient, logger *log.Logge
 func (h *ProductHandlers) AddProduct(ctx *gin.Context) {
 	var req genprotos.AddProductRequest
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := h.bindMoneyJSON(ctx, &req, "price"); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
 	resp, err := h.client.AddProduct(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -53,26 +64,37 @@ func (h *ProductHandlers) AddProduct(ctx *gin.Context) {
 
 // EditProduct godoc
 // @Summary Edit an existing product
-// @Description Edit the details of an existing product
+// @Description Update the fields listed in update_mask ("name,price" or {"paths": [...]}). The version you read must be sent, in the body or an If-Match header; the edit is rejected with 409 if someone else changed the product since.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param product body genprotos.EditProductRequest true "Product"
+// @Param If-Match header string false "Product version the edit is based on"
 // @Success 200 {object} genprotos.EditProductResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /product/edit [put]
 func (h *ProductHandlers) EditProduct(ctx *gin.Context) {
 	var req genprotos.EditProductRequest
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := h.bindMoneyJSON(ctx, &req, "price"); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	if ifMatch := strings.Trim(ctx.GetHeader("If-Match"), `" `); req.Version == 0 && ifMatch != "" {
+		version, err := strconv.ParseInt(ifMatch, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		req.Version = version
+	}
 
 	resp, err := h.client.EditProduct(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -81,7 +103,7 @@ func (h *ProductHandlers) EditProduct(ctx *gin.Context) {
 
 // DeleteProduct godoc
 // @Summary Delete a product
-// @Description Delete a product from the catalog
+// @Description Delete a product from the catalog. Products with order history are archived instead.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -115,6 +137,9 @@ func (h *ProductHandlers) DeleteProduct(ctx *gin.Context) {
 // @Produce json
 // @Param page query int true "Page number"
 // @Param limit query int true "Page size"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Param status query string false "Product status (default published); draft and archived need artisan_id"
+// @Param artisan_id query string false "Only this artisan's products"
 // @Success 200 {object} genprotos.GetAllProductsResponse
 // @Failure 400 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
@@ -134,10 +159,13 @@ func (h *ProductHandlers) GetAllProducts(ctx *gin.Context) {
 	}
 	req.Page = page
 	req.Limit = limit
+	req.DisplayCurrency = requestCurrency(ctx)
+	req.Status = ctx.Query("status")
+	req.ArtisanId = ctx.Query("artisan_id")
 
 	resp, err := h.client.GetAllProducts(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -151,16 +179,127 @@ func (h *ProductHandlers) GetAllProducts(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Param artisan_id query string false "The product's artisan, needed while it is a draft or archived (a filter, not an access check)"
 // @Success 200 {object} genprotos.GetProductResponse
+// @Failure 404 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /products/{id} [get]
 func (h *ProductHandlers) GetProduct(ctx *gin.Context) {
 	var req genprotos.GetProductRequest
 	req.Id = ctx.Param("id")
+	req.DisplayCurrency = requestCurrency(ctx)
+	req.ArtisanId = ctx.Query("artisan_id")
 
 	resp, err := h.client.GetProduct(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ChangeProductStatus godoc
+// @Summary Change a product's lifecycle status
+// @Description Move a product between draft, published, out_of_stock and archived. Publishing with a future publish_at schedules it.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param status body genprotos.ChangeProductStatusRequest true "New status"
+// @Success 200 {object} genprotos.ProductStatusResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/status [put]
+func (h *ProductHandlers) ChangeProductStatus(ctx *gin.Context) {
+	var req genprotos.ChangeProductStatusRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.Id = ctx.Param("id")
+
+	resp, err := h.client.ChangeProductStatus(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListProductRevisions godoc
+// @Summary List product revisions
+// @Description Retrieve the change history of a product, newest first
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all revisions when omitted)"
+// @Success 200 {object} genprotos.ListProductRevisionsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/revisions [get]
+func (h *ProductHandlers) ListProductRevisions(ctx *gin.Context) {
+	var req genprotos.ListProductRevisionsRequest
+	req.ProductId = ctx.Param("id")
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListProductRevisions(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// RevertProduct godoc
+// @Summary Revert a product to an earlier revision
+// @Description Restore name, description, price, category, tags and attributes from an earlier version. The revert is recorded as a new revision.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param revert body genprotos.RevertProductRequest true "Version to restore"
+// @Success 200 {object} genprotos.ProductRevision
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/revert [post]
+func (h *ProductHandlers) RevertProduct(ctx *gin.Context) {
+	var req genprotos.RevertProductRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ProductId = ctx.Param("id")
+
+	resp, err := h.client.RevertProduct(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -174,6 +313,7 @@ func (h *ProductHandlers) GetProduct(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param product body genprotos.SearchAndFilterRequest true "Details"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
 // @Success 200 {object} genprotos.SearchAndFilterResponse
 // @Failure 400 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
@@ -181,16 +321,20 @@ func (h *ProductHandlers) GetProduct(ctx *gin.Context) {
 func (h *ProductHandlers) SearchAndFilterProduct(ctx *gin.Context) {
 	var req genprotos.SearchAndFilterRequest
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := h.bindMoneyJSON(ctx, &req, "min_price", "max_price"); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	pp.Println(req)
+	if req.DisplayCurrency == "" {
+		req.DisplayCurrency = requestCurrency(ctx)
+	}
+
+	pp.Println(redact.Message(&req))
 
 	resp, err := h.client.SearchAndFilterProduct(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -250,13 +394,17 @@ func (h *ProductHandlers) GetAllRatings(ctx *gin.Context) {
 
 // OrderProduct godoc
 // @Summary Order a product
-// @Description Place an order for a product
+// @Description Place an order for a product. The stock is held for the order until reserved_until; unpaid orders are cancelled after that. Running promotions and any coupon_codes are applied and listed as discounts. An order that cannot be placed as a whole (missing address, unknown, unpublished or short products, unusable codes) fails with 400 and nothing is written; violations lists every problem by field. The order is split into one fulfillment per artisan, each with its own status, shipping details and payout.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param order body genprotos.OrderRequest true "Order"
+// @Param Idempotency-Key header string false "Key that makes retries safe: a retry gets the first response back, and reusing the key with different input fails with 409"
+// @Param currency query string false "Order currency when not set in the body (or X-Currency header)"
 // @Success 200 {object} genprotos.OrderResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /order [post]
 func (h *ProductHandlers) OrderProduct(ctx *gin.Context) {
@@ -266,10 +414,13 @@ func (h *ProductHandlers) OrderProduct(ctx *gin.Context) {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
 
-	resp, err := h.client.OrderProduct(context.Background(), &req)
+	resp, err := h.client.OrderProduct(idempotentContext(ctx), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -278,13 +429,15 @@ func (h *ProductHandlers) OrderProduct(ctx *gin.Context) {
 
 // CancelOrder godoc
 // @Summary Cancel an order
-// @Description Cancel a placed order
+// @Description Cancel an order, or with fulfillment_id one artisan's part of it, that has not shipped yet. Its stock goes back and what was paid for it is refunded; once the whole order is cancelled its promotion uses are released. role defaults to buyer; actor_id must be the buyer, the artisan of the part, or an admin. status is the order's status afterwards.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param order body genprotos.CancelOrderRequest true "Cancel Order"
 // @Success 200 {object} genprotos.CancelOrderResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /order/cancel [put]
 func (h *ProductHandlers) CancelOrder(ctx *gin.Context) {
@@ -297,7 +450,7 @@ func (h *ProductHandlers) CancelOrder(ctx *gin.Context) {
 
 	resp, err := h.client.CancelOrder(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -306,13 +459,15 @@ func (h *ProductHandlers) CancelOrder(ctx *gin.Context) {
 
 // ChangeOrderStatus godoc
 // @Summary Change order status
-// @Description Update the status of an order
+// @Description Move an order along pending -> paid -> processing -> shipped -> delivered -> completed, or delivered -> returned -> refunded; pending, paid and processing orders can be cancelled. Artisans and admins move orders forward, buyers complete or cancel their own. Illegal moves fail with 400, moves the role may not make with 403. With fulfillment_id only that artisan's part moves; without it every part that is not cancelled does, which artisans may only do for their own parts. The order's status follows from its parts: cancelled parts are left out and it is as far along as its least advanced part. Cancelling or returning puts the stock back; cancelling or refunding refunds what the buyer paid for the part, less what returns already gave back. The buyer is notified of every change.
 // @Tags orders
 // @Accept json
 // @Produce json
-// @Param status body genprotos.ChangeOrderStatusRequest true "Order Status"
+// @Param status body genprotos.ChangeOrderStatusRequest true "Order, new status and who is asking"
 // @Success 200 {object} genprotos.ChangeOrderStatusResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /order/status [put]
 func (h *ProductHandlers) ChangeOrderStatus(ctx *gin.Context) {
@@ -325,7 +480,7 @@ func (h *ProductHandlers) ChangeOrderStatus(ctx *gin.Context) {
 
 	resp, err := h.client.ChangeOrderStatus(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -369,9 +524,53 @@ func (h *ProductHandlers) GetAllOrders(ctx *gin.Context) {
 	ctx.JSON(200, resp)
 }
 
+// ListMyOrders godoc
+// @Summary List a buyer's orders
+// @Description Retrieve a buyer's order history, newest first. Lines keep the product's name, image and price at purchase, so they still show after the product changes or is archived. Pass next_cursor back as cursor for the next page.
+// @Tags orders
+// @Produce json
+// @Param user_id query string true "Buyer ID"
+// @Param status query string false "Comma-separated order statuses"
+// @Param from query string false "Placed at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Placed before, or on when a date (RFC 3339 or YYYY-MM-DD)"
+// @Param q query string false "Search by product name"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size (20 by default, at most 100)"
+// @Success 200 {object} genprotos.ListMyOrdersResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /order/my [get]
+func (h *ProductHandlers) ListMyOrders(ctx *gin.Context) {
+	req := genprotos.ListMyOrdersRequest{
+		UserId:   ctx.Query("user_id"),
+		Statuses: queryList(ctx, "status"),
+		From:     ctx.Query("from"),
+		To:       ctx.Query("to"),
+		Query:    ctx.Query("q"),
+		Cursor:   ctx.Query("cursor"),
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListMyOrders(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
 // ShowOrderInfo godoc
 // @Summary Show order information
-// @Description Retrieve information for a specific order
+// @Description Retrieve information for a specific order, including its fulfillments and timeline
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -392,28 +591,85 @@ func (h *ProductHandlers) ShowOrderInfo(ctx *gin.Context) {
 	ctx.JSON(200, resp)
 }
 
+// GetOrderTimeline godoc
+// @Summary Show an order's timeline
+// @Description Retrieve everything that happened to an order, oldest first: when it was placed, each status change, payments and shipping updates, with who did it and their notes
+// @Tags orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} genprotos.GetOrderTimelineResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /order/{id}/timeline [get]
+func (h *ProductHandlers) GetOrderTimeline(ctx *gin.Context) {
+	req := genprotos.GetOrderTimelineRequest{OrderId: ctx.Param("id")}
+
+	resp, err := h.client.GetOrderTimeline(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
 // Pay godoc
 // @Summary Pay for an order
-// @Description Make a payment for a specific order
+// @Description Charge a card for a pending order through the payment provider. The order is paid once the payment is captured (status paid). A declined card comes back with status declined and failure_reason, and the order can be paid again. Status requires_action means the buyer must complete 3-D Secure at challenge_url and then call /order/pay/confirm. Card details are never accepted: the client sends the card to the payment provider (POST /v1/tokens on the mock provider at PAYMENT_MOCK_ADDR, or on the local paystub) and pays with the payment_token it returns; bodies carrying card numbers are refused with 400. Fails with 400 once the order is paid, cancelled or its stock hold has expired, and with 503 when the provider does not answer in time; the payment is then pending and is finished with /order/pay/confirm. The mock provider also accepts these test tokens: tok_visa authorized, tok_declined declined, tok_insufficient_funds insufficient funds, tok_3ds 3-D Secure, tok_3ds_fails failed 3-D Secure, tok_timeout timeout.
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param payment body genprotos.PayRequest true "Payment"
+// @Param Idempotency-Key header string false "Key that makes retries safe: a retry gets the first response back, and reusing the key with different input fails with 409"
 // @Success 200 {object} genprotos.PayResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
+// @Failure 503 {object} genprotos.Message
 // @Router /order/pay [post]
 func (h *ProductHandlers) Pay(ctx *gin.Context) {
 	var req genprotos.PayRequest
 
+	if err := bindPaymentJSON(ctx, &req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.Pay(idempotentContext(ctx), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ConfirmPayment godoc
+// @Summary Confirm a payment
+// @Description Finish a payment left open: a pending payment the provider did not answer for is sent again under the same idempotency key, after the buyer completed 3-D Secure the provider's answer is taken, and an authorized payment is captured, which marks the order paid. Payments that are no longer open are returned as they are.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param payment body genprotos.ConfirmPaymentRequest true "Payment"
+// @Success 200 {object} genprotos.PayResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Failure 503 {object} genprotos.Message
+// @Router /order/pay/confirm [post]
+func (h *ProductHandlers) ConfirmPayment(ctx *gin.Context) {
+	var req genprotos.ConfirmPaymentRequest
+
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	resp, err := h.client.Pay(context.Background(), &req)
+	resp, err := h.client.ConfirmPayment(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -422,13 +678,14 @@ func (h *ProductHandlers) Pay(ctx *gin.Context) {
 
 // CheckPaymentStatus godoc
 // @Summary Check payment status
-// @Description Check the status of a payment
+// @Description Check the latest payment attempt of an order: requires_action, authorized, declined, failed, voided, paid, partially_refunded or refunded, with how much went back to the buyer and why a payment did not go through
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param order_id path string true "Order ID"
 // @Success 200 {object} genprotos.CheckPaymentStatusResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /order/payment/status/{order_id} [get]
 func (h *ProductHandlers) CheckPaymentStatus(ctx *gin.Context) {
@@ -437,7 +694,7 @@ func (h *ProductHandlers) CheckPaymentStatus(ctx *gin.Context) {
 	req.OrderId = id
 	resp, err := h.client.CheckPaymentStatus(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -446,13 +703,14 @@ func (h *ProductHandlers) CheckPaymentStatus(ctx *gin.Context) {
 
 // UpdateShippingDetails godoc
 // @Summary Update shipping details
-// @Description Update the shipping details for an order
+// @Description Update the shipping details of one artisan's part of an order. fulfillment_id is required when the order has more than one. The update goes on the order's timeline with actor_id and note.
 // @Tags shipping
 // @Accept json
 // @Produce json
 // @Param shipping body genprotos.UpdateShippingDetailsRequest true "Shipping Details"
 // @Success 200 {object} genprotos.UpdateShippingDetailsResponse
 // @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
 // @Failure 500 {object} genprotos.Message
 // @Router /order/shipping [put]
 func (h *ProductHandlers) UpdateShippingDetails(ctx *gin.Context) {
@@ -465,7 +723,7 @@ func (h *ProductHandlers) UpdateShippingDetails(ctx *gin.Context) {
 
 	resp, err := h.client.UpdateShippingDetails(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
 		return
 	}
 
@@ -493,7 +751,376 @@ func (h *ProductHandlers) AddCategory(ctx *gin.Context) {
 
 	resp, err := h.client.AddCategory(context.Background(), &req)
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetCategory godoc
+// @Summary Get a category
+// @Description Retrieve a category by its ID or slug
+// @Tags category
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Success 200 {object} genprotos.Category
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /category/{id} [get]
+func (h *ProductHandlers) GetCategory(ctx *gin.Context) {
+	var req genprotos.GetCategoryRequest
+	req.Id = ctx.Param("id")
+
+	resp, err := h.client.GetCategory(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description List the direct children of a category, or the top-level categories
+// @Tags category
+// @Produce json
+// @Param parent_id query string false "Parent category ID or slug"
+// @Success 200 {object} genprotos.ListCategoriesResponse
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /categories [get]
+func (h *ProductHandlers) ListCategories(ctx *gin.Context) {
+	var req genprotos.ListCategoriesRequest
+	req.ParentId = ctx.Query("parent_id")
+
+	resp, err := h.client.ListCategories(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description Retrieve the nested category tree, optionally starting at one category
+// @Tags category
+// @Produce json
+// @Param root_id query string false "Root category ID or slug"
+// @Success 200 {object} genprotos.GetCategoryTreeResponse
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /categories/tree [get]
+func (h *ProductHandlers) GetCategoryTree(ctx *gin.Context) {
+	var req genprotos.GetCategoryTreeRequest
+	req.RootId = ctx.Query("root_id")
+
+	resp, err := h.client.GetCategoryTree(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// UpdateCategory godoc
+// @Summary Update a category
+// @Description Update category fields. Omitted fields are left unchanged; setting parent_id moves the whole subtree ("" moves it to the top level).
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Param category body genprotos.UpdateCategoryRequest true "Fields to change"
+// @Success 200 {object} genprotos.Category
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /category/{id} [put]
+func (h *ProductHandlers) UpdateCategory(ctx *gin.Context) {
+	var req genprotos.UpdateCategoryRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.Id = ctx.Param("id")
+
+	resp, err := h.client.UpdateCategory(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Delete a category. Its children move up to its parent; its products must be reassigned.
+// @Tags category
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Param reassign_products_to query string false "Category that receives the deleted category's products"
+// @Success 200 {object} genprotos.Message
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /category/{id} [delete]
+func (h *ProductHandlers) DeleteCategory(ctx *gin.Context) {
+	req := genprotos.DeleteCategoryRequest{
+		Id:                 ctx.Param("id"),
+		ReassignProductsTo: ctx.Query("reassign_products_to"),
+	}
+
+	resp, err := h.client.DeleteCategory(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// UploadProductImage godoc
+// @Summary Upload a product image
+// @Description Upload an image for a product. Thumbnails are generated server-side.
+// @Tags product images
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param image formData file true "Image file (jpeg, png or webp)"
+// @Param primary formData bool false "Make this the primary image"
+// @Success 200 {object} genprotos.ProductImage
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 413 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/images [post]
+func (h *ProductHandlers) UploadProductImage(ctx *gin.Context) {
+	file, err := ctx.FormFile("image")
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if file.Size > h.cfg.MaxUploadSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "image exceeds the maximum upload size"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := genprotos.UploadProductImageRequest{
+		ProductId:   ctx.Param("id"),
+		Filename:    file.Filename,
+		ContentType: file.Header.Get("Content-Type"),
+		Data:        data,
+		MakePrimary: ctx.PostForm("primary") == "true",
+	}
+
+	resp, err := h.client.UploadProductImage(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListProductImages godoc
+// @Summary List product images
+// @Description Retrieve all images of a product in display order
+// @Tags product images
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} genprotos.ListProductImagesResponse
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/images [get]
+func (h *ProductHandlers) ListProductImages(ctx *gin.Context) {
+	var req genprotos.ListProductImagesRequest
+	req.ProductId = ctx.Param("id")
+
+	resp, err := h.client.ListProductImages(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ReorderProductImages godoc
+// @Summary Reorder product images
+// @Description Set the display order of all images of a product
+// @Tags product images
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param order body genprotos.ReorderProductImagesRequest true "Image IDs in the new order"
+// @Success 200 {object} genprotos.ListProductImagesResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/images/order [put]
+func (h *ProductHandlers) ReorderProductImages(ctx *gin.Context) {
+	var req genprotos.ReorderProductImagesRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ProductId = ctx.Param("id")
+
+	resp, err := h.client.ReorderProductImages(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// SetPrimaryProductImage godoc
+// @Summary Set the primary product image
+// @Description Mark an image as the one shown in listings
+// @Tags product images
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} genprotos.ProductImage
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/images/{image_id}/primary [put]
+func (h *ProductHandlers) SetPrimaryProductImage(ctx *gin.Context) {
+	req := genprotos.SetPrimaryProductImageRequest{
+		ProductId: ctx.Param("id"),
+		ImageId:   ctx.Param("image_id"),
+	}
+
+	resp, err := h.client.SetPrimaryProductImage(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// DeleteProductImage godoc
+// @Summary Delete a product image
+// @Description Delete an image and its thumbnails
+// @Tags product images
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /product/{id}/images/{image_id} [delete]
+func (h *ProductHandlers) DeleteProductImage(ctx *gin.Context) {
+	req := genprotos.DeleteProductImageRequest{
+		ProductId: ctx.Param("id"),
+		ImageId:   ctx.Param("image_id"),
+	}
+
+	resp, err := h.client.DeleteProductImage(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// SetCategoryAttribute godoc
+// @Summary Define a category attribute
+// @Description Create or replace a typed attribute (string, number, boolean or enum) that products in the category and its subcategories may carry
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Param attribute body genprotos.AttributeDefinition true "Attribute definition"
+// @Success 200 {object} genprotos.AttributeDefinition
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /category/{id}/attributes [post]
+func (h *ProductHandlers) SetCategoryAttribute(ctx *gin.Context) {
+	var req genprotos.AttributeDefinition
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.CategoryId = ctx.Param("id")
+
+	resp, err := h.client.SetCategoryAttribute(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListCategoryAttributes godoc
+// @Summary List category attributes
+// @Description Retrieve the attribute schema of a category, including attributes inherited from its parents
+// @Tags category
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Success 200 {object} genprotos.ListCategoryAttributesResponse
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /category/{id}/attributes [get]
+func (h *ProductHandlers) ListCategoryAttributes(ctx *gin.Context) {
+	var req genprotos.ListCategoryAttributesRequest
+	req.CategoryId = ctx.Param("id")
+
+	resp, err := h.client.ListCategoryAttributes(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// DeleteCategoryAttribute godoc
+// @Summary Delete a category attribute
+// @Description Remove an attribute definition from a category
+// @Tags category
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Param key path string true "Attribute key"
+// @Success 200 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /category/{id}/attributes/{key} [delete]
+func (h *ProductHandlers) DeleteCategoryAttribute(ctx *gin.Context) {
+	req := genprotos.DeleteCategoryAttributeRequest{
+		CategoryId: ctx.Param("id"),
+		Key:        ctx.Param("key"),
+	}
+
+	resp, err := h.client.DeleteCategoryAttribute(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
 		return
 	}
 
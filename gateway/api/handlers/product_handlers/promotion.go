@@ -0,0 +1,180 @@
+package producthandlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Description Create a discount rule: percentage, fixed, free_shipping or buy_x_get_y. Without a code it applies to every qualifying order; with one it is a coupon. Limit it to an artisan or category (with subcategories), a minimum subtotal, a validity window and usage limits (0 is unlimited). Stackable promotions combine; otherwise the order gets whichever gives the most off.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param promotion body genprotos.CreatePromotionRequest true "Promotion"
+// @Success 200 {object} genprotos.Promotion
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /promotions [post]
+func (h *ProductHandlers) CreatePromotion(ctx *gin.Context) {
+	var req genprotos.CreatePromotionRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.CreatePromotion(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListPromotions godoc
+// @Summary List promotions
+// @Description Retrieve promotions, newest first, with how often each was used
+// @Tags promotions
+// @Produce json
+// @Param artisan_id query string false "Only promotions of this artisan"
+// @Param active_only query bool false "Only promotions running now"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all promotions when omitted)"
+// @Success 200 {object} genprotos.ListPromotionsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /promotions [get]
+func (h *ProductHandlers) ListPromotions(ctx *gin.Context) {
+	var req genprotos.ListPromotionsRequest
+	req.ArtisanId = ctx.Query("artisan_id")
+	req.ActiveOnly = ctx.Query("active_only") == "true"
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListPromotions(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// SetPromotionActive godoc
+// @Summary Switch a promotion on or off
+// @Description Pause or resume a promotion. Orders already placed keep their discounts.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param active body genprotos.SetPromotionActiveRequest true "Whether the promotion is active"
+// @Success 200 {object} genprotos.Promotion
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /promotions/{id}/active [put]
+func (h *ProductHandlers) SetPromotionActive(ctx *gin.Context) {
+	var req genprotos.SetPromotionActiveRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.Id = ctx.Param("id")
+
+	resp, err := h.client.SetPromotionActive(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ApplyCartCoupon godoc
+// @Summary Enter a coupon code
+// @Description Add a coupon code to a cart. The code must be valid now; coupon_warnings on the cart explain when it does not take anything off the current items.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param coupon body genprotos.CartCouponRequest true "Cart and code"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/coupons [post]
+func (h *ProductHandlers) ApplyCartCoupon(ctx *gin.Context) {
+	var req genprotos.CartCouponRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.ApplyCartCoupon(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// RemoveCartCoupon godoc
+// @Summary Remove a coupon code
+// @Description Take a coupon code off a cart
+// @Tags cart
+// @Produce json
+// @Param code path string true "Coupon code"
+// @Param user_id query string false "Buyer ID"
+// @Param cart_id query string false "Anonymous cart ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /cart/coupons/{code} [delete]
+func (h *ProductHandlers) RemoveCartCoupon(ctx *gin.Context) {
+	req := genprotos.CartCouponRequest{
+		Code:     ctx.Param("code"),
+		UserId:   ctx.Query("user_id"),
+		CartId:   ctx.Query("cart_id"),
+		Currency: requestCurrency(ctx),
+	}
+
+	resp, err := h.client.RemoveCartCoupon(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
@@ -0,0 +1,290 @@
+package producthandlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// RequestReturn godoc
+// @Summary Request a return
+// @Description Ask to send back items of a delivered order, all sold by the same artisan. Each item is an order line id and quantity written as line_id:quantity; give several comma-separated or repeat the field. Up to 5 photos may be attached.
+// @Tags returns
+// @Accept multipart/form-data
+// @Produce json
+// @Param order_id formData string true "Order ID"
+// @Param user_id formData string true "Buyer ID"
+// @Param reason formData string true "Why the items go back"
+// @Param items formData string true "Order lines to return, e.g. 3f1c...:1"
+// @Param photos formData file false "Photos of the items (jpeg, png or webp)"
+// @Success 200 {object} genprotos.Return
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 413 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns [post]
+func (h *ProductHandlers) RequestReturn(ctx *gin.Context) {
+	req := genprotos.RequestReturnRequest{
+		OrderId: ctx.PostForm("order_id"),
+		UserId:  ctx.PostForm("user_id"),
+		Reason:  ctx.PostForm("reason"),
+	}
+
+	for _, value := range ctx.PostFormArray("items") {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			id, quantity, _ := strings.Cut(part, ":")
+			line := genprotos.ReturnLine{OrderItemId: strings.TrimSpace(id), Quantity: 1}
+			if quantity != "" {
+				value, err := strconv.ParseUint(strings.TrimSpace(quantity), 10, 64)
+				if err != nil {
+					ctx.JSON(400, gin.H{"error": fmt.Sprintf("Invalid quantity in item %q", part)})
+					return
+				}
+				line.Quantity = value
+			}
+			req.Items = append(req.Items, &line)
+		}
+	}
+
+	if form, err := ctx.MultipartForm(); err == nil {
+		for _, file := range form.File["photos"] {
+			if file.Size > h.cfg.MaxUploadSize {
+				ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "photo exceeds the maximum upload size"})
+				return
+			}
+			f, err := file.Open()
+			if err != nil {
+				ctx.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				ctx.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			req.Photos = append(req.Photos, &genprotos.ReturnPhoto{
+				Data:        data,
+				ContentType: file.Header.Get("Content-Type"),
+			})
+		}
+	}
+
+	resp, err := h.client.RequestReturn(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetReturn godoc
+// @Summary Get a return
+// @Description Retrieve a return with its items, photos and refund
+// @Tags returns
+// @Produce json
+// @Param id path string true "Return ID"
+// @Success 200 {object} genprotos.Return
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns/{id} [get]
+func (h *ProductHandlers) GetReturn(ctx *gin.Context) {
+	req := genprotos.GetReturnRequest{Id: ctx.Param("id")}
+
+	resp, err := h.client.GetReturn(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListReturns godoc
+// @Summary List returns
+// @Description Retrieve returns, newest first, filtered by buyer, artisan, order or status
+// @Tags returns
+// @Produce json
+// @Param user_id query string false "Buyer ID"
+// @Param artisan_id query string false "Artisan ID"
+// @Param order_id query string false "Order ID"
+// @Param status query string false "Comma-separated return statuses"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all returns when omitted)"
+// @Success 200 {object} genprotos.ListReturnsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns [get]
+func (h *ProductHandlers) ListReturns(ctx *gin.Context) {
+	req := genprotos.ListReturnsRequest{
+		UserId:    ctx.Query("user_id"),
+		ArtisanId: ctx.Query("artisan_id"),
+		OrderId:   ctx.Query("order_id"),
+		Statuses:  queryList(ctx, "status"),
+	}
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListReturns(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ReviewReturn godoc
+// @Summary Approve or reject a return
+// @Description The artisan, or an admin, approves a requested return or rejects it with a note for the buyer
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path string true "Return ID"
+// @Param review body genprotos.ReviewReturnRequest true "Actor, decision and note"
+// @Success 200 {object} genprotos.Return
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns/{id}/review [put]
+func (h *ProductHandlers) ReviewReturn(ctx *gin.Context) {
+	var req genprotos.ReviewReturnRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ReturnId = ctx.Param("id")
+
+	resp, err := h.client.ReviewReturn(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ShipReturn godoc
+// @Summary Ship a return back
+// @Description The buyer says an approved return is on its way back, with its tracking details
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path string true "Return ID"
+// @Param shipping body genprotos.ShipReturnRequest true "Buyer and tracking details"
+// @Success 200 {object} genprotos.Return
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns/{id}/ship [put]
+func (h *ProductHandlers) ShipReturn(ctx *gin.Context) {
+	var req genprotos.ShipReturnRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ReturnId = ctx.Param("id")
+
+	resp, err := h.client.ShipReturn(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ReceiveReturn godoc
+// @Summary Receive a return
+// @Description The artisan, or an admin, records that the items came back. They go back in stock and the buyer is refunded what they are worth, or refund_amount (minor units) when less is given back. The return is closed as refunded even when refund_amount is 0. The order's status follows once everything was returned.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path string true "Return ID"
+// @Param receipt body genprotos.ReceiveReturnRequest true "Actor, refund and note"
+// @Success 200 {object} genprotos.Return
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns/{id}/receive [put]
+func (h *ProductHandlers) ReceiveReturn(ctx *gin.Context) {
+	var req genprotos.ReceiveReturnRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ReturnId = ctx.Param("id")
+
+	resp, err := h.client.ReceiveReturn(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// CancelReturn godoc
+// @Summary Cancel a return
+// @Description The buyer withdraws a return that was not shipped yet
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path string true "Return ID"
+// @Param cancel body genprotos.CancelReturnRequest true "Buyer and note"
+// @Success 200 {object} genprotos.Return
+// @Failure 400 {object} genprotos.Message
+// @Failure 403 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /returns/{id}/cancel [put]
+func (h *ProductHandlers) CancelReturn(ctx *gin.Context) {
+	var req genprotos.CancelReturnRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.ReturnId = ctx.Param("id")
+
+	resp, err := h.client.CancelReturn(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
@@ -0,0 +1,86 @@
+package producthandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+	"github.com/ruziba3vich/armiya-gateway/webhook"
+)
+
+const (
+	// recentWebhookEvents is how many event ids are remembered to answer
+	// redeliveries from memory.
+	recentWebhookEvents = 10000
+	maxWebhookSize      = 1 << 20
+)
+
+// PaymentWebhook godoc
+// @Summary Receive a payment provider event
+// @Description The payment provider reports captures, refunds, voids, declines and chargebacks here. The Payment-Signature header must hold "t=<unix seconds>,v1=<hex HMAC-SHA256 of t.body>" made with the webhook secret within the allowed tolerance of now. Each event is applied once; a redelivered event is answered with duplicate set.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param Payment-Signature header string true "Signature of the event"
+// @Param event body webhook.Event true "Event"
+// @Success 200 {object} genprotos.PaymentEventResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 401 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 413 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Failure 503 {object} genprotos.Message
+// @Router /webhooks/payments [post]
+func (h *ProductHandlers) PaymentWebhook(ctx *gin.Context) {
+	if h.cfg.PaymentWebhookSecret == "" {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "payment webhooks are not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookSize+1))
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if len(body) > maxWebhookSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "event exceeds the maximum size"})
+		return
+	}
+
+	err = webhook.Verify(h.cfg.PaymentWebhookSecret, ctx.GetHeader(webhook.SignatureHeader), body, h.cfg.PaymentWebhookTolerance, time.Now())
+	if err != nil {
+		if !errors.Is(err, webhook.ErrNoSignature) {
+			h.logger.Printf("refused payment webhook: %v", err)
+		}
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var event webhook.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if event.ID == "" {
+		ctx.JSON(400, gin.H{"error": "event id is required"})
+		return
+	}
+	if h.webhookEvents.Contains(event.ID) {
+		ctx.JSON(200, &genprotos.PaymentEventResponse{EventId: event.ID, Duplicate: true})
+		return
+	}
+
+	resp, err := h.client.HandlePaymentEvent(context.Background(), event.Proto())
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	h.webhookEvents.Add(event.ID)
+
+	ctx.JSON(200, resp)
+}
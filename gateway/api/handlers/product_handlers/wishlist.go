@@ -0,0 +1,374 @@
+package producthandlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+// CreateWishlist godoc
+// @Summary Create a wishlist
+// @Description Create a named list for saving products. Names are unique per user.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param wishlist body genprotos.CreateWishlistRequest true "Owner and name"
+// @Success 200 {object} genprotos.Wishlist
+// @Failure 400 {object} genprotos.Message
+// @Failure 409 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists [post]
+func (h *ProductHandlers) CreateWishlist(ctx *gin.Context) {
+	var req genprotos.CreateWishlistRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.CreateWishlist(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListWishlists godoc
+// @Summary List wishlists
+// @Description Retrieve a user's lists with how many products each holds
+// @Tags wishlists
+// @Produce json
+// @Param user_id query string true "User ID"
+// @Success 200 {object} genprotos.ListWishlistsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists [get]
+func (h *ProductHandlers) ListWishlists(ctx *gin.Context) {
+	var req genprotos.ListWishlistsRequest
+	req.UserId = ctx.Query("user_id")
+
+	resp, err := h.client.ListWishlists(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetWishlist godoc
+// @Summary Get a wishlist
+// @Description Retrieve one of the user's lists with current prices and stock
+// @Tags wishlists
+// @Produce json
+// @Param id path string true "Wishlist ID"
+// @Param user_id query string true "Owner ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Wishlist
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/{id} [get]
+func (h *ProductHandlers) GetWishlist(ctx *gin.Context) {
+	req := genprotos.GetWishlistRequest{
+		Id:       ctx.Param("id"),
+		UserId:   ctx.Query("user_id"),
+		Currency: requestCurrency(ctx),
+	}
+
+	resp, err := h.client.GetWishlist(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetSharedWishlist godoc
+// @Summary View a shared wishlist
+// @Description Retrieve a list through its share link. The owner is not shown.
+// @Tags wishlists
+// @Produce json
+// @Param token path string true "Share token"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Wishlist
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/shared/{token} [get]
+func (h *ProductHandlers) GetSharedWishlist(ctx *gin.Context) {
+	req := genprotos.GetWishlistRequest{
+		ShareToken: ctx.Param("token"),
+		Currency:   requestCurrency(ctx),
+	}
+
+	resp, err := h.client.GetWishlist(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// DeleteWishlist godoc
+// @Summary Delete a wishlist
+// @Description Delete one of the user's lists and everything saved on it
+// @Tags wishlists
+// @Produce json
+// @Param id path string true "Wishlist ID"
+// @Param user_id query string true "Owner ID"
+// @Success 200 {object} genprotos.Message
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/{id} [delete]
+func (h *ProductHandlers) DeleteWishlist(ctx *gin.Context) {
+	req := genprotos.DeleteWishlistRequest{
+		Id:     ctx.Param("id"),
+		UserId: ctx.Query("user_id"),
+	}
+
+	resp, err := h.client.DeleteWishlist(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// AddWishlistItem godoc
+// @Summary Save a product
+// @Description Save a product to a list, or to the user's "Saved for later" list when wishlist_id is empty
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param item body genprotos.WishlistItemRequest true "List and product"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.Wishlist
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/items [post]
+func (h *ProductHandlers) AddWishlistItem(ctx *gin.Context) {
+	var req genprotos.WishlistItemRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.AddWishlistItem(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// RemoveWishlistItem godoc
+// @Summary Remove a saved product
+// @Description Take a product off one of the user's lists
+// @Tags wishlists
+// @Produce json
+// @Param id path string true "Wishlist ID"
+// @Param product_id path string true "Product ID"
+// @Param user_id query string true "Owner ID"
+// @Param currency query string false "Display currency (or X-Currency header)"
+// @Success 200 {object} genprotos.Wishlist
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/{id}/items/{product_id} [delete]
+func (h *ProductHandlers) RemoveWishlistItem(ctx *gin.Context) {
+	req := genprotos.WishlistItemRequest{
+		WishlistId: ctx.Param("id"),
+		ProductId:  ctx.Param("product_id"),
+		UserId:     ctx.Query("user_id"),
+		Currency:   requestCurrency(ctx),
+	}
+
+	resp, err := h.client.RemoveWishlistItem(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ShareWishlist godoc
+// @Summary Share a wishlist
+// @Description Create a share token for a list, or revoke it with shared set to false
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Wishlist ID"
+// @Param share body genprotos.ShareWishlistRequest true "Owner and whether to share"
+// @Success 200 {object} genprotos.Wishlist
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/{id}/share [put]
+func (h *ProductHandlers) ShareWishlist(ctx *gin.Context) {
+	var req genprotos.ShareWishlistRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.Id = ctx.Param("id")
+
+	resp, err := h.client.ShareWishlist(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// MoveWishlistItemToCart godoc
+// @Summary Move a saved product to the cart
+// @Description Take a product off a list and add it to the user's cart
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Wishlist ID"
+// @Param product_id path string true "Product ID"
+// @Param move body genprotos.MoveWishlistItemRequest true "Owner and quantity"
+// @Param currency query string false "Display currency when not set in the body (or X-Currency header)"
+// @Success 200 {object} genprotos.Cart
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /wishlists/{id}/items/{product_id}/move-to-cart [post]
+func (h *ProductHandlers) MoveWishlistItemToCart(ctx *gin.Context) {
+	var req genprotos.MoveWishlistItemRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.WishlistId = ctx.Param("id")
+	req.ProductId = ctx.Param("product_id")
+	if req.Currency == "" {
+		req.Currency = requestCurrency(ctx)
+	}
+
+	resp, err := h.client.MoveWishlistItemToCart(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// GetProductSaveCounts godoc
+// @Summary Product save counts
+// @Description Show an artisan how many shoppers saved each of their products, most saved first
+// @Tags wishlists
+// @Produce json
+// @Param artisan_id query string true "Artisan ID"
+// @Param product_id query string false "Only this product"
+// @Success 200 {object} genprotos.GetProductSaveCountsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 404 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /products/saves [get]
+func (h *ProductHandlers) GetProductSaveCounts(ctx *gin.Context) {
+	req := genprotos.GetProductSaveCountsRequest{
+		ArtisanId: ctx.Query("artisan_id"),
+		ProductId: ctx.Query("product_id"),
+	}
+
+	resp, err := h.client.GetProductSaveCounts(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Retrieve a user's notifications, newest first, such as saved products dropping in price or coming back in stock
+// @Tags notifications
+// @Produce json
+// @Param user_id query string true "User ID"
+// @Param unread_only query bool false "Only unread notifications"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (all notifications when omitted)"
+// @Success 200 {object} genprotos.ListNotificationsResponse
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /notifications [get]
+func (h *ProductHandlers) ListNotifications(ctx *gin.Context) {
+	var req genprotos.ListNotificationsRequest
+	req.UserId = ctx.Query("user_id")
+	req.UnreadOnly = ctx.Query("unread_only") == "true"
+
+	if page := ctx.Query("page"); page != "" {
+		value, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		req.Page = value
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		req.Limit = value
+	}
+
+	resp, err := h.client.ListNotifications(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+// MarkNotificationsRead godoc
+// @Summary Mark notifications as read
+// @Description Mark the listed notifications, or all of the user's when ids is empty, as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param read body genprotos.MarkNotificationsReadRequest true "User and notification ids"
+// @Success 200 {object} genprotos.Message
+// @Failure 400 {object} genprotos.Message
+// @Failure 500 {object} genprotos.Message
+// @Router /notifications/read [put]
+func (h *ProductHandlers) MarkNotificationsRead(ctx *gin.Context) {
+	var req genprotos.MarkNotificationsReadRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.client.MarkNotificationsRead(context.Background(), &req)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
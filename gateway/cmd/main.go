@@ -4,17 +4,25 @@ import (
 	"log"
 	"os"
 
+	"github.com/gin-gonic/gin"
+	"github.com/k0kubun/pp"
 	"github.com/ruziba3vich/armiya-gateway/api"
 	authhandlers "github.com/ruziba3vich/armiya-gateway/api/handlers/auth_handlers"
 	producthandlers "github.com/ruziba3vich/armiya-gateway/api/handlers/product_handlers"
 	"github.com/ruziba3vich/armiya-gateway/config"
 	"github.com/ruziba3vich/armiya-gateway/genprotos"
+	"github.com/ruziba3vich/armiya-gateway/redact"
 	"google.golang.org/grpc"
 )
 
 func main() {
 	var cfg config.Config
-	logger := log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
+	// Nothing that looks like a card number is ever printed, whether it
+	// comes from a request URL, a debug dump or an error.
+	gin.DefaultWriter = redact.NewWriter(os.Stdout)
+	gin.DefaultErrorWriter = redact.NewWriter(os.Stderr)
+	pp.SetDefaultOutput(redact.NewWriter(os.Stdout))
+	logger := log.New(redact.NewWriter(os.Stdout), "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
 	if err := cfg.Load(); err != nil {
 		logger.Fatal(err)
 	}
@@ -24,7 +32,13 @@ func main() {
 		logger.Fatalf("Failed to connect to auth service: %v", err)
 	}
 
-	connProduct, err := grpc.Dial(cfg.ProductHost, grpc.WithInsecure())
+	// A return request carries up to five photos.
+	maxFile := 5 * cfg.MaxUploadSize
+	if cfg.MaxImportSize > maxFile {
+		maxFile = cfg.MaxImportSize
+	}
+	connProduct, err := grpc.Dial(cfg.ProductHost, grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(int(maxFile)+1<<20)))
 	if err != nil {
 		logger.Fatalf("Failed to connect to product service: %v", err)
 	}
@@ -35,7 +49,7 @@ func main() {
 
 	productClient := genprotos.NewProductServiceClient(connProduct)
 
-	productHandlers := producthandlers.NewProductHandlers(productClient, logger)
+	productHandlers := producthandlers.NewProductHandlers(productClient, logger, &cfg)
 
 	api := api.New(&cfg, logger, authHandlers, productHandlers)
 	logger.Fatal(api.RUN())
@@ -2,16 +2,28 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type (
 	Config struct {
-		AuthHost      string
-		ProductHost   string
-		AiService     string
-		ServerAddress string
+		AuthHost        string
+		ProductHost     string
+		AiService       string
+		ServerAddress   string
+		MaxUploadSize   int64
+		MaxImportSize   int64
+		DefaultCurrency string
+		// PaymentWebhookSecret signs the events the payment provider
+		// sends; without it the webhook refuses them all.
+		PaymentWebhookSecret string
+		// PaymentWebhookTolerance is how far an event's signature time
+		// may be from now.
+		PaymentWebhookTolerance time.Duration
 	}
 )
 
@@ -23,6 +35,39 @@ func (c *Config) Load() error {
 	c.ProductHost = os.Getenv("PRODUCT_HOST")
 	c.AiService = os.Getenv("AI_SERVICE")
 	c.ServerAddress = os.Getenv("SERVER_ADDRESS")
+
+	c.DefaultCurrency = strings.ToUpper(os.Getenv("DEFAULT_CURRENCY"))
+	if c.DefaultCurrency == "" {
+		c.DefaultCurrency = "USD"
+	}
+
+	c.MaxUploadSize = 5 << 20
+	if value := os.Getenv("MAX_UPLOAD_SIZE"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.MaxUploadSize = size
+	}
+
+	c.MaxImportSize = 8 << 20
+	if value := os.Getenv("MAX_IMPORT_SIZE"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.MaxImportSize = size
+	}
+
+	c.PaymentWebhookSecret = os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	c.PaymentWebhookTolerance = 5 * time.Minute
+	if value := os.Getenv("PAYMENT_WEBHOOK_TOLERANCE"); value != "" {
+		tolerance, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		c.PaymentWebhookTolerance = tolerance
+	}
 	return nil
 }
 
@@ -15,6 +15,316 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/exchange-rates": {
+            "get": {
+                "description": "Retrieve the stored exchange rates, optionally for one base currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange rates"
+                ],
+                "summary": "List exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base currency",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListExchangeRatesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create or replace the rate for a currency pair (1 base = rate quote)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange rates"
+                ],
+                "summary": "Set an exchange rate",
+                "parameters": [
+                    {
+                        "description": "Exchange rate",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.SetExchangeRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ExchangeRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/exchange-rates/{base}/{quote}": {
+            "delete": {
+                "description": "Remove the stored rate for a currency pair",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange rates"
+                ],
+                "summary": "Delete an exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base currency",
+                        "name": "base",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote currency",
+                        "name": "quote",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/artisan/orders": {
+            "get": {
+                "description": "Retrieve the artisan's parts of orders: only their own lines, with the buyer's shipping address. Filter by the part's status and when the order was placed; sort by created_at, updated_at, status or payout, with a leading - for descending (newest first by default).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artisan orders"
+                ],
+                "summary": "List an artisan's orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses of the artisan's part",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Placed at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Placed before, or on when a date (RFC 3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key, e.g. -payout",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all orders when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListArtisanOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/artisan/orders/packing-slips": {
+            "get": {
+                "description": "Retrieve a packing slip for each of the artisan's parts of orders. With format=text the slips come as plain text ready to print, one per page.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "artisan orders"
+                ],
+                "summary": "Print packing slips",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fulfillment IDs",
+                        "name": "fulfillment_ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or text",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetPackingSlipsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/artisan/orders/status": {
+            "put": {
+                "description": "Move several of the artisan's parts of orders to a status, for example processing or shipped. Each moves on its own; results tell which failed and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artisan orders"
+                ],
+                "summary": "Move several orders at once",
+                "parameters": [
+                    {
+                        "description": "Artisan, fulfillments and new status",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.BulkUpdateArtisanOrdersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.BulkUpdateArtisanOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/delete/{id}": {
             "delete": {
                 "description": "This endpoint for deleting user.",
@@ -360,35 +670,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/category": {
-            "post": {
-                "description": "Add a new category to the product catalog",
-                "consumes": [
-                    "application/json"
-                ],
+        "/cart": {
+            "get": {
+                "description": "Retrieve a signed-in buyer's cart (user_id) or an anonymous cart (cart_id), priced with current prices and flagged where stock is short or a price changed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "category"
+                    "cart"
                 ],
-                "summary": "Add a new category",
+                "summary": "Get a cart",
                 "parameters": [
                     {
-                        "description": "Category Details",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/genprotos.AddCategoryRequest"
-                        }
-                    }
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Anonymous cart ID",
+                        "name": "cart_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.AddCategoryResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -397,48 +713,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/genprotos.Message"
                         }
-                    }
-                }
-            }
-        },
-        "/order": {
-            "post": {
-                "description": "Place an order for a product",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "orders"
-                ],
-                "summary": "Order a product",
-                "parameters": [
-                    {
-                        "description": "Order",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/genprotos.OrderRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/genprotos.OrderResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/genprotos.Message"
                         }
@@ -450,42 +732,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/order/all": {
-            "get": {
-                "description": "Retrieve all orders",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Remove every item from a cart",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "cart"
                 ],
-                "summary": "Get all orders",
+                "summary": "Clear a cart",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Page size",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Anonymous cart ID",
+                        "name": "cart_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.GetAllOrdersResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -494,6 +775,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -503,9 +796,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/order/cancel": {
-            "put": {
-                "description": "Cancel a placed order",
+        "/cart/checkout": {
+            "post": {
+                "description": "Place an order for everything in the buyer's cart, with the cart's coupon codes, and empty it. Stock is held as for any other order.",
                 "consumes": [
                     "application/json"
                 ],
@@ -513,25 +806,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "cart"
                 ],
-                "summary": "Cancel an order",
+                "summary": "Check out a cart",
                 "parameters": [
                     {
-                        "description": "Cancel Order",
-                        "name": "order",
+                        "description": "Buyer and shipping address",
+                        "name": "checkout",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.CancelOrderRequest"
+                            "$ref": "#/definitions/genprotos.CheckoutCartRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries safe: a retry gets the first response back, and reusing the key with different input fails with 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.CancelOrderResponse"
+                            "$ref": "#/definitions/genprotos.OrderResponse"
                         }
                     },
                     "400": {
@@ -540,6 +845,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -549,9 +866,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/order/pay": {
+        "/cart/coupons": {
             "post": {
-                "description": "Make a payment for a specific order",
+                "description": "Add a coupon code to a cart. The code must be valid now; coupon_warnings on the cart explain when it does not take anything off the current items.",
                 "consumes": [
                     "application/json"
                 ],
@@ -559,25 +876,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "cart"
                 ],
-                "summary": "Pay for an order",
+                "summary": "Enter a coupon code",
                 "parameters": [
                     {
-                        "description": "Payment",
-                        "name": "payment",
+                        "description": "Cart and code",
+                        "name": "coupon",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.PayRequest"
+                            "$ref": "#/definitions/genprotos.CartCouponRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.PayResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -586,6 +909,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -595,33 +930,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/order/payment/status/{order_id}": {
-            "get": {
-                "description": "Check the status of a payment",
-                "consumes": [
-                    "application/json"
-                ],
+        "/cart/coupons/{code}": {
+            "delete": {
+                "description": "Take a coupon code off a cart",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "cart"
                 ],
-                "summary": "Check payment status",
+                "summary": "Remove a coupon code",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Coupon code",
+                        "name": "code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Anonymous cart ID",
+                        "name": "cart_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.CheckPaymentStatusResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -630,6 +980,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -639,9 +1001,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/order/shipping": {
-            "put": {
-                "description": "Update the shipping details for an order",
+        "/cart/items": {
+            "post": {
+                "description": "Add a quantity of a product to a cart. Without user_id or cart_id a new anonymous cart is created; keep its id to merge it on login.",
                 "consumes": [
                     "application/json"
                 ],
@@ -649,25 +1011,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "shipping"
+                    "cart"
                 ],
-                "summary": "Update shipping details",
+                "summary": "Add to cart",
                 "parameters": [
                     {
-                        "description": "Shipping Details",
-                        "name": "shipping",
+                        "description": "Item",
+                        "name": "item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.UpdateShippingDetailsRequest"
+                            "$ref": "#/definitions/genprotos.CartItemRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.UpdateShippingDetailsResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -676,6 +1044,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -685,9 +1065,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/order/status": {
+        "/cart/items/{product_id}": {
             "put": {
-                "description": "Update the status of an order",
+                "description": "Set the quantity of a product already in the cart. A quantity of 0 removes it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -695,25 +1075,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "cart"
                 ],
-                "summary": "Change order status",
+                "summary": "Change a cart item's quantity",
                 "parameters": [
                     {
-                        "description": "Order Status",
-                        "name": "status",
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cart and new quantity",
+                        "name": "item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.ChangeOrderStatusRequest"
+                            "$ref": "#/definitions/genprotos.CartItemRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.ChangeOrderStatusResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -722,6 +1115,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -729,35 +1134,66 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/order/{id}": {
-            "get": {
-                "description": "Retrieve information for a specific order",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Remove a product from a cart",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "cart"
                 ],
-                "summary": "Show order information",
+                "summary": "Remove from cart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
+                        "description": "Product ID",
+                        "name": "product_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Anonymous cart ID",
+                        "name": "cart_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.ShowOrderInfoResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
                         }
                     },
                     "500": {
@@ -769,9 +1205,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/add": {
+        "/cart/merge": {
             "post": {
-                "description": "Add a new product to the catalog",
+                "description": "Move an anonymous cart into the buyer's cart, adding up quantities of products in both",
                 "consumes": [
                     "application/json"
                 ],
@@ -779,25 +1215,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "cart"
                 ],
-                "summary": "Add a new product",
+                "summary": "Merge an anonymous cart on login",
                 "parameters": [
                     {
-                        "description": "Product",
-                        "name": "product",
+                        "description": "Anonymous cart and buyer",
+                        "name": "merge",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.AddProductRequest"
+                            "$ref": "#/definitions/genprotos.MergeCartRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.AddProductResponse"
+                            "$ref": "#/definitions/genprotos.Cart"
                         }
                     },
                     "400": {
@@ -806,6 +1248,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -815,37 +1263,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/delete/{id}": {
-            "delete": {
-                "description": "Delete a product from the catalog",
-                "consumes": [
-                    "application/json"
-                ],
+        "/categories": {
+            "get": {
+                "description": "List the direct children of a category, or the top-level categories",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "category"
                 ],
-                "summary": "Delete a product",
+                "summary": "List categories",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Parent category ID or slug",
+                        "name": "parent_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListCategoriesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/tree": {
+            "get": {
+                "description": "Retrieve the nested category tree, optionally starting at one category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Get the category tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Root category ID or slug",
+                        "name": "root_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetCategoryTreeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/genprotos.Message"
                         }
@@ -859,9 +1343,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/edit": {
-            "put": {
-                "description": "Edit the details of an existing product",
+        "/category": {
+            "post": {
+                "description": "Add a new category to the product catalog",
                 "consumes": [
                     "application/json"
                 ],
@@ -869,17 +1353,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "category"
                 ],
-                "summary": "Edit an existing product",
+                "summary": "Add a new category",
                 "parameters": [
                     {
-                        "description": "Product",
-                        "name": "product",
+                        "description": "Category Details",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.EditProductRequest"
+                            "$ref": "#/definitions/genprotos.AddCategoryRequest"
                         }
                     }
                 ],
@@ -887,7 +1371,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.EditProductResponse"
+                            "$ref": "#/definitions/genprotos.AddCategoryResponse"
                         }
                     },
                     "400": {
@@ -905,9 +1389,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/rate": {
-            "post": {
-                "description": "Rate a product by its ID",
+        "/category/{id}": {
+            "get": {
+                "description": "Retrieve a category by its ID or slug",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Get a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Category"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update category fields. Omitted fields are left unchanged; setting parent_id moves the whole subtree (\"\" moves it to the top level).",
                 "consumes": [
                     "application/json"
                 ],
@@ -915,17 +1438,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "category"
                 ],
-                "summary": "Rate a product",
+                "summary": "Update a category",
                 "parameters": [
                     {
-                        "description": "Rating",
-                        "name": "rating",
+                        "type": "string",
+                        "description": "Category ID or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.RateProductRequest"
+                            "$ref": "#/definitions/genprotos.UpdateCategoryRequest"
                         }
                     }
                 ],
@@ -933,7 +1463,64 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.RateProductResponse"
+                            "$ref": "#/definitions/genprotos.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a category. Its children move up to its parent; its products must be reassigned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category that receives the deleted category's products",
+                        "name": "reassign_products_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
                         }
                     },
                     "400": {
@@ -942,6 +1529,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -951,25 +1544,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/ratings/{product_id}": {
+        "/category/{id}/attributes": {
             "get": {
-                "description": "Retrieve all ratings for a specific product",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the attribute schema of a category, including attributes inherited from its parents",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "category"
                 ],
-                "summary": "Get all ratings for a product",
+                "summary": "List category attributes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "product_id",
-                        "in": "query",
+                        "description": "Category ID or slug",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -977,7 +1567,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.GetAllRatingsResponse"
+                            "$ref": "#/definitions/genprotos.ListCategoryAttributesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
                         }
                     },
                     "500": {
@@ -987,11 +1583,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/product/search": {
+            },
             "post": {
-                "description": "Search and filter products based on criteria",
+                "description": "Create or replace a typed attribute (string, number, boolean or enum) that products in the category and its subcategories may carry",
                 "consumes": [
                     "application/json"
                 ],
@@ -999,17 +1593,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "category"
                 ],
-                "summary": "Search and filter products",
+                "summary": "Define a category attribute",
                 "parameters": [
                     {
-                        "description": "Details",
-                        "name": "product",
+                        "type": "string",
+                        "description": "Category ID or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attribute definition",
+                        "name": "attribute",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/genprotos.SearchAndFilterRequest"
+                            "$ref": "#/definitions/genprotos.AttributeDefinition"
                         }
                     }
                 ],
@@ -1017,7 +1618,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.SearchAndFilterResponse"
+                            "$ref": "#/definitions/genprotos.AttributeDefinition"
                         }
                     },
                     "400": {
@@ -1026,6 +1627,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/genprotos.Message"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1035,32 +1642,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
-            "get": {
-                "description": "Retrieve all products from the catalog",
-                "consumes": [
-                    "application/json"
-                ],
+        "/category/{id}/attributes/{key}": {
+            "delete": {
+                "description": "Remove an attribute definition from a category",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "category"
                 ],
-                "summary": "Get all products",
+                "summary": "Delete a category attribute",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Category ID or slug",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page size",
-                        "name": "limit",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Attribute key",
+                        "name": "key",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1068,11 +1672,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.GetAllProductsResponse"
+                            "$ref": "#/definitions/genprotos.Message"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/genprotos.Message"
                         }
@@ -1086,33 +1690,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
+        "/notifications": {
             "get": {
-                "description": "Retrieve a product by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve a user's notifications, newest first, such as saved products dropping in price or coming back in stock",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "notifications"
                 ],
-                "summary": "Get a product by ID",
+                "summary": "List notifications",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all notifications when omitted)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/genprotos.GetProductResponse"
+                            "$ref": "#/definitions/genprotos.ListNotificationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
                         }
                     },
                     "500": {
@@ -1123,43 +1748,5386 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "genprotos.AddCategoryRequest": {
-            "type": "object",
-            "properties": {
+        },
+        "/notifications/read": {
+            "put": {
+                "description": "Mark the listed notifications, or all of the user's when ids is empty, as read",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notifications as read",
+                "parameters": [
+                    {
+                        "description": "User and notification ids",
+                        "name": "read",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.MarkNotificationsReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order": {
+            "post": {
+                "description": "Place an order for a product. The stock is held for the order until reserved_until; unpaid orders are cancelled after that. Running promotions and any coupon_codes are applied and listed as discounts. An order that cannot be placed as a whole (missing address, unknown, unpublished or short products, unusable codes) fails with 400 and nothing is written; violations lists every problem by field. The order is split into one fulfillment per artisan, each with its own status, shipping details and payout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Order a product",
+                "parameters": [
+                    {
+                        "description": "Order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.OrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries safe: a retry gets the first response back, and reusing the key with different input fails with 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/all": {
+            "get": {
+                "description": "Retrieve all orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get all orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetAllOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/cancel": {
+            "put": {
+                "description": "Cancel an order, or with fulfillment_id one artisan's part of it, that has not shipped yet. Its stock goes back and what was paid for it is refunded; once the whole order is cancelled its promotion uses are released. role defaults to buyer; actor_id must be the buyer, the artisan of the part, or an admin. status is the order's status afterwards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel an order",
+                "parameters": [
+                    {
+                        "description": "Cancel Order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.CancelOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/my": {
+            "get": {
+                "description": "Retrieve a buyer's order history, newest first. Lines keep the product's name, image and price at purchase, so they still show after the product changes or is archived. Pass next_cursor back as cursor for the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List a buyer's orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated order statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Placed at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Placed before, or on when a date (RFC 3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by product name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (20 by default, at most 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListMyOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/pay": {
+            "post": {
+                "description": "Charge a card for a pending order through the payment provider. The order is paid once the payment is captured (status paid). A declined card comes back with status declined and failure_reason, and the order can be paid again. Status requires_action means the buyer must complete 3-D Secure at challenge_url and then call /order/pay/confirm. Card details are never accepted: the client sends the card to the payment provider (POST /v1/tokens on the mock provider at PAYMENT_MOCK_ADDR, or on the local paystub) and pays with the payment_token it returns; bodies carrying card numbers are refused with 400. Fails with 400 once the order is paid, cancelled or its stock hold has expired, and with 503 when the provider does not answer in time; the payment is then pending and is finished with /order/pay/confirm. The mock provider also accepts these test tokens: tok_visa authorized, tok_declined declined, tok_insufficient_funds insufficient funds, tok_3ds 3-D Secure, tok_3ds_fails failed 3-D Secure, tok_timeout timeout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Pay for an order",
+                "parameters": [
+                    {
+                        "description": "Payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.PayRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries safe: a retry gets the first response back, and reusing the key with different input fails with 409",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.PayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/pay/confirm": {
+            "post": {
+                "description": "Finish a payment left open: a pending payment the provider did not answer for is sent again under the same idempotency key, after the buyer completed 3-D Secure the provider's answer is taken, and an authorized payment is captured, which marks the order paid. Payments that are no longer open are returned as they are.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Confirm a payment",
+                "parameters": [
+                    {
+                        "description": "Payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ConfirmPaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.PayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/payment/status/{order_id}": {
+            "get": {
+                "description": "Check the latest payment attempt of an order: requires_action, authorized, declined, failed, voided, paid, partially_refunded or refunded, with how much went back to the buyer and why a payment did not go through",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Check payment status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.CheckPaymentStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/shipping": {
+            "put": {
+                "description": "Update the shipping details of one artisan's part of an order. fulfillment_id is required when the order has more than one. The update goes on the order's timeline with actor_id and note.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Update shipping details",
+                "parameters": [
+                    {
+                        "description": "Shipping Details",
+                        "name": "shipping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.UpdateShippingDetailsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.UpdateShippingDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/status": {
+            "put": {
+                "description": "Move an order along pending -\u003e paid -\u003e processing -\u003e shipped -\u003e delivered -\u003e completed, or delivered -\u003e returned -\u003e refunded; pending, paid and processing orders can be cancelled. Artisans and admins move orders forward, buyers complete or cancel their own. Illegal moves fail with 400, moves the role may not make with 403. With fulfillment_id only that artisan's part moves; without it every part that is not cancelled does, which artisans may only do for their own parts. The order's status follows from its parts: cancelled parts are left out and it is as far along as its least advanced part. Cancelling or returning puts the stock back; cancelling or refunding refunds what the buyer paid for the part, less what returns already gave back. The buyer is notified of every change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Change order status",
+                "parameters": [
+                    {
+                        "description": "Order, new status and who is asking",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ChangeOrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ChangeOrderStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}": {
+            "get": {
+                "description": "Retrieve information for a specific order, including its fulfillments and timeline",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Show order information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ShowOrderInfoResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/timeline": {
+            "get": {
+                "description": "Retrieve everything that happened to an order, oldest first: when it was placed, each status change, payments and shipping updates, with who did it and their notes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Show an order's timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetOrderTimelineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/add": {
+            "post": {
+                "description": "Add a new product to the catalog",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Add a new product",
+                "parameters": [
+                    {
+                        "description": "Product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.AddProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.AddProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/delete/{id}": {
+            "delete": {
+                "description": "Delete a product from the catalog. Products with order history are archived instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/edit": {
+            "put": {
+                "description": "Update the fields listed in update_mask (\"name,price\" or {\"paths\": [...]}). The version you read must be sent, in the body or an If-Match header; the edit is rejected with 409 if someone else changed the product since.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Edit an existing product",
+                "parameters": [
+                    {
+                        "description": "Product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.EditProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product version the edit is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.EditProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/rate": {
+            "post": {
+                "description": "Rate a product by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Rate a product",
+                "parameters": [
+                    {
+                        "description": "Rating",
+                        "name": "rating",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.RateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.RateProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/ratings/{product_id}": {
+            "get": {
+                "description": "Retrieve all ratings for a specific product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get all ratings for a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetAllRatingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/search": {
+            "post": {
+                "description": "Search and filter products based on criteria",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Search and filter products",
+                "parameters": [
+                    {
+                        "description": "Details",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.SearchAndFilterRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.SearchAndFilterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/images": {
+            "get": {
+                "description": "Retrieve all images of a product in display order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product images"
+                ],
+                "summary": "List product images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListProductImagesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Upload an image for a product. Thumbnails are generated server-side.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product images"
+                ],
+                "summary": "Upload a product image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file (jpeg, png or webp)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Make this the primary image",
+                        "name": "primary",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ProductImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/images/order": {
+            "put": {
+                "description": "Set the display order of all images of a product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product images"
+                ],
+                "summary": "Reorder product images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ReorderProductImagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListProductImagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/images/{image_id}": {
+            "delete": {
+                "description": "Delete an image and its thumbnails",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product images"
+                ],
+                "summary": "Delete a product image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/images/{image_id}/primary": {
+            "put": {
+                "description": "Mark an image as the one shown in listings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product images"
+                ],
+                "summary": "Set the primary product image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ProductImage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/revert": {
+            "post": {
+                "description": "Restore name, description, price, category, tags and attributes from an earlier version. The revert is recorded as a new revision.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Revert a product to an earlier revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version to restore",
+                        "name": "revert",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.RevertProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ProductRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/revisions": {
+            "get": {
+                "description": "Retrieve the change history of a product, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List product revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all revisions when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListProductRevisionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/status": {
+            "put": {
+                "description": "Move a product between draft, published, out_of_stock and archived. Publishing with a future publish_at schedules it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Change a product's lifecycle status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ChangeProductStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ProductStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/stock/adjust": {
+            "post": {
+                "description": "Add to or remove from a product's stock, e.g. after a stock count or for damaged goods. Stock cannot go below zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Adjust stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Signed change, reason (adjustment or return) and note",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.AdjustStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/stock/movements": {
+            "get": {
+                "description": "Retrieve a product's inventory ledger, newest first, with its current stock and the stock the ledger adds up to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List stock movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "initial, sale, cancellation, return, adjustment, import or correction",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all movements when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListStockMovementsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/stock/threshold": {
+            "put": {
+                "description": "Raise a low-stock alert whenever the product's stock is at or below the threshold. 0 turns alerts off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Set a low-stock threshold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Threshold",
+                        "name": "threshold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.SetLowStockThresholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.StockLevel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Retrieve all products from the catalog",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product status (default published); draft and archived need artisan_id",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this artisan's products",
+                        "name": "artisan_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetAllProductsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export": {
+            "get": {
+                "description": "Download an artisan's catalog as CSV or JSON Lines, in the same format imports accept",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "product import"
+                ],
+                "summary": "Export products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or jsonl",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "description": "Queue a CSV or JSON Lines file of products for import. Rows are upserted by sku and processed in the background; poll the returned import for per-row errors. Send the file as the \"file\" form field or as the raw request body.",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product import"
+                ],
+                "summary": "Import products in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or jsonl (guessed from the file name or Content-Type when omitted)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate without saving",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the import (defaults to the artisan)",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Import file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ProductImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/imports": {
+            "get": {
+                "description": "Retrieve an artisan's imports, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product import"
+                ],
+                "summary": "List product imports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all imports when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListProductImportsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/imports/{id}": {
+            "get": {
+                "description": "Retrieve the progress of an import and the rows it could not apply",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product import"
+                ],
+                "summary": "Get a product import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ProductImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/low-stock": {
+            "get": {
+                "description": "Retrieve an artisan's low-stock alerts, newest first. Only open alerts are listed unless include_resolved is set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List low-stock alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include alerts for products that were restocked",
+                        "name": "include_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all alerts when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListLowStockAlertsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/saves": {
+            "get": {
+                "description": "Show an artisan how many shoppers saved each of their products, most saved first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Product save counts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this product",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetProductSaveCountsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/stock/reconcile": {
+            "post": {
+                "description": "List products whose stock no longer matches their inventory ledger. With repair set, a correction movement brings each ledger in line with the stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Reconcile stock with the ledger",
+                "parameters": [
+                    {
+                        "description": "Artisan or product to check",
+                        "name": "reconcile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ReconcileStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ReconcileStockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Retrieve a product by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The product's artisan, needed while it is a draft or archived (a filter, not an access check)",
+                        "name": "artisan_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.GetProductResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "description": "Retrieve promotions, newest first, with how often each was used",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "List promotions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only promotions of this artisan",
+                        "name": "artisan_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only promotions running now",
+                        "name": "active_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all promotions when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListPromotionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a discount rule: percentage, fixed, free_shipping or buy_x_get_y. Without a code it applies to every qualifying order; with one it is a coupon. Limit it to an artisan or category (with subcategories), a minimum subtotal, a validity window and usage limits (0 is unlimited). Stackable promotions combine; otherwise the order gets whichever gives the most off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Create a promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.CreatePromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{id}/active": {
+            "put": {
+                "description": "Pause or resume a promotion. Orders already placed keep their discounts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Switch a promotion on or off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the promotion is active",
+                        "name": "active",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.SetPromotionActiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns": {
+            "get": {
+                "description": "Retrieve returns, newest first, filtered by buyer, artisan, order or status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "List returns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Artisan ID",
+                        "name": "artisan_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated return statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (all returns when omitted)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListReturnsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Ask to send back items of a delivered order, all sold by the same artisan. Each item is an order line id and quantity written as line_id:quantity; give several comma-separated or repeat the field. Up to 5 photos may be attached.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Request a return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Buyer ID",
+                        "name": "user_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Why the items go back",
+                        "name": "reason",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order lines to return, e.g. 3f1c...:1",
+                        "name": "items",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photos of the items (jpeg, png or webp)",
+                        "name": "photos",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}": {
+            "get": {
+                "description": "Retrieve a return with its items, photos and refund",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Get a return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}/cancel": {
+            "put": {
+                "description": "The buyer withdraws a return that was not shipped yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Cancel a return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buyer and note",
+                        "name": "cancel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.CancelReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}/receive": {
+            "put": {
+                "description": "The artisan, or an admin, records that the items came back. They go back in stock and the buyer is refunded what they are worth, or refund_amount (minor units) when less is given back. The return is closed as refunded even when refund_amount is 0. The order's status follows once everything was returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Receive a return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Actor, refund and note",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ReceiveReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}/review": {
+            "put": {
+                "description": "The artisan, or an admin, approves a requested return or rejects it with a note for the buyer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Approve or reject a return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Actor, decision and note",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ReviewReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}/ship": {
+            "put": {
+                "description": "The buyer says an approved return is on its way back, with its tracking details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Ship a return back",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buyer and tracking details",
+                        "name": "shipping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ShipReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/payments": {
+            "post": {
+                "description": "The payment provider reports captures, refunds, voids, declines and chargebacks here. The Payment-Signature header must hold \"t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 of t.body\u003e\" made with the webhook secret within the allowed tolerance of now. Each event is applied once; a redelivered event is answered with duplicate set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Receive a payment provider event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature of the event",
+                        "name": "Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.PaymentEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists": {
+            "get": {
+                "description": "Retrieve a user's lists with how many products each holds",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "List wishlists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ListWishlistsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a named list for saving products. Names are unique per user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Create a wishlist",
+                "parameters": [
+                    {
+                        "description": "Owner and name",
+                        "name": "wishlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.CreateWishlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/items": {
+            "post": {
+                "description": "Save a product to a list, or to the user's \"Saved for later\" list when wishlist_id is empty",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Save a product",
+                "parameters": [
+                    {
+                        "description": "List and product",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.WishlistItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/shared/{token}": {
+            "get": {
+                "description": "Retrieve a list through its share link. The owner is not shown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "View a shared wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Wishlist"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/{id}": {
+            "get": {
+                "description": "Retrieve one of the user's lists with current prices and stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Get a wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete one of the user's lists and everything saved on it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Delete a wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/{id}/items/{product_id}": {
+            "delete": {
+                "description": "Take a product off one of the user's lists",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Remove a saved product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/{id}/items/{product_id}/move-to-cart": {
+            "post": {
+                "description": "Take a product off a list and add it to the user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Move a saved product to the cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Owner and quantity",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.MoveWishlistItemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display currency when not set in the body (or X-Currency header)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/{id}/share": {
+            "put": {
+                "description": "Create a share token for a list, or revoke it with shared set to false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Share a wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Owner and whether to share",
+                        "name": "share",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.ShareWishlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/genprotos.Message"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "genprotos.AddCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.AddCategoryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.AddProductRequest": {
+            "type": "object",
+            "properties": {
+                "artisan_id": {
+                    "type": "string"
+                },
+                "attributes": {
+                    "description": "Values are validated against the category's attribute schema.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "legacy_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku": {
+                    "description": "Unique among the artisan's products; bulk imports match on it.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "draft (default) or published. A future publish_at (RFC 3339) keeps the\nproduct a draft until then.",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "genprotos.AddProductResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "legacy_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.AdjustStockRequest": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "change": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.ArtisanOrder": {
+            "type": "object",
+            "properties": {
+                "buyer_id": {
+                    "type": "string"
+                },
+                "fulfillment": {
+                    "$ref": "#/definitions/genprotos.Fulfillment"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderLine"
+                    }
+                },
+                "order_status": {
+                    "type": "string"
+                },
+                "placed_at": {
+                    "type": "string"
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                }
+            }
+        },
+        "genprotos.AttributeDefinition": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "options": {
+                    "description": "allowed values of an enum",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.AttributeRange": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "genprotos.AuthMessage": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.BulkOrderResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "order_status": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.BulkUpdateArtisanOrdersRequest": {
+            "type": "object",
+            "properties": {
+                "artisan_id": {
+                    "type": "string"
+                },
+                "fulfillment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "note": {
+                    "description": "kept on each order's timeline",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.BulkUpdateArtisanOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.BulkOrderResult"
+                    }
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.BuyerOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "fulfillments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Fulfillment"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderLine"
+                    }
+                },
+                "shipping": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CancelOrderRequest": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
+                    "description": "cancels one artisan's part; the whole order when empty",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "kept on the order timeline",
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "defaults to buyer",
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CancelOrderResponse": {
+            "type": "object",
+            "properties": {
+                "fulfillment_id": {
+                    "type": "string"
+                },
+                "fulfillment_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "previous_status": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CancelReturnRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "return_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Cart": {
+            "type": "object",
+            "properties": {
+                "can_checkout": {
+                    "type": "boolean"
+                },
+                "coupon_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "coupon_warnings": {
+                    "description": "Why an entered code is not taking anything off right now.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "discount_total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderDiscount"
+                    }
+                },
+                "id": {
+                    "description": "empty until the first item is added",
+                    "type": "string"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.CartItem"
+                    }
+                },
+                "shipping": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CartCouponRequest": {
+            "type": "object",
+            "properties": {
+                "cart_id": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CartItem": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "available": {
+                    "description": "false when it cannot be ordered in this quantity",
+                    "type": "boolean"
+                },
+                "in_stock": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CartItemRequest": {
+            "type": "object",
+            "properties": {
+                "cart_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "AddCartItem adds this many; UpdateCartItem sets it, 0 removes the item.",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Category": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Category"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.ChangeOrderStatusRequest": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
+                    "description": "Moves one artisan's part. When empty every part of the order that is\nnot cancelled moves, and an artisan may only do so alone in an order.",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "kept on the order timeline",
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.ChangeOrderStatusResponse": {
+            "type": "object",
+            "properties": {
+                "fulfillment_id": {
+                    "type": "string"
+                },
+                "fulfillment_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "previous_status": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.ChangeProductStatusRequest": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CheckPaymentStatusResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "challenge_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "legacy_amount": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "refunded": {
+                    "description": "How much of the payment went back to the buyer so far.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/genprotos.Money"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CheckoutCartRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.ConfirmPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "payment_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.CreatePromotionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "artisan_id": {
+                    "type": "string"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "min_subtotal": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "type": "boolean"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.CreateWishlistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.EditProductRequest": {
+            "type": "object"
+        },
+        "genprotos.EditProductResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "legacy_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.EditProfileRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.EditProfileResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "user_type": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.EditUserTypeRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "user_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.EditUserTypeResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_type": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "quote_currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Fulfillment": {
+            "type": "object",
+            "properties": {
+                "artisan_id": {
+                    "type": "string"
+                },
+                "carrier": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "estimated_delivery_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Item"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payout": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "refunded": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "shipping": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "tracking_number": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.GetAllOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Order"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.GetAllProductsResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Product"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.GetAllRatingsResponse": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "ratings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Rating"
+                    }
+                },
+                "total_ratings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.GetAllUsersResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.User"
+                    }
+                }
+            }
+        },
+        "genprotos.GetCategoryTreeResponse": {
+            "type": "object",
+            "properties": {
+                "roots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Category"
+                    }
+                }
+            }
+        },
+        "genprotos.GetOrderTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderEvent"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.GetPackingSlipsResponse": {
+            "type": "object",
+            "properties": {
+                "slips": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.PackingSlip"
+                    }
+                }
+            }
+        },
+        "genprotos.GetProductResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "display_price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ProductImage"
+                    }
+                },
+                "legacy_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.GetProductSaveCountsResponse": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ProductSaveCount"
+                    }
+                }
+            }
+        },
+        "genprotos.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "1-based, not counting the CSV header",
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Item": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ListArtisanOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ArtisanOrder"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ListCategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Category"
+                    }
+                }
+            }
+        },
+        "genprotos.ListCategoryAttributesResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.AttributeDefinition"
+                    }
+                }
+            }
+        },
+        "genprotos.ListExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ExchangeRate"
+                    }
+                }
+            }
+        },
+        "genprotos.ListLowStockAlertsResponse": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.LowStockAlert"
+                    }
+                }
+            }
+        },
+        "genprotos.ListMyOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.BuyerOrder"
+                    }
+                }
+            }
+        },
+        "genprotos.ListNotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Notification"
+                    }
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ListProductImagesResponse": {
+            "type": "object",
+            "properties": {
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ProductImage"
+                    }
+                }
+            }
+        },
+        "genprotos.ListProductImportsResponse": {
+            "type": "object",
+            "properties": {
+                "imports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ProductImport"
+                    }
+                }
+            }
+        },
+        "genprotos.ListProductRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ProductRevision"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ListPromotionsResponse": {
+            "type": "object",
+            "properties": {
+                "promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Promotion"
+                    }
+                }
+            }
+        },
+        "genprotos.ListReturnsResponse": {
+            "type": "object",
+            "properties": {
+                "returns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Return"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ListStockMovementsResponse": {
+            "type": "object",
+            "properties": {
+                "ledger_quantity": {
+                    "type": "integer"
+                },
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.StockMovement"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ListWishlistsResponse": {
+            "type": "object",
+            "properties": {
+                "wishlists": {
+                    "description": "without items",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Wishlist"
+                    }
+                }
+            }
+        },
+        "genprotos.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "true": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "genprotos.LowStockAlert": {
+            "type": "object",
+            "properties": {
+                "artisan_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "latest stock while open",
+                    "type": "integer"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.MarkNotificationsReadRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.MergeCartRequest": {
+            "type": "object",
+            "properties": {
+                "cart_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Message": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.MoveWishlistItemRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "1 when zero",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "wishlist_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Order": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "legacy_total_amount": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.OrderDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "promotion_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.OrderEvent": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
+                    "description": "set when the event concerns one artisan's part",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.OrderLine": {
+            "type": "object",
+            "properties": {
+                "base_price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "exchange_rate": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "product": {
+                    "$ref": "#/definitions/genprotos.ProductSnapshot"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "description": "The product's name and primary image when it was bought.",
+                    "type": "string"
+                },
+                "product_revision": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.OrderRequest": {
+            "type": "object",
+            "properties": {
+                "coupon_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Item"
+                    }
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderDiscount"
+                    }
+                },
+                "fulfillments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Fulfillment"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Item"
+                    }
+                },
+                "legacy_total_amount": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "number"
+                },
+                "reserved_until": {
+                    "description": "Unpaid orders are cancelled and their stock released after this time.",
+                    "type": "string"
+                },
+                "shipping": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "description": "total_amount = subtotal + shipping - discount_total",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/genprotos.Money"
+                        }
+                    ]
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.PackingSlip": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.PackingSlipLine"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "placed_at": {
+                    "type": "string"
+                },
+                "ship_to": {
+                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "tracking_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.PackingSlipLine": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.PayRequest": {
+            "type": "object",
+            "properties": {
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "payment_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.PayResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "challenge_url": {
+                    "description": "Where the buyer completes 3-D Secure while status is requires_action.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "description": "Why the provider declined the card, or why the payment failed.",
+                    "type": "string"
+                },
+                "legacy_amount": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending while the provider's answer is not known; ConfirmPayment\nfinishes it.",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.PaymentEventResponse": {
+            "type": "object",
+            "properties": {
+                "duplicate": {
+                    "description": "Set when the event was handled before; nothing was changed.",
+                    "type": "boolean"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "order_status": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Product": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
-                "name": {
+                "display_price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "legacy_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "primary_image": {
+                    "$ref": "#/definitions/genprotos.ProductImage"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ProductImage": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "product_id": {
                     "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "thumbnails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Thumbnail"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.AddCategoryResponse": {
+        "genprotos.ProductImport": {
             "type": "object",
             "properties": {
+                "artisan_id": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "description": "set when the whole file was rejected",
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ImportRowError"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, running, completed or failed",
                     "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.AddProductRequest": {
+        "genprotos.ProductRevision": {
             "type": "object",
             "properties": {
-                "artisan_id": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "restored_version": {
+                    "description": "set on reverts",
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/genprotos.ProductSnapshot"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ProductSaveCount": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
                     "type": "string"
                 },
+                "users": {
+                    "description": "distinct shoppers who saved it",
+                    "type": "integer"
+                },
+                "wishlists": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genprotos.ProductSnapshot": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "category_id": {
                     "type": "string"
                 },
@@ -1170,283 +7138,480 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "price": {
-                    "type": "string"
+                    "$ref": "#/definitions/genprotos.Money"
                 },
                 "quantity": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "genprotos.AddProductResponse": {
+        "genprotos.ProductStatusResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.Promotion": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "artisan_id": {
+                    "type": "string"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
                 "category_id": {
                     "type": "string"
                 },
+                "code": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "ends_at": {
                     "type": "string"
                 },
+                "get_quantity": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "kind": {
                     "type": "string"
                 },
-                "price": {
+                "min_subtotal": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "name": {
                     "type": "string"
                 },
-                "quantity": {
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "description": "can be combined with other stackable promotions",
+                    "type": "boolean"
+                },
+                "starts_at": {
                     "type": "string"
+                },
+                "times_used": {
+                    "type": "integer"
+                },
+                "usage_limit": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.AuthMessage": {
+        "genprotos.RateProductRequest": {
             "type": "object",
             "properties": {
-                "message": {
+                "comment": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.CancelOrderRequest": {
+        "genprotos.RateProductResponse": {
             "type": "object",
             "properties": {
-                "order_id": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.CancelOrderResponse": {
+        "genprotos.Rating": {
             "type": "object",
             "properties": {
-                "id": {
+                "comment": {
                     "type": "string"
                 },
-                "status": {
+                "created_at": {
                     "type": "string"
                 },
-                "updated_at": {
+                "id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.ChangeOrderStatusRequest": {
+        "genprotos.ReceiveReturnRequest": {
             "type": "object",
             "properties": {
-                "order_id": {
+                "actor_id": {
                     "type": "string"
                 },
-                "status": {
+                "note": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "description": "Minor units to give back, at most the items' worth; the full worth\nwhen unset. Zero keeps the items without a refund; the return is\nstill closed as refunded.",
+                    "type": "integer"
+                },
+                "return_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "artisan (default) or admin.",
                     "type": "string"
                 }
             }
         },
-        "genprotos.ChangeOrderStatusResponse": {
+        "genprotos.ReconcileStockRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "actor_id": {
                     "type": "string"
                 },
-                "status": {
+                "artisan_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "product_id": {
                     "type": "string"
+                },
+                "repair": {
+                    "type": "boolean"
                 }
             }
         },
-        "genprotos.CheckPaymentStatusResponse": {
+        "genprotos.ReconcileStockResponse": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.StockDiscrepancy"
+                    }
+                },
+                "repaired": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "genprotos.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "user_type": {
+                    "type": "string"
                 },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "genprotos.RegisterResponse": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "order_id": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "password": {
                     "type": "string"
                 },
-                "payment_id": {
+                "user_type": {
                     "type": "string"
                 },
-                "status": {
+                "username": {
                     "type": "string"
+                }
+            }
+        },
+        "genprotos.ReorderProductImagesRequest": {
+            "type": "object",
+            "properties": {
+                "image_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "transaction_id": {
+                "product_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.EditProductRequest": {
+        "genprotos.ResetPasswordRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "name": {
+                "email": {
                     "type": "string"
-                },
-                "price": {
-                    "type": "number"
                 }
             }
         },
-        "genprotos.EditProductResponse": {
+        "genprotos.Return": {
             "type": "object",
             "properties": {
-                "category_id": {
+                "artisan_id": {
                     "type": "string"
                 },
-                "description": {
+                "carrier": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fulfillment_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.ReturnItem"
+                    }
+                },
+                "order_id": {
                     "type": "string"
                 },
-                "price": {
+                "photo_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
                     "type": "string"
                 },
-                "quantity": {
+                "received_at": {
                     "type": "string"
                 },
-                "updated_at": {
+                "refund_amount": {
+                    "description": "What the items are worth to the buyer until the return is received,\nthen what the artisan agreed to give back.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/genprotos.Money"
+                        }
+                    ]
+                },
+                "refunded": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "refunded_at": {
                     "type": "string"
-                }
-            }
-        },
-        "genprotos.EditProfileRequest": {
-            "type": "object",
-            "properties": {
-                "bio": {
+                },
+                "resolution_note": {
                     "type": "string"
                 },
-                "full_name": {
+                "status": {
                     "type": "string"
                 },
-                "id": {
+                "tracking_number": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.EditProfileResponse": {
+        "genprotos.ReturnItem": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "full_name": {
+                "order_item_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "price": {
+                    "description": "The unit price paid.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/genprotos.Money"
+                        }
+                    ]
                 },
-                "password": {
+                "product_id": {
                     "type": "string"
                 },
-                "user_type": {
+                "product_name": {
                     "type": "string"
                 },
-                "username": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.EditUserTypeRequest": {
+        "genprotos.RevertProductRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "actor_id": {
                     "type": "string"
                 },
-                "user_type": {
+                "expected_version": {
+                    "type": "integer"
+                },
+                "product_id": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.EditUserTypeResponse": {
+        "genprotos.ReviewReturnRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "actor_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "approve": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "description": "Required when rejecting.",
                     "type": "string"
                 },
-                "user_type": {
+                "return_id": {
                     "type": "string"
                 },
-                "username": {
+                "role": {
+                    "description": "artisan (default) or admin.",
                     "type": "string"
                 }
             }
         },
-        "genprotos.GetAllOrdersResponse": {
+        "genprotos.SearchAndFilterRequest": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "orders": {
+                "attribute_ranges": {
+                    "description": "for number attributes",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/genprotos.Order"
+                        "$ref": "#/definitions/genprotos.AttributeRange"
                     }
                 },
-                "page": {
-                    "type": "integer"
+                "attributes": {
+                    "description": "exact matches",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "description": "id or slug; products in subcategories match too",
+                    "type": "string"
+                },
+                "display_currency": {
+                    "type": "string"
+                },
+                "legacy_max_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "number"
+                },
+                "legacy_min_price": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "number"
                 },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "genprotos.GetAllProductsResponse": {
-            "type": "object",
-            "properties": {
                 "limit": {
                     "type": "integer"
                 },
-                "page": {
-                    "type": "integer"
+                "max_price": {
+                    "$ref": "#/definitions/genprotos.Money"
                 },
-                "products": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/genprotos.Product"
-                    }
+                "min_price": {
+                    "$ref": "#/definitions/genprotos.Money"
                 },
-                "total": {
+                "name": {
+                    "type": "string"
+                },
+                "page": {
                     "type": "integer"
-                }
-            }
-        },
-        "genprotos.GetAllRatingsResponse": {
-            "type": "object",
-            "properties": {
-                "average_rating": {
-                    "type": "number"
                 },
-                "ratings": {
+                "tags": {
+                    "description": "products must carry all of them",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/genprotos.Rating"
+                        "type": "string"
                     }
-                },
-                "total_ratings": {
-                    "type": "integer"
                 }
             }
         },
-        "genprotos.GetAllUsersResponse": {
+        "genprotos.SearchAndFilterResponse": {
             "type": "object",
             "properties": {
                 "limit": {
@@ -1455,97 +7620,77 @@ const docTemplate = `{
                 "page": {
                     "type": "integer"
                 },
-                "total": {
-                    "type": "integer"
-                },
-                "users": {
+                "products": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/genprotos.User"
+                        "$ref": "#/definitions/genprotos.Product"
                     }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.GetProductResponse": {
+        "genprotos.SetExchangeRateRequest": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
+                "base_currency": {
                     "type": "string"
                 },
-                "quantity": {
+                "quote_currency": {
                     "type": "string"
                 },
-                "updated_at": {
+                "rate": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.Item": {
+        "genprotos.SetLowStockThresholdRequest": {
             "type": "object",
             "properties": {
                 "product_id": {
                     "type": "string"
                 },
-                "quantity": {
+                "threshold": {
                     "type": "integer"
                 }
             }
         },
-        "genprotos.LoginRequest": {
+        "genprotos.SetPromotionActiveRequest": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "active": {
+                    "type": "boolean"
                 },
-                "password": {
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.LoginResponse": {
+        "genprotos.ShareWishlistRequest": {
             "type": "object",
             "properties": {
-                "true": {
+                "id": {
+                    "type": "string"
+                },
+                "shared": {
                     "type": "boolean"
-                }
-            }
-        },
-        "genprotos.Message": {
-            "type": "object",
-            "properties": {
-                "message": {
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.Order": {
+        "genprotos.ShipReturnRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
+                "carrier": {
                     "type": "string"
                 },
-                "status": {
+                "return_id": {
                     "type": "string"
                 },
-                "total_amount": {
+                "tracking_number": {
                     "type": "string"
                 },
                 "user_id": {
@@ -1553,403 +7698,420 @@ const docTemplate = `{
                 }
             }
         },
-        "genprotos.OrderRequest": {
+        "genprotos.ShippingAddress": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/genprotos.Item"
-                    }
+                "city": {
+                    "type": "string"
                 },
-                "shipping_address": {
-                    "$ref": "#/definitions/genprotos.ShippingAddress"
+                "country": {
+                    "type": "string"
                 },
-                "user_id": {
+                "street": {
+                    "type": "string"
+                },
+                "zip_code": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.OrderResponse": {
+        "genprotos.ShowOrderInfoResponse": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "discount_total": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderDiscount"
+                    }
+                },
+                "fulfillments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Fulfillment"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.Item"
+                    }
+                },
+                "legacy_total_amount": {
+                    "description": "Deprecated: Marked as deprecated in protos/product-protos/product.proto.",
+                    "type": "number"
                 },
-                "items": {
+                "lines": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/genprotos.Item"
+                        "$ref": "#/definitions/genprotos.OrderLine"
                     }
                 },
+                "order_id": {
+                    "type": "string"
+                },
+                "reserved_until": {
+                    "type": "string"
+                },
+                "shipping": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
                 "shipping_address": {
                     "$ref": "#/definitions/genprotos.ShippingAddress"
                 },
                 "status": {
                     "type": "string"
                 },
+                "subtotal": {
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genprotos.OrderEvent"
+                    }
+                },
                 "total_amount": {
-                    "type": "number"
+                    "$ref": "#/definitions/genprotos.Money"
+                },
+                "updated_at": {
+                    "type": "string"
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.PayRequest": {
+        "genprotos.ShowProfileResponse": {
             "type": "object",
             "properties": {
-                "card_number": {
-                    "type": "string"
-                },
-                "cvv": {
-                    "type": "string"
-                },
-                "expiry_date": {
+                "created_at": {
                     "type": "string"
                 },
-                "order_id": {
+                "email": {
                     "type": "string"
                 },
-                "payment_method": {
+                "full_name": {
                     "type": "string"
-                }
-            }
-        },
-        "genprotos.PayResponse": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "number"
                 },
-                "created_at": {
+                "id": {
                     "type": "string"
                 },
-                "order_id": {
+                "password": {
                     "type": "string"
                 },
-                "payment_id": {
+                "updated_at": {
                     "type": "string"
                 },
-                "status": {
+                "user_type": {
                     "type": "string"
                 },
-                "transaction_id": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.Product": {
+        "genprotos.StockDiscrepancy": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "ledger_quantity": {
+                    "type": "integer"
                 },
-                "price": {
+                "product_id": {
                     "type": "string"
                 },
                 "quantity": {
-                    "type": "string"
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.RateProductRequest": {
+        "genprotos.StockLevel": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
+                "low_stock": {
+                    "type": "boolean"
+                },
+                "low_stock_threshold": {
+                    "type": "integer"
                 },
                 "product_id": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
-                },
-                "user_id": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.RateProductResponse": {
+        "genprotos.StockMovement": {
             "type": "object",
             "properties": {
-                "comment": {
+                "actor_id": {
                     "type": "string"
                 },
+                "change": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "note": {
+                    "type": "string"
+                },
                 "product_id": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
+                "quantity_after": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.Rating": {
+        "genprotos.Thumbnail": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
+                "height": {
+                    "type": "integer"
                 },
-                "created_at": {
+                "size": {
                     "type": "string"
                 },
-                "id": {
+                "url": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
-                },
-                "user_id": {
-                    "type": "string"
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "genprotos.RegisterRequest": {
+        "genprotos.UpdateCategoryRequest": {
             "type": "object",
             "properties": {
-                "email": {
+                "description": {
                     "type": "string"
                 },
-                "full_name": {
+                "id": {
                     "type": "string"
                 },
-                "password": {
+                "name": {
                     "type": "string"
                 },
-                "user_type": {
+                "parent_id": {
                     "type": "string"
                 },
-                "username": {
+                "position": {
+                    "type": "integer"
+                },
+                "slug": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.RegisterResponse": {
+        "genprotos.UpdateShippingDetailsRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "actor_id": {
                     "type": "string"
                 },
-                "email": {
+                "carrier": {
                     "type": "string"
                 },
-                "full_name": {
+                "estimated_delivery_date": {
                     "type": "string"
                 },
-                "id": {
+                "fulfillment_id": {
+                    "description": "Required when the order has more than one fulfillment.",
                     "type": "string"
                 },
-                "password": {
+                "note": {
                     "type": "string"
                 },
-                "user_type": {
+                "order_id": {
                     "type": "string"
                 },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
-        "genprotos.ResetPasswordRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
+                "tracking_number": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.SearchAndFilterRequest": {
+        "genprotos.UpdateShippingDetailsResponse": {
             "type": "object",
             "properties": {
-                "category": {
+                "carrier": {
                     "type": "string"
                 },
-                "limit": {
-                    "type": "integer"
-                },
-                "max_price": {
-                    "type": "number"
-                },
-                "min_price": {
-                    "type": "number"
-                },
-                "name": {
+                "estimated_delivery_date": {
                     "type": "string"
                 },
-                "page": {
-                    "type": "integer"
-                }
-            }
-        },
-        "genprotos.SearchAndFilterResponse": {
-            "type": "object",
-            "properties": {
-                "limit": {
-                    "type": "integer"
+                "fulfillment_id": {
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "order_id": {
+                    "type": "string"
                 },
-                "products": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/genprotos.Product"
-                    }
+                "tracking_number": {
+                    "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "genprotos.ShippingAddress": {
+        "genprotos.User": {
             "type": "object",
             "properties": {
-                "city": {
+                "full_name": {
                     "type": "string"
                 },
-                "country": {
+                "id": {
                     "type": "string"
                 },
-                "street": {
+                "user_type": {
                     "type": "string"
                 },
-                "zip_code": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.ShowOrderInfoResponse": {
+        "genprotos.Wishlist": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/genprotos.Item"
+                        "$ref": "#/definitions/genprotos.WishlistItem"
                     }
                 },
-                "order_id": {
+                "name": {
                     "type": "string"
                 },
-                "shipping_address": {
-                    "$ref": "#/definitions/genprotos.ShippingAddress"
-                },
-                "status": {
+                "share_token": {
                     "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
-                },
                 "updated_at": {
                     "type": "string"
                 },
                 "user_id": {
+                    "description": "empty on shared views",
                     "type": "string"
                 }
             }
         },
-        "genprotos.ShowProfileResponse": {
+        "genprotos.WishlistItem": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
+                "added_at": {
                     "type": "string"
                 },
-                "full_name": {
-                    "type": "string"
+                "available": {
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "string"
+                "in_stock": {
+                    "type": "integer"
                 },
-                "password": {
+                "name": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
+                "price": {
+                    "$ref": "#/definitions/genprotos.Money"
                 },
-                "user_type": {
+                "product_id": {
                     "type": "string"
                 },
-                "username": {
-                    "type": "string"
+                "saved_price": {
+                    "description": "the price when it was saved",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/genprotos.Money"
+                        }
+                    ]
                 }
             }
         },
-        "genprotos.UpdateShippingDetailsRequest": {
+        "genprotos.WishlistItemRequest": {
             "type": "object",
             "properties": {
-                "carrier": {
+                "currency": {
                     "type": "string"
                 },
-                "estimated_delivery_date": {
+                "product_id": {
                     "type": "string"
                 },
-                "order_id": {
+                "user_id": {
                     "type": "string"
                 },
-                "tracking_number": {
+                "wishlist_id": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.UpdateShippingDetailsResponse": {
+        "webhook.Event": {
             "type": "object",
             "properties": {
-                "carrier": {
-                    "type": "string"
-                },
-                "estimated_delivery_date": {
-                    "type": "string"
+                "created": {
+                    "type": "integer"
                 },
-                "order_id": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/webhook.Transaction"
                 },
-                "tracking_number": {
+                "id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "genprotos.User": {
+        "webhook.Transaction": {
             "type": "object",
             "properties": {
-                "full_name": {
+                "amount": {
+                    "type": "integer"
+                },
+                "captured": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "failure_code": {
+                    "type": "string"
+                },
+                "failure_message": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "user_type": {
+                "reference": {
                     "type": "string"
                 },
-                "username": {
+                "refunded": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string"
                 }
             }
@@ -0,0 +1,105 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UpgradeLegacyJSON rewrites money fields that older clients still send as
+// plain decimals ("price": "12.50" or "price": 12.5) into the Money object
+// form ({"amount": 1250, "currency": "USD"}) using defaultCurrency. Objects
+// are left alone apart from upper-casing their currency. Only the named
+// fields are touched, at any depth.
+func UpgradeLegacyJSON(body []byte, defaultCurrency string, fields ...string) ([]byte, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field] = true
+	}
+
+	upgraded, err := upgrade(doc, names, defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(upgraded)
+}
+
+func upgrade(value interface{}, names map[string]bool, defaultCurrency string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if names[key] {
+				converted, err := upgradeMoney(key, field, defaultCurrency)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = converted
+				continue
+			}
+			converted, err := upgrade(field, names, defaultCurrency)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+	case []interface{}:
+		for i, item := range v {
+			converted, err := upgrade(item, names, defaultCurrency)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	}
+	return value, nil
+}
+
+func upgradeMoney(key string, value interface{}, defaultCurrency string) (interface{}, error) {
+	var decimal string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		decimal = v
+	case json.Number:
+		decimal = v.String()
+	case map[string]interface{}:
+		if currency, ok := v["currency"].(string); ok {
+			currency = strings.ToUpper(currency)
+			if _, err := Exponent(currency); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v["currency"] = currency
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%s: %w", key, ErrInvalidAmount)
+	}
+
+	amount, err := Parse(decimal, defaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return map[string]interface{}{
+		"amount":   amount,
+		"currency": defaultCurrency,
+	}, nil
+}
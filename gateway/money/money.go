@@ -0,0 +1,58 @@
+// Package money reads the plain decimal amounts older clients send. Exponent
+// and Parse are a copy of product-service's internal/money, which is the
+// source of truth for the currency table and the accepted amount form: the
+// gateway is a separate module and cannot import it, so change it there
+// first and copy it here.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidAmount   = errors.New("invalid amount")
+)
+
+var decimal = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+var exponents = map[string]int{
+	"USD": 2, "EUR": 2, "GBP": 2, "CHF": 2, "CAD": 2, "AUD": 2,
+	"CNY": 2, "INR": 2, "RUB": 2, "TRY": 2, "KZT": 2, "AED": 2,
+	"UZS": 2, "JPY": 0, "KRW": 0, "KWD": 3, "BHD": 3,
+}
+
+func Exponent(currency string) (int, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return exp, nil
+}
+
+func Parse(value, currency string) (int64, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	value = strings.TrimSpace(value)
+	if !decimal.MatchString(value) {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+	r, _ := new(big.Rat).SetString(value)
+
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("%w: %q has more than %d fractional digits for %s", ErrInvalidAmount, value, exp, currency)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, value)
+	}
+
+	return r.Num().Int64(), nil
+}
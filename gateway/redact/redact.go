@@ -0,0 +1,144 @@
+// Package redact keeps card numbers out of the gateway's logs and the
+// requests it forwards. The card number matching (panLike, maskPAN and
+// luhn) is a copy of product-service's internal/redact, which is the source
+// of truth: the gateway is a separate module and cannot import it, so
+// change it there first and copy it here.
+package redact
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var panLike = regexp.MustCompile(`\b(?:\d{13,19}|\d{4}(?:[ -]\d{4}){2}[ -]\d{1,7}|\d{4}[ -]\d{6}[ -]\d{4,5})\b`)
+
+// PAN masks the card numbers in s.
+func PAN(s string) string {
+	if !panLike.MatchString(s) {
+		return s
+	}
+	return panLike.ReplaceAllStringFunc(s, maskPAN)
+}
+
+// ContainsPAN reports whether s holds something that looks like a card
+// number.
+func ContainsPAN(s string) bool {
+	for _, match := range panLike.FindAllString(s, -1) {
+		if maskPAN(match) != match {
+			return true
+		}
+	}
+	return false
+}
+
+func maskPAN(match string) string {
+	digits := make([]byte, 0, len(match))
+	ends := make([]int, 0, len(match))
+	for i := 0; i < len(match); i++ {
+		if match[i] >= '0' && match[i] <= '9' {
+			digits = append(digits, match[i])
+			ends = append(ends, i+1)
+		}
+	}
+	for n := len(digits); n >= 13; n-- {
+		if luhn(digits[:n]) {
+			return strings.Repeat("*", n-4) + string(digits[n-4:n]) + match[ends[n-1]:]
+		}
+	}
+	return match
+}
+
+func luhn(digits []byte) bool {
+	var sum int
+	for i := range digits {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter masks card numbers in everything written to w. Each write is
+// redacted on its own, which suits loggers that write a line at a time.
+func NewWriter(w io.Writer) io.Writer {
+	return writer{w: w}
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, PAN(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// sensitiveFields are blanked wherever they appear, whatever they hold.
+var sensitiveFields = map[protoreflect.Name]bool{
+	"card_number": true,
+	"cvv":         true,
+	"cvc":         true,
+	"expiry_date": true,
+}
+
+// Message returns a copy of m that is safe to print: card fields are
+// blanked and card numbers in any other string are masked.
+func Message(m proto.Message) proto.Message {
+	copied := proto.Clone(m)
+	redactMessage(copied.ProtoReflect())
+	return copied
+}
+
+func redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.StringKind && sensitiveFields[fd.Name()] && !fd.IsList() && !fd.IsMap():
+			m.Set(fd, protoreflect.ValueOfString("[redacted]"))
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				switch fd.Kind() {
+				case protoreflect.StringKind:
+					list.Set(i, protoreflect.ValueOfString(PAN(list.Get(i).String())))
+				case protoreflect.MessageKind, protoreflect.GroupKind:
+					redactMessage(list.Get(i).Message())
+				}
+			}
+		case fd.IsMap():
+			// The map is only changed once it is no longer being ranged over.
+			type entry struct {
+				key   protoreflect.MapKey
+				value string
+			}
+			var masked []entry
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				switch fd.MapValue().Kind() {
+				case protoreflect.StringKind:
+					masked = append(masked, entry{key: k, value: PAN(mv.String())})
+				case protoreflect.MessageKind, protoreflect.GroupKind:
+					redactMessage(mv.Message())
+				}
+				return true
+			})
+			for _, e := range masked {
+				v.Map().Set(e.key, protoreflect.ValueOfString(e.value))
+			}
+		case fd.Kind() == protoreflect.StringKind:
+			m.Set(fd, protoreflect.ValueOfString(PAN(v.String())))
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			redactMessage(v.Message())
+		}
+		return true
+	})
+}
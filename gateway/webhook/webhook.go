@@ -0,0 +1,156 @@
+// Package webhook checks the events payment providers send to the gateway.
+// Each event is signed with a shared secret: the Payment-Signature header
+// holds "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">", and may
+// carry several v1 signatures while the secret is being rotated.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	genprotos "github.com/ruziba3vich/armiya-gateway/genprotos"
+)
+
+const SignatureHeader = "Payment-Signature"
+
+var (
+	ErrNoSignature  = errors.New("the event is not signed")
+	ErrBadSignature = errors.New("the event signature does not match")
+	// ErrStale means the signature was made too far from now, as when an
+	// old delivery is replayed.
+	ErrStale = errors.New("the event signature is too old")
+)
+
+// Verify checks that body was signed with secret no more than tolerance
+// away from now.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var (
+		timestamp  string
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrNoSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrBadSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		given, err := hex.DecodeString(signature)
+		if err != nil || !hmac.Equal(given, expected) {
+			continue
+		}
+		if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+			return ErrStale
+		}
+		return nil
+	}
+	return ErrBadSignature
+}
+
+// Transaction is a provider's transaction as an event reports it. Amounts
+// are in minor units of Currency.
+type Transaction struct {
+	ID             string `json:"id"`
+	Reference      string `json:"reference"`
+	Status         string `json:"status"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	Captured       int64  `json:"captured"`
+	Refunded       int64  `json:"refunded"`
+	FailureCode    string `json:"failure_code,omitempty"`
+	FailureMessage string `json:"failure_message,omitempty"`
+}
+
+// Event tells that a transaction changed; Data is the transaction right
+// after the change.
+type Event struct {
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	Created int64       `json:"created"`
+	Data    Transaction `json:"data"`
+}
+
+// Proto converts the event for product-service.
+func (e Event) Proto() *genprotos.PaymentEvent {
+	event := &genprotos.PaymentEvent{
+		Id:   e.ID,
+		Type: e.Type,
+		Transaction: &genprotos.PaymentTransaction{
+			Id:             e.Data.ID,
+			Reference:      e.Data.Reference,
+			Status:         e.Data.Status,
+			Amount:         e.Data.Amount,
+			Currency:       e.Data.Currency,
+			Captured:       e.Data.Captured,
+			Refunded:       e.Data.Refunded,
+			FailureCode:    e.Data.FailureCode,
+			FailureMessage: e.Data.FailureMessage,
+		},
+	}
+	if e.Created > 0 {
+		event.CreatedAt = time.Unix(e.Created, 0).UTC().Format(time.RFC3339)
+	}
+	return event
+}
+
+// Seen remembers the ids of the most recent events handled, so that
+// redeliveries are answered without bothering product-service, which keeps
+// the lasting record.
+type Seen struct {
+	mu    sync.Mutex
+	ids   map[string]struct{}
+	order []string
+	next  int
+}
+
+func NewSeen(size int) *Seen {
+	return &Seen{
+		ids:   make(map[string]struct{}, size),
+		order: make([]string, size),
+	}
+}
+
+func (s *Seen) Contains(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.ids[id]
+	return ok
+}
+
+// Add remembers id, forgetting the oldest id once the cache is full.
+func (s *Seen) Add(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; ok {
+		return
+	}
+	if oldest := s.order[s.next]; oldest != "" {
+		delete(s.ids, oldest)
+	}
+	s.order[s.next] = id
+	s.ids[id] = struct{}{}
+	s.next = (s.next + 1) % len(s.order)
+}
@@ -3,9 +3,11 @@ package api
 import (
 	"log"
 	"net"
+	"net/http"
 
 	"armiya/equipment-service/genprotos"
 	"armiya/equipment-service/internal/config"
+	"armiya/equipment-service/internal/media"
 
 	"google.golang.org/grpc"
 )
@@ -28,10 +30,35 @@ func (a *API) RUN(config *config.Config) error {
 		return err
 	}
 
-	serverRegisterer := grpc.NewServer()
+	// Image uploads and import files travel inside a single message, so
+	// leave room for the largest allowed file plus the rest of the request.
+	// A return request carries up to five photos.
+	maxFile := 5 * config.Media.MaxUploadSize
+	if config.Catalog.ImportMaxBytes > maxFile {
+		maxFile = config.Catalog.ImportMaxBytes
+	}
+	serverRegisterer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(int(maxFile) + 1<<20),
+	)
 	genprotos.RegisterProductServiceServer(serverRegisterer, a.service)
 
+	if config.Media.Backend == media.BackendLocal {
+		go serveMedia(config.Media)
+	}
+
 	log.Println("server has started running on port", config.Server.Port)
 
 	return serverRegisterer.Serve(listener)
 }
+
+// serveMedia exposes the local media directory over HTTP so the URLs handed
+// out by the local store resolve.
+func serveMedia(cfg config.MediaConfig) {
+	mux := http.NewServeMux()
+	mux.Handle("/media/", http.StripPrefix("/media/", http.FileServer(http.Dir(cfg.LocalDir))))
+
+	log.Println("media server has started running on port", cfg.HTTPPort)
+	if err := http.ListenAndServe(cfg.HTTPPort, mux); err != nil {
+		log.Printf("media server stopped: %v", err)
+	}
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"armiya/equipment-service/api"
 	"armiya/equipment-service/internal/config"
+	"armiya/equipment-service/internal/redact"
 	"armiya/equipment-service/internal/service"
 	"armiya/equipment-service/internal/storage"
+	"context"
 	"log"
+	"net/http"
 	"os"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -15,6 +18,10 @@ import (
 )
 
 func main() {
+	// Nothing that looks like a card number is ever printed.
+	log.SetOutput(redact.NewWriter(os.Stderr))
+	pp.SetDefaultOutput(redact.NewWriter(os.Stdout))
+
 	configs, err := config.New()
 	if err != nil {
 		log.Fatal(err)
@@ -27,6 +34,20 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if configs.Currency.RatesFile != "" {
+		go storage.WatchRatesFile(context.Background(), configs.Currency.RatesFile, configs.Currency.RatesRefresh)
+	}
+	go storage.RunLifecycleWorker(context.Background(), configs.Catalog.LifecycleInterval)
+	go storage.RunImportWorker(context.Background(), configs.Catalog.ImportInterval)
+	go storage.RunReservationWorker(context.Background(), configs.Orders.ReservationInterval)
+	go storage.RunPaymentWorker(context.Background(), configs.Payment.RetryInterval)
+	if stub := storage.PaymentStub(); stub != nil {
+		go func() {
+			log.Println("mock payment provider has started running on", configs.Payment.MockAddr)
+			log.Fatal(http.ListenAndServe(configs.Payment.MockAddr, stub))
+		}()
+	}
+
 	api := api.New(service.New(*storage))
 
 	log.Fatal(api.RUN(configs))
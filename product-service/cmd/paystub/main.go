@@ -0,0 +1,41 @@
+// Command paystub runs a local payment provider for development, backed by
+// the mock provider. Point the service at it with PAYMENT_BACKEND=http and
+// PAYMENT_URL; clients get card tokens from its POST /v1/tokens. With
+// -webhook-url it also sends each event to the gateway's payment webhook,
+// signed with -webhook-secret.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"armiya/equipment-service/internal/payment"
+)
+
+func main() {
+	addr := flag.String("addr", ":4446", "address to listen on")
+	publicURL := flag.String("public-url", "http://localhost:4446", "URL buyers reach the stub at, for 3-D Secure challenges")
+	webhookURL := flag.String("webhook-url", "", "where to send webhook events, e.g. http://localhost:9090/api/v1/webhooks/payments")
+	webhookSecret := flag.String("webhook-secret", os.Getenv("PAYMENT_WEBHOOK_SECRET"), "secret webhook events are signed with")
+	flag.Parse()
+
+	mock := payment.NewMock(*publicURL + "/challenge")
+
+	if *webhookURL != "" {
+		client := &http.Client{Timeout: 10 * time.Second}
+		mock.OnEvent(func(event payment.Event) {
+			if err := payment.DeliverWebhook(context.Background(), client, *webhookURL, *webhookSecret, event, time.Now()); err != nil {
+				log.Printf("webhook: %v", err)
+				return
+			}
+			log.Printf("webhook: delivered %s %s", event.ID, event.Type)
+		})
+	}
+
+	log.Println("payment stub has started running on", *addr)
+	log.Fatal(http.ListenAndServe(*addr, payment.NewStubServer(mock)))
+}
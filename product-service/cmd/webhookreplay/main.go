@@ -0,0 +1,113 @@
+// Command webhookreplay sends payment provider events to the gateway's
+// webhook again, signed like the provider would sign them, for testing
+// webhook handling locally. Events come from the paystub's event log, or
+// from a file holding a JSON array of events.
+//
+// Sending an event more than once (-times), in reverse order (-reverse) or
+// with an old signature (-age) checks that duplicates, late events and
+// stale signatures are handled.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"armiya/equipment-service/internal/payment"
+)
+
+func main() {
+	provider := flag.String("provider", "http://localhost:4446", "paystub to read events from")
+	file := flag.String("file", "", "read events from this JSON file instead of the paystub")
+	target := flag.String("url", "http://localhost:9090/api/v1/webhooks/payments", "webhook to send events to")
+	secret := flag.String("secret", os.Getenv("PAYMENT_WEBHOOK_SECRET"), "secret to sign events with")
+	transaction := flag.String("transaction", "", "only send the events of this transaction")
+	eventID := flag.String("event", "", "only send the event with this id")
+	times := flag.Int("times", 1, "how many times to send each event")
+	reverse := flag.Bool("reverse", false, "send the newest events first")
+	age := flag.Duration("age", 0, "sign events as if they were sent this long ago")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("no secret: pass -secret or set PAYMENT_WEBHOOK_SECRET")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	events, err := loadEvents(client, *provider, *file, *transaction)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var selected []payment.Event
+	for _, event := range events {
+		if *eventID != "" && event.ID != *eventID {
+			continue
+		}
+		if *transaction != "" && event.Data.ID != *transaction {
+			continue
+		}
+		selected = append(selected, event)
+	}
+	if *reverse {
+		for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+			selected[i], selected[j] = selected[j], selected[i]
+		}
+	}
+	if len(selected) == 0 {
+		log.Fatal("no events to send")
+	}
+
+	var failed int
+	for _, event := range selected {
+		for i := 0; i < *times; i++ {
+			err := payment.DeliverWebhook(context.Background(), client, *target, *secret, event, time.Now().Add(-*age))
+			if err != nil {
+				failed++
+				log.Print(err)
+				continue
+			}
+			log.Printf("sent %s %s (transaction %s, %s)", event.ID, event.Type, event.Data.ID, event.Data.Status)
+		}
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d deliveries failed", failed, len(selected)**times)
+	}
+}
+
+func loadEvents(client *http.Client, provider, file, transaction string) ([]payment.Event, error) {
+	var events []payment.Event
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, fmt.Errorf("failed to read events from %s: %v", file, err)
+		}
+		return events, nil
+	}
+
+	query := ""
+	if transaction != "" {
+		query = "?transaction=" + url.QueryEscape(transaction)
+	}
+	resp, err := client.Get(strings.TrimRight(provider, "/") + "/v1/events" + query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events from the paystub: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the paystub answered %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to decode events: %v", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,104 @@
+// Package bulk reads and writes product catalogs in the CSV and JSON Lines
+// formats used by bulk import and export.
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrInvalidFile   = errors.New("invalid file")
+)
+
+// byteOrderMark is skipped at the start of files saved by spreadsheet tools.
+var byteOrderMark = []byte("\ufeff")
+
+// Record is one product row. Nil fields were not present in the row, so an
+// update leaves them alone.
+type Record struct {
+	Row         int
+	SKU         string
+	Name        *string
+	Description *string
+	Price       *string
+	Currency    *string
+	Category    *string
+	Quantity    *string
+	Status      *string
+	Tags        []string
+	Attributes  map[string]string
+}
+
+// RowError reports a row that could not be read or applied.
+type RowError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Message string `json:"message"`
+}
+
+// Writer writes records in one of the supported formats.
+type Writer interface {
+	Write(record *Record) error
+	Flush() error
+}
+
+// ParseFormat normalises a format name, accepting "ndjson" and "json" as
+// JSON Lines.
+func ParseFormat(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONL, "ndjson", "json":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("%w %q, use csv or jsonl", ErrUnknownFormat, value)
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Decode reads every record of a file. Rows that cannot be read are reported
+// as row errors; an error is only returned when the file as a whole is
+// unusable.
+func Decode(format string, data []byte) ([]*Record, []RowError, error) {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(data)
+	case FormatJSONL:
+		return decodeJSONL(data)
+	}
+	return nil, nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+// NewWriter returns a writer for format. CSV files get one attr.<key>
+// column per attribute key.
+func NewWriter(format string, w io.Writer, attributeKeys []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		keys := append([]string(nil), attributeKeys...)
+		sort.Strings(keys)
+		return newCSVWriter(w, keys), nil
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,181 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	attributePrefix = "attr."
+	tagSeparator    = "|"
+)
+
+// csvColumns are the fixed columns, in the order exports write them.
+var csvColumns = []string{"sku", "name", "description", "price", "currency", "category", "quantity", "status", "tags"}
+
+func decodeCSV(data []byte) ([]*Record, []RowError, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, byteOrderMark)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !strings.HasPrefix(name, attributePrefix) && !containsString(csvColumns, name) {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, header[i])
+		}
+		if name == attributePrefix {
+			return nil, nil, fmt.Errorf("%w: attribute column %d has no key", ErrInvalidFile, i+1)
+		}
+		if seen[name] {
+			return nil, nil, fmt.Errorf("%w: column %q appears twice", ErrInvalidFile, name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	if !seen["sku"] {
+		return nil, nil, fmt.Errorf("%w: the sku column is required", ErrInvalidFile)
+	}
+
+	var (
+		records []*Record
+		errs    []RowError
+	)
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, parseErr.Line, parseErr.Err)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if len(fields) != len(columns) {
+			errs = append(errs, RowError{Row: row, Message: fmt.Sprintf("expected %d fields, got %d", len(columns), len(fields))})
+			continue
+		}
+
+		record := &Record{Row: row}
+		for i, column := range columns {
+			// Empty cells leave the current value alone.
+			value := strings.TrimSpace(fields[i])
+			if value == "" {
+				continue
+			}
+			switch column {
+			case "sku":
+				record.SKU = value
+			case "name":
+				record.Name = stringPtr(value)
+			case "description":
+				record.Description = stringPtr(value)
+			case "price":
+				record.Price = stringPtr(value)
+			case "currency":
+				record.Currency = stringPtr(value)
+			case "category":
+				record.Category = stringPtr(value)
+			case "quantity":
+				record.Quantity = stringPtr(value)
+			case "status":
+				record.Status = stringPtr(value)
+			case "tags":
+				record.Tags = strings.Split(value, tagSeparator)
+			default:
+				if record.Attributes == nil {
+					record.Attributes = make(map[string]string)
+				}
+				record.Attributes[strings.TrimPrefix(column, attributePrefix)] = value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, errs, nil
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	attributeKeys []string
+	wroteHeader   bool
+}
+
+func newCSVWriter(w io.Writer, attributeKeys []string) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(w), attributeKeys: attributeKeys}
+}
+
+func (w *csvWriter) Write(record *Record) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	fields := []string{
+		record.SKU,
+		valueOf(record.Name),
+		valueOf(record.Description),
+		valueOf(record.Price),
+		valueOf(record.Currency),
+		valueOf(record.Category),
+		valueOf(record.Quantity),
+		valueOf(record.Status),
+		strings.Join(record.Tags, tagSeparator),
+	}
+	for _, key := range w.attributeKeys {
+		fields = append(fields, record.Attributes[key])
+	}
+	return w.writer.Write(fields)
+}
+
+// Flush writes the header even when there were no records, so an empty
+// export is still a valid import file.
+func (w *csvWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	header := append([]string(nil), csvColumns...)
+	for _, key := range w.attributeKeys {
+		header = append(header, attributePrefix+key)
+	}
+	w.wroteHeader = true
+	return w.writer.Write(header)
+}
+
+func valueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
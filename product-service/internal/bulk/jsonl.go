@@ -0,0 +1,179 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const maxLineSize = 1 << 20
+
+// jsonlRecord is how a record is written as JSON Lines.
+type jsonlRecord struct {
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Price       string            `json:"price,omitempty"`
+	Currency    string            `json:"currency,omitempty"`
+	Category    string            `json:"category,omitempty"`
+	Quantity    json.Number       `json:"quantity,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+func decodeJSONL(data []byte) ([]*Record, []RowError, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, byteOrderMark)))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+	var (
+		records []*Record
+		errs    []RowError
+	)
+	row := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row++
+
+		record, err := decodeJSONLine(line)
+		if err != nil {
+			errs = append(errs, RowError{Row: row, Message: err.Error()})
+			continue
+		}
+		record.Row = row
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, row+1, err)
+	}
+	if row == 0 {
+		return nil, nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+
+	return records, errs, nil
+}
+
+func decodeJSONLine(line []byte) (*Record, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	record := &Record{}
+	for key, raw := range fields {
+		switch key {
+		case "tags":
+			var tags []string
+			if err := json.Unmarshal(raw, &tags); err != nil {
+				return nil, fmt.Errorf("tags must be a list of strings")
+			}
+			record.Tags = tags
+		case "attributes":
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return nil, fmt.Errorf("attributes must be an object")
+			}
+			if values == nil {
+				continue
+			}
+			record.Attributes = make(map[string]string, len(values))
+			for name, value := range values {
+				text, ok, err := scalarString(value)
+				if err != nil {
+					return nil, fmt.Errorf("attribute %q: %v", name, err)
+				}
+				if ok {
+					record.Attributes[name] = text
+				}
+			}
+		case "sku", "name", "description", "price", "currency", "category", "quantity", "status":
+			text, ok, err := scalarString(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", key, err)
+			}
+			if !ok {
+				continue
+			}
+			switch key {
+			case "sku":
+				record.SKU = strings.TrimSpace(text)
+			case "name":
+				record.Name = stringPtr(text)
+			case "description":
+				record.Description = stringPtr(text)
+			case "price":
+				record.Price = stringPtr(text)
+			case "currency":
+				record.Currency = stringPtr(text)
+			case "category":
+				record.Category = stringPtr(text)
+			case "quantity":
+				record.Quantity = stringPtr(text)
+			case "status":
+				record.Status = stringPtr(text)
+			}
+		default:
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	return record, nil
+}
+
+// scalarString reads a JSON string, number or boolean as text. ok is false
+// for null.
+func scalarString(raw json.RawMessage) (string, bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false, err
+	}
+	switch v := value.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case json.Number:
+		return v.String(), true, nil
+	case bool:
+		return strconv.FormatBool(v), true, nil
+	}
+	return "", false, fmt.Errorf("must be a string, number or boolean")
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &jsonlWriter{encoder: encoder}
+}
+
+func (w *jsonlWriter) Write(record *Record) error {
+	return w.encoder.Encode(jsonlRecord{
+		SKU:         record.SKU,
+		Name:        valueOf(record.Name),
+		Description: valueOf(record.Description),
+		Price:       valueOf(record.Price),
+		Currency:    valueOf(record.Currency),
+		Category:    valueOf(record.Category),
+		Quantity:    json.Number(valueOf(record.Quantity)),
+		Status:      valueOf(record.Status),
+		Tags:        record.Tags,
+		Attributes:  record.Attributes,
+	})
+}
+
+func (w *jsonlWriter) Flush() error {
+	return nil
+}
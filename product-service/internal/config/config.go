@@ -1,14 +1,25 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"armiya/equipment-service/internal/money"
 )
 
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Media    MediaConfig
+	Currency CurrencyConfig
+	Catalog  CatalogConfig
+	Orders   OrderConfig
+	Payment  PaymentConfig
 }
 
 type ServerConfig struct {
@@ -23,6 +34,63 @@ type DatabaseConfig struct {
 	DBName   string
 }
 
+type MediaConfig struct {
+	Backend        string
+	LocalDir       string
+	HTTPPort       string
+	PublicURL      string
+	MaxUploadSize  int64
+	MaxPixels      int64
+	AllowedTypes   []string
+	ThumbnailSizes map[string]int
+	S3             S3Config
+}
+
+type CurrencyConfig struct {
+	Default      string
+	Rounding     string
+	RatesFile    string
+	RatesRefresh time.Duration
+}
+
+type CatalogConfig struct {
+	LifecycleInterval time.Duration
+	ImportInterval    time.Duration
+	ImportMaxBytes    int64
+	ImportMaxRows     int
+}
+
+type OrderConfig struct {
+	ReservationTTL      time.Duration
+	ReservationInterval time.Duration
+	// ShippingFee is charged per order, in minor units of the default
+	// currency.
+	ShippingFee int64
+}
+
+type PaymentConfig struct {
+	// Backend is mock (in-process) or http.
+	Backend string
+	// URL is the provider's API for the http backend.
+	URL string
+	// MockAddr is where the mock backend serves the stub API, so clients
+	// can get card tokens from its POST /v1/tokens.
+	MockAddr string
+	Timeout  time.Duration
+	// RetryInterval is how often refunds and voids the provider could not
+	// be reached for are looked at again.
+	RetryInterval time.Duration
+}
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
 func (c *Config) Load() error {
 	err := godotenv.Load()
 	if err != nil {
@@ -36,6 +104,80 @@ func (c *Config) Load() error {
 	c.Database.Password = os.Getenv("DB_PASSWORD")
 	c.Database.DBName = os.Getenv("DB_NAME")
 
+	c.Media.Backend = getEnv("MEDIA_BACKEND", "local")
+	c.Media.LocalDir = getEnv("MEDIA_LOCAL_DIR", "./uploads")
+	c.Media.HTTPPort = ":" + getEnv("MEDIA_HTTP_PORT", "4445")
+	c.Media.PublicURL = strings.TrimRight(os.Getenv("MEDIA_PUBLIC_URL"), "/")
+	c.Media.MaxUploadSize, err = strconv.ParseInt(getEnv("MEDIA_MAX_UPLOAD_SIZE", "5242880"), 10, 64)
+	if err != nil {
+		return err
+	}
+	c.Media.MaxPixels, err = strconv.ParseInt(getEnv("MEDIA_MAX_PIXELS", "40000000"), 10, 64)
+	if err != nil {
+		return err
+	}
+	c.Media.AllowedTypes = strings.Split(getEnv("MEDIA_ALLOWED_TYPES", "image/jpeg,image/png,image/webp"), ",")
+	c.Media.ThumbnailSizes, err = parseSizes(getEnv("MEDIA_THUMBNAIL_SIZES", "small:160,medium:480,large:1024"))
+	if err != nil {
+		return err
+	}
+	c.Media.S3.Endpoint = getEnv("S3_ENDPOINT", "localhost:9000")
+	c.Media.S3.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	c.Media.S3.SecretKey = os.Getenv("S3_SECRET_KEY")
+	c.Media.S3.Bucket = getEnv("S3_BUCKET", "products")
+	c.Media.S3.Region = os.Getenv("S3_REGION")
+	c.Media.S3.UseSSL = os.Getenv("S3_USE_SSL") == "true"
+
+	c.Currency.Default = strings.ToUpper(getEnv("DEFAULT_CURRENCY", "USD"))
+	c.Currency.Rounding = getEnv("CURRENCY_ROUNDING", "half_even")
+	c.Currency.RatesFile = os.Getenv("EXCHANGE_RATES_FILE")
+	c.Currency.RatesRefresh, err = time.ParseDuration(getEnv("EXCHANGE_RATES_REFRESH", "0s"))
+	if err != nil {
+		return err
+	}
+
+	c.Catalog.LifecycleInterval, err = positiveDuration("PRODUCT_LIFECYCLE_INTERVAL", "1m")
+	if err != nil {
+		return err
+	}
+	c.Catalog.ImportInterval, err = positiveDuration("PRODUCT_IMPORT_INTERVAL", "10s")
+	if err != nil {
+		return err
+	}
+	c.Catalog.ImportMaxBytes, err = strconv.ParseInt(getEnv("PRODUCT_IMPORT_MAX_BYTES", "8388608"), 10, 64)
+	if err != nil {
+		return err
+	}
+	c.Catalog.ImportMaxRows, err = strconv.Atoi(getEnv("PRODUCT_IMPORT_MAX_ROWS", "10000"))
+	if err != nil {
+		return err
+	}
+
+	c.Orders.ReservationTTL, err = positiveDuration("ORDER_RESERVATION_TTL", "30m")
+	if err != nil {
+		return err
+	}
+	c.Orders.ReservationInterval, err = positiveDuration("ORDER_RESERVATION_INTERVAL", "1m")
+	if err != nil {
+		return err
+	}
+	c.Orders.ShippingFee, err = money.Parse(getEnv("ORDER_SHIPPING_FEE", "0"), c.Currency.Default)
+	if err != nil {
+		return err
+	}
+
+	c.Payment.Backend = getEnv("PAYMENT_BACKEND", "mock")
+	c.Payment.URL = getEnv("PAYMENT_URL", "http://localhost:4446")
+	c.Payment.MockAddr = getEnv("PAYMENT_MOCK_ADDR", ":4446")
+	c.Payment.Timeout, err = positiveDuration("PAYMENT_TIMEOUT", "10s")
+	if err != nil {
+		return err
+	}
+	c.Payment.RetryInterval, err = positiveDuration("PAYMENT_RETRY_INTERVAL", "1m")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -47,3 +189,45 @@ func New() (*Config, error) {
 	}
 	return config, nil
 }
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// positiveDuration reads a duration that must be above zero, such as a
+// ticker interval, which panics on anything else.
+func positiveDuration(key, fallback string) (time.Duration, error) {
+	value := getEnv(key, fallback)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %s", key, value)
+	}
+	return d, nil
+}
+
+// parseSizes reads a list like "small:160,medium:480" into a name to
+// bounding box (in pixels) map.
+func parseSizes(value string) (map[string]int, error) {
+	sizes := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		name, px, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(px)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("thumbnail size %s must be positive, got %d", name, n)
+		}
+		sizes[name] = n
+	}
+	return sizes, nil
+}
@@ -0,0 +1,150 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"sort"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"armiya/equipment-service/internal/config"
+)
+
+// ErrInvalidImage is wrapped by every validation failure so callers can tell
+// bad input apart from storage errors.
+var ErrInvalidImage = errors.New("invalid image")
+
+type Variant struct {
+	Name        string
+	Width       int
+	Height      int
+	ContentType string
+	Ext         string
+	Data        []byte
+}
+
+type ProcessedImage struct {
+	Original   Variant
+	Thumbnails []Variant
+}
+
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// ProcessImage validates an upload against the media config and renders the
+// configured thumbnail sizes. The declared content type, when given, must
+// agree with what the bytes actually contain.
+func ProcessImage(data []byte, declaredType string, cfg config.MediaConfig) (*ProcessedImage, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty upload", ErrInvalidImage)
+	}
+	if int64(len(data)) > cfg.MaxUploadSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrInvalidImage, len(data), cfg.MaxUploadSize)
+	}
+
+	contentType := http.DetectContentType(data)
+	if declaredType != "" && declaredType != contentType {
+		return nil, fmt.Errorf("%w: declared content type %s does not match detected %s", ErrInvalidImage, declaredType, contentType)
+	}
+	if !allowed(contentType, cfg.AllowedTypes) {
+		return nil, fmt.Errorf("%w: content type %s is not allowed", ErrInvalidImage, contentType)
+	}
+
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode: %v", ErrInvalidImage, err)
+	}
+	if pixels := int64(header.Width) * int64(header.Height); pixels > cfg.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels exceeds the %d pixel limit", ErrInvalidImage, header.Width, header.Height, cfg.MaxPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode: %v", ErrInvalidImage, err)
+	}
+
+	bounds := src.Bounds()
+	processed := &ProcessedImage{
+		Original: Variant{
+			Name:        "original",
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			ContentType: contentType,
+			Ext:         extensions[contentType],
+			Data:        data,
+		},
+	}
+
+	names := make([]string, 0, len(cfg.ThumbnailSizes))
+	for name := range cfg.ThumbnailSizes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return cfg.ThumbnailSizes[names[i]] < cfg.ThumbnailSizes[names[j]]
+	})
+
+	for _, name := range names {
+		thumb, err := thumbnail(src, cfg.ThumbnailSizes[name], contentType)
+		if err != nil {
+			return nil, err
+		}
+		thumb.Name = name
+		processed.Thumbnails = append(processed.Thumbnails, *thumb)
+	}
+
+	return processed, nil
+}
+
+// thumbnail scales src to fit inside a maxSide square, never upscaling.
+// PNG sources stay PNG to keep transparency, everything else becomes JPEG.
+func thumbnail(src image.Image, maxSide int, contentType string) (*Variant, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSide || height > maxSide {
+		if width >= height {
+			height = max(1, height*maxSide/width)
+			width = maxSide
+		} else {
+			width = max(1, width*maxSide/height)
+			height = maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	variant := &Variant{Width: width, Height: height}
+	if contentType == "image/png" {
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %v", err)
+		}
+		variant.ContentType, variant.Ext = "image/png", "png"
+	} else {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %v", err)
+		}
+		variant.ContentType, variant.Ext = "image/jpeg", "jpg"
+	}
+	variant.Data = buf.Bytes()
+
+	return variant, nil
+}
+
+func allowed(contentType string, allowedTypes []string) bool {
+	for _, t := range allowedTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package media
+
+import (
+	"context"
+	"fmt"
+
+	"armiya/equipment-service/internal/config"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Store keeps uploaded objects and knows the public URL they are served from.
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+func NewStore(ctx context.Context, cfg config.MediaConfig) (Store, error) {
+	switch cfg.Backend {
+	case BackendLocal:
+		publicURL := cfg.PublicURL
+		if publicURL == "" {
+			publicURL = "http://localhost" + cfg.HTTPPort + "/media"
+		}
+		return NewLocalStore(cfg.LocalDir, publicURL)
+	case BackendS3:
+		return NewS3Store(ctx, cfg.S3, cfg.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown media backend %q", cfg.Backend)
+	}
+}
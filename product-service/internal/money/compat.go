@@ -0,0 +1,50 @@
+package money
+
+import (
+	"fmt"
+
+	"armiya/equipment-service/genprotos"
+)
+
+// The deprecated price fields kept in the protos for older clients carry
+// plain decimals in an implied currency. These helpers prefer the Money field
+// and fall back to the legacy value in the default currency.
+
+// Resolve picks m when set, otherwise parses the legacy decimal string.
+// A nil result with a nil error means neither was provided.
+func Resolve(m *genprotos.Money, legacy, defaultCurrency string) (*genprotos.Money, error) {
+	if m != nil {
+		if err := Validate(m, false); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	if legacy == "" {
+		return nil, nil
+	}
+
+	amount, err := Parse(legacy, defaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("legacy price: %w", err)
+	}
+	if amount < 0 {
+		return nil, fmt.Errorf("%w: %s is negative", ErrInvalidAmount, legacy)
+	}
+	return New(amount, defaultCurrency), nil
+}
+
+// ResolveFloat is Resolve for legacy float fields, where zero means unset.
+func ResolveFloat(m *genprotos.Money, legacy float32, defaultCurrency string) (*genprotos.Money, error) {
+	if m != nil || legacy == 0 {
+		return Resolve(m, "", defaultCurrency)
+	}
+
+	amount, err := FromFloat(float64(legacy), defaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("legacy price: %w", err)
+	}
+	if amount < 0 {
+		return nil, fmt.Errorf("%w: %v is negative", ErrInvalidAmount, legacy)
+	}
+	return New(amount, defaultCurrency), nil
+}
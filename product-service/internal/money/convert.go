@@ -0,0 +1,170 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var ErrNoRate = errors.New("no exchange rate")
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundDown     RoundingMode = "down"
+	RoundUp       RoundingMode = "up"
+)
+
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(value)); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	case "":
+		return RoundHalfEven, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", value)
+	}
+}
+
+// ParseRate reads a positive decimal exchange rate such as "0.9215".
+func ParseRate(value string) (*big.Rat, error) {
+	trimmed := strings.TrimSpace(value)
+	if !decimal.MatchString(trimmed) {
+		return nil, fmt.Errorf("invalid exchange rate %q", value)
+	}
+	rate, _ := new(big.Rat).SetString(trimmed)
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q", value)
+	}
+	return rate, nil
+}
+
+// FormatRate renders a rate with enough digits to round-trip typical feeds.
+func FormatRate(rate *big.Rat) string {
+	return strings.TrimRight(strings.TrimRight(rate.FloatString(12), "0"), ".")
+}
+
+// Convert turns an amount in from's minor units into to's minor units. The
+// rate is quoted in major units (1 from = rate to).
+func Convert(amount int64, from, to string, rate *big.Rat, mode RoundingMode) (int64, error) {
+	fromExp, err := Exponent(from)
+	if err != nil {
+		return 0, err
+	}
+	toExp, err := Exponent(to)
+	if err != nil {
+		return 0, err
+	}
+
+	value := new(big.Rat).SetInt64(amount)
+	value.Mul(value, rate)
+	value.Mul(value, new(big.Rat).SetFrac(pow10(toExp), pow10(fromExp)))
+
+	rounded := round(value, mode)
+	if !rounded.IsInt64() {
+		return 0, fmt.Errorf("%w: converting %d %s to %s", ErrOverflow, amount, from, to)
+	}
+	return rounded.Int64(), nil
+}
+
+func round(value *big.Rat, mode RoundingMode) *big.Int {
+	num, denom := value.Num(), value.Denom()
+	quo, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// away steps one unit further from zero.
+	away := func() *big.Int {
+		return quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+
+	switch mode {
+	case RoundDown:
+		return quo
+	case RoundUp:
+		return away()
+	}
+
+	// Compare the remainder with half of the denominator.
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	switch twice.Cmp(denom) {
+	case 1:
+		return away()
+	case -1:
+		return quo
+	}
+	if mode == RoundHalfEven && quo.Bit(0) == 0 {
+		return quo
+	}
+	return away()
+}
+
+type Rate struct {
+	Base  string
+	Quote string
+	Rate  *big.Rat
+}
+
+// RateTable answers conversions from a snapshot of stored rates. Pairs are
+// looked up directly, then inverted, then crossed through the pivot currency.
+type RateTable struct {
+	pivot string
+	rates map[[2]string]*big.Rat
+}
+
+func NewRateTable(pivot string, rates []Rate) *RateTable {
+	table := &RateTable{
+		pivot: pivot,
+		rates: make(map[[2]string]*big.Rat, len(rates)),
+	}
+	for _, r := range rates {
+		table.rates[[2]string{r.Base, r.Quote}] = r.Rate
+	}
+	return table
+}
+
+func (t *RateTable) Rate(from, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	if rate, ok := t.direct(from, to); ok {
+		return rate, nil
+	}
+	if from != t.pivot && to != t.pivot {
+		first, ok1 := t.direct(from, t.pivot)
+		second, ok2 := t.direct(t.pivot, to)
+		if ok1 && ok2 {
+			return new(big.Rat).Mul(first, second), nil
+		}
+	}
+	return nil, fmt.Errorf("%w from %s to %s", ErrNoRate, from, to)
+}
+
+func (t *RateTable) direct(from, to string) (*big.Rat, bool) {
+	if rate, ok := t.rates[[2]string{from, to}]; ok {
+		return rate, true
+	}
+	if rate, ok := t.rates[[2]string{to, from}]; ok {
+		return new(big.Rat).Inv(rate), true
+	}
+	return nil, false
+}
+
+// Currencies lists every currency that appears in the table.
+func (t *RateTable) Currencies() []string {
+	seen := map[string]bool{t.pivot: true}
+	currencies := []string{t.pivot}
+	for pair := range t.rates {
+		for _, c := range pair {
+			if !seen[c] {
+				seen[c] = true
+				currencies = append(currencies, c)
+			}
+		}
+	}
+	return currencies
+}
@@ -0,0 +1,53 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// rateFeed is the file format used for exchange rate feeds:
+//
+//	{"base": "USD", "rates": {"EUR": "0.9215", "GBP": 0.7841}}
+type rateFeed struct {
+	Base  string                 `json:"base"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// LoadRatesFile reads a rate feed from disk. Unknown currencies are rejected
+// so a typo in the feed does not silently add an unusable pair.
+func LoadRatesFile(path string) ([]Rate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %v", err)
+	}
+
+	var feed rateFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file: %v", err)
+	}
+
+	base := strings.ToUpper(feed.Base)
+	if _, err := Exponent(base); err != nil {
+		return nil, err
+	}
+
+	rates := make([]Rate, 0, len(feed.Rates))
+	for quote, value := range feed.Rates {
+		quote = strings.ToUpper(quote)
+		if _, err := Exponent(quote); err != nil {
+			return nil, err
+		}
+		if quote == base {
+			continue
+		}
+		rate, err := ParseRate(value.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %v", base, quote, err)
+		}
+		rates = append(rates, Rate{Base: base, Quote: quote, Rate: rate})
+	}
+
+	return rates, nil
+}
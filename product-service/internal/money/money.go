@@ -0,0 +1,207 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"armiya/equipment-service/genprotos"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrOverflow        = errors.New("amount overflow")
+)
+
+// decimal is the only form amounts are accepted in. big.Rat would also take
+// fractions such as "1/3" and exponents such as "1e6", and a huge exponent
+// is costly to expand.
+var decimal = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// exponents lists the ISO 4217 minor unit digits of the currencies we accept.
+// The gateway's money package keeps a copy of it and of Parse; keep the two
+// in step.
+var exponents = map[string]int{
+	"USD": 2, "EUR": 2, "GBP": 2, "CHF": 2, "CAD": 2, "AUD": 2,
+	"CNY": 2, "INR": 2, "RUB": 2, "TRY": 2, "KZT": 2, "AED": 2,
+	"UZS": 2, "JPY": 0, "KRW": 0, "KWD": 3, "BHD": 3,
+}
+
+// Exponent returns the number of minor unit digits of a currency.
+func Exponent(currency string) (int, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return exp, nil
+}
+
+func New(amount int64, currency string) *genprotos.Money {
+	return &genprotos.Money{Amount: amount, Currency: currency}
+}
+
+// Validate checks that m names a supported currency and, unless allowNegative
+// is set, holds a non-negative amount.
+func Validate(m *genprotos.Money, allowNegative bool) error {
+	if m == nil {
+		return fmt.Errorf("%w: missing", ErrInvalidAmount)
+	}
+	if _, err := Exponent(m.Currency); err != nil {
+		return err
+	}
+	if !allowNegative && m.Amount < 0 {
+		return fmt.Errorf("%w: %d is negative", ErrInvalidAmount, m.Amount)
+	}
+	return nil
+}
+
+// Parse reads a decimal string such as "12.50" into minor units. It rejects
+// more fractional digits than the currency has instead of rounding them away.
+func Parse(value, currency string) (int64, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	value = strings.TrimSpace(value)
+	if !decimal.MatchString(value) {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+	r, _ := new(big.Rat).SetString(value)
+
+	r.Mul(r, new(big.Rat).SetInt(pow10(exp)))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("%w: %q has more than %d fractional digits for %s", ErrInvalidAmount, value, exp, currency)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, value)
+	}
+
+	return r.Num().Int64(), nil
+}
+
+// FromFloat converts a legacy floating point amount, rounding to the nearest
+// minor unit. Only use it for inputs that were floats to begin with.
+func FromFloat(value float64, currency string) (int64, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, value)
+	}
+
+	scaled := math.Round(value * math.Pow10(exp))
+	if scaled > math.MaxInt64 || scaled < math.MinInt64 {
+		return 0, fmt.Errorf("%w: %v", ErrOverflow, value)
+	}
+
+	return int64(scaled), nil
+}
+
+// Format renders minor units as a plain decimal string, e.g. 1250 USD -> "12.50".
+func Format(amount int64, currency string) string {
+	exp, err := Exponent(currency)
+	if err != nil || exp == 0 {
+		return fmt.Sprintf("%d", amount)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(amount))
+	unit := pow10(exp)
+	whole, frac := new(big.Int).QuoRem(abs, unit, new(big.Int))
+
+	return fmt.Sprintf("%s%s.%0*d", sign, whole.String(), exp, frac.Int64())
+}
+
+// ToFloat is only meant for filling deprecated float fields.
+func ToFloat(amount int64, currency string) float32 {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return float32(amount)
+	}
+	return float32(float64(amount) / math.Pow10(exp))
+}
+
+// Add sums amounts of the same currency, refusing to silently overflow.
+func Add(a, b int64) (int64, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// Multiply returns amount * quantity, refusing to silently overflow.
+func Multiply(amount int64, quantity uint64) (int64, error) {
+	if quantity == 0 || amount == 0 {
+		return 0, nil
+	}
+	if quantity > math.MaxInt64 {
+		return 0, ErrOverflow
+	}
+	product := amount * int64(quantity)
+	if product/int64(quantity) != amount {
+		return 0, ErrOverflow
+	}
+	return product, nil
+}
+
+// Allocate splits a non-negative amount into parts proportional to weights.
+// The parts add up to amount exactly: what rounding leaves over goes a
+// minor unit at a time to the parts that lost the most. Zero weights split
+// it evenly.
+func Allocate(amount int64, weights []int64) []int64 {
+	parts := make([]int64, len(weights))
+	if len(weights) == 0 || amount <= 0 {
+		return parts
+	}
+
+	total := new(big.Int)
+	for _, weight := range weights {
+		total.Add(total, big.NewInt(max(weight, 0)))
+	}
+	even := total.Sign() == 0
+
+	remainders := make([]*big.Int, len(weights))
+	left := amount
+	for i, weight := range weights {
+		share := new(big.Int)
+		if even {
+			share.SetInt64(amount)
+			remainders[i] = new(big.Int).Mod(share, big.NewInt(int64(len(weights))))
+			share.Div(share, big.NewInt(int64(len(weights))))
+		} else {
+			share.Mul(big.NewInt(amount), big.NewInt(max(weight, 0)))
+			remainders[i] = new(big.Int).Mod(share, total)
+			share.Div(share, total)
+		}
+		parts[i] = share.Int64()
+		left -= parts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := 0; left > 0; i = (i + 1) % len(order) {
+		parts[order[i]]++
+		left--
+	}
+	return parts
+}
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
@@ -0,0 +1,130 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPProvider talks to a payment provider over its REST API, such as the
+// stub server in cmd/paystub.
+type HTTPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// apiError is the body of a failed request.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type authorizeBody struct {
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Method    string `json:"method"`
+	Token     string `json:"token"`
+}
+
+type amountBody struct {
+	Amount int64 `json:"amount"`
+}
+
+func NewHTTPProvider(baseURL string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HTTPProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error) {
+	body := authorizeBody{
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Method:    req.Method,
+		Token:     req.Token,
+	}
+	return h.do(ctx, http.MethodPost, "/v1/transactions", body, req.IdempotencyKey)
+}
+
+func (h *HTTPProvider) Capture(ctx context.Context, id string, amount int64) (*Transaction, error) {
+	return h.do(ctx, http.MethodPost, "/v1/transactions/"+url.PathEscape(id)+"/capture", amountBody{Amount: amount}, "")
+}
+
+func (h *HTTPProvider) Void(ctx context.Context, id string) (*Transaction, error) {
+	return h.do(ctx, http.MethodPost, "/v1/transactions/"+url.PathEscape(id)+"/void", nil, "")
+}
+
+func (h *HTTPProvider) Refund(ctx context.Context, id string, amount int64, key string) (*Transaction, error) {
+	return h.do(ctx, http.MethodPost, "/v1/transactions/"+url.PathEscape(id)+"/refund", amountBody{Amount: amount}, key)
+}
+
+func (h *HTTPProvider) Status(ctx context.Context, id string) (*Transaction, error) {
+	return h.do(ctx, http.MethodGet, "/v1/transactions/"+url.PathEscape(id), nil, "")
+}
+
+// do sends a request to the provider. A non-empty idempotencyKey goes in the
+// Idempotency-Key header, so the provider acts on a retried request once.
+func (h *HTTPProvider) do(ctx context.Context, method, path string, body interface{}, idempotencyKey string) (*Transaction, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode payment request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, h.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build payment request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("failed to reach payment provider: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure apiError
+		if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil {
+			failure.Message = resp.Status
+		}
+		switch resp.StatusCode {
+		case http.StatusBadRequest, http.StatusConflict:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, failure.Message)
+		case http.StatusNotFound:
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, failure.Message)
+		case http.StatusGatewayTimeout:
+			return nil, ErrTimeout
+		default:
+			return nil, fmt.Errorf("payment provider answered %s: %s", resp.Status, failure.Message)
+		}
+	}
+
+	var txn Transaction
+	if err := json.NewDecoder(resp.Body).Decode(&txn); err != nil {
+		return nil, fmt.Errorf("failed to decode payment response: %v", err)
+	}
+	return &txn, nil
+}
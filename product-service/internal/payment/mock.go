@@ -0,0 +1,386 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Test cards the mock treats specially. Any other card that passes the
+// Luhn check and has not expired is authorized.
+const (
+	CardDeclined          = "4000000000000002"
+	CardInsufficientFunds = "4000000000009995"
+	CardChallenge         = "4000000000003220"
+	CardChallengeFails    = "4000008400001629"
+	CardTimeout           = "4000000000000119"
+)
+
+// Test tokens the mock always accepts, so clients can pay without
+// tokenizing a card first. Each stands for a card above; TokenVisa is an
+// ordinary card that is authorized.
+const (
+	TokenVisa              = "tok_visa"
+	TokenDeclined          = "tok_declined"
+	TokenInsufficientFunds = "tok_insufficient_funds"
+	TokenChallenge         = "tok_3ds"
+	TokenChallengeFails    = "tok_3ds_fails"
+	TokenTimeout           = "tok_timeout"
+)
+
+var testTokens = map[string]string{
+	TokenVisa:              "4242424242424242",
+	TokenDeclined:          CardDeclined,
+	TokenInsufficientFunds: CardInsufficientFunds,
+	TokenChallenge:         CardChallenge,
+	TokenChallengeFails:    CardChallengeFails,
+	TokenTimeout:           CardTimeout,
+}
+
+// Mock is an in-memory provider with deterministic outcomes decided by the
+// card number, for local development.
+//
+// 3-D Secure cards need a challenge before they are authorized. With a
+// challenge URL the challenge waits for CompleteChallenge, which the stub
+// server calls when the buyer answers at that URL. Without one there is
+// nobody to answer it, so it is completed at the next status check:
+// CardChallenge passes, CardChallengeFails fails.
+//
+// Every change to a transaction is recorded as an Event, as a real provider
+// would send it by webhook.
+type Mock struct {
+	mu           sync.Mutex
+	challengeURL string
+	tokens       map[string]mockCard
+	transactions map[string]*mockTransaction
+	// authorizeKeys maps an authorization's idempotency key to the
+	// transaction it made.
+	authorizeKeys map[string]string
+	refundKeys    map[string]bool
+	events        []Event
+	onEvent       func(Event)
+}
+
+type mockCard struct {
+	number  string
+	expires time.Time
+}
+
+type mockTransaction struct {
+	Transaction
+	card string
+}
+
+func NewMock(challengeURL string) *Mock {
+	m := &Mock{
+		challengeURL:  strings.TrimRight(challengeURL, "/"),
+		tokens:        make(map[string]mockCard),
+		transactions:  make(map[string]*mockTransaction),
+		authorizeKeys: make(map[string]string),
+		refundKeys:    make(map[string]bool),
+	}
+	for token, number := range testTokens {
+		m.tokens[token] = mockCard{number: number, expires: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	}
+	return m
+}
+
+// Tokenize swaps a card for a token to pay with. The CVV is checked and
+// then forgotten.
+func (m *Mock) Tokenize(card Card) (*Token, error) {
+	number := strings.ReplaceAll(strings.ReplaceAll(card.Number, " ", ""), "-", "")
+	if !luhn(number) {
+		return nil, fmt.Errorf("%w: card number is not valid", ErrInvalidRequest)
+	}
+	if len(card.CVV) < 3 || len(card.CVV) > 4 || strings.Trim(card.CVV, "0123456789") != "" {
+		return nil, fmt.Errorf("%w: cvv is not valid", ErrInvalidRequest)
+	}
+	expires, err := parseExpiry(card.Expiry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	token := &Token{
+		ID:     "tok_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Last4:  number[len(number)-4:],
+		Expiry: strings.TrimSpace(card.Expiry),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[token.ID] = mockCard{number: number, expires: expires}
+	return token, nil
+}
+
+func (m *Mock) Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRequest)
+	}
+	m.mu.Lock()
+	if id, ok := m.authorizeKeys[req.IdempotencyKey]; ok {
+		defer m.mu.Unlock()
+		return m.transactions[id].snapshot(), nil
+	}
+	card, ok := m.tokens[req.Token]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown payment token", ErrInvalidRequest)
+	}
+	number, expires := card.number, card.expires
+	if number == CardTimeout {
+		return nil, ErrTimeout
+	}
+
+	txn := &mockTransaction{
+		Transaction: Transaction{
+			ID:        "mock_" + uuid.NewString(),
+			Reference: req.Reference,
+			Status:    StatusAuthorized,
+			Amount:    req.Amount,
+			Currency:  req.Currency,
+		},
+		card: number,
+	}
+	switch {
+	case !time.Now().Before(expires):
+		txn.decline("expired_card", "the card has expired")
+	case number == CardDeclined:
+		txn.decline("card_declined", "the card was declined")
+	case number == CardInsufficientFunds:
+		txn.decline("insufficient_funds", "the card has insufficient funds")
+	case number == CardChallenge || number == CardChallengeFails:
+		txn.Status = StatusRequiresAction
+		if m.challengeURL != "" {
+			txn.ChallengeURL = m.challengeURL + "/" + txn.ID
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transactions[txn.ID] = txn
+	if req.IdempotencyKey != "" {
+		m.authorizeKeys[req.IdempotencyKey] = txn.ID
+	}
+	switch txn.Status {
+	case StatusAuthorized:
+		m.record(EventAuthorized, txn)
+	case StatusDeclined:
+		m.record(EventDeclined, txn)
+	}
+	return txn.snapshot(), nil
+}
+
+func (m *Mock) Capture(ctx context.Context, id string, amount int64) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txn, err := m.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status != StatusAuthorized {
+		return nil, fmt.Errorf("%w: transaction %s is %s and cannot be captured", ErrInvalidRequest, id, txn.Status)
+	}
+	if amount <= 0 || amount > txn.Amount {
+		return nil, fmt.Errorf("%w: capture amount must be between 1 and %d", ErrInvalidRequest, txn.Amount)
+	}
+	txn.Status = StatusCaptured
+	txn.Captured = amount
+	m.record(EventCaptured, txn)
+	return txn.snapshot(), nil
+}
+
+func (m *Mock) Void(ctx context.Context, id string) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txn, err := m.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status != StatusAuthorized && txn.Status != StatusRequiresAction {
+		return nil, fmt.Errorf("%w: transaction %s is %s and cannot be voided", ErrInvalidRequest, id, txn.Status)
+	}
+	txn.Status = StatusVoided
+	txn.ChallengeURL = ""
+	m.record(EventVoided, txn)
+	return txn.snapshot(), nil
+}
+
+func (m *Mock) Refund(ctx context.Context, id string, amount int64, key string) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txn, err := m.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" && m.refundKeys[key] {
+		return txn.snapshot(), nil
+	}
+	if txn.Status != StatusCaptured && txn.Status != StatusPartiallyRefunded {
+		return nil, fmt.Errorf("%w: transaction %s is %s and cannot be refunded", ErrInvalidRequest, id, txn.Status)
+	}
+	if amount <= 0 || amount > txn.Captured-txn.Refunded {
+		return nil, fmt.Errorf("%w: refund amount must be between 1 and %d", ErrInvalidRequest, txn.Captured-txn.Refunded)
+	}
+	if key != "" {
+		m.refundKeys[key] = true
+	}
+	txn.Refunded += amount
+	txn.Status = StatusPartiallyRefunded
+	if txn.Refunded == txn.Captured {
+		txn.Status = StatusRefunded
+	}
+	m.record(EventRefunded, txn)
+	return txn.snapshot(), nil
+}
+
+// Chargeback has the card's bank take back what is left of a captured
+// transaction, as happens when a buyer disputes a payment.
+func (m *Mock) Chargeback(id string) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txn, err := m.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status != StatusCaptured && txn.Status != StatusPartiallyRefunded {
+		return nil, fmt.Errorf("%w: transaction %s is %s and cannot be charged back", ErrInvalidRequest, id, txn.Status)
+	}
+	txn.Status = StatusChargedBack
+	m.record(EventChargedBack, txn)
+	return txn.snapshot(), nil
+}
+
+// Events returns the events recorded so far, oldest first.
+func (m *Mock) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event(nil), m.events...)
+}
+
+// OnEvent has fn called with each new event, outside the mock's lock.
+func (m *Mock) OnEvent(fn func(Event)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEvent = fn
+}
+
+// record keeps an event for the transaction's current state. Callers hold
+// m.mu.
+func (m *Mock) record(eventType string, txn *mockTransaction) {
+	event := Event{
+		ID:      "evt_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Type:    eventType,
+		Created: time.Now().Unix(),
+		Data:    *txn.snapshot(),
+	}
+	m.events = append(m.events, event)
+	if m.onEvent != nil {
+		go m.onEvent(event)
+	}
+}
+
+func (m *Mock) Status(ctx context.Context, id string) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txn, err := m.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status == StatusRequiresAction && m.challengeURL == "" {
+		m.completeChallenge(txn, txn.card == CardChallenge)
+	}
+	return txn.snapshot(), nil
+}
+
+// CompleteChallenge records the buyer's answer to a 3-D Secure challenge.
+func (m *Mock) CompleteChallenge(id string, passed bool) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txn, err := m.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status != StatusRequiresAction {
+		return nil, fmt.Errorf("%w: transaction %s has no challenge pending", ErrInvalidRequest, id)
+	}
+	m.completeChallenge(txn, passed)
+	return txn.snapshot(), nil
+}
+
+func (m *Mock) find(id string) (*mockTransaction, error) {
+	txn, ok := m.transactions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return txn, nil
+}
+
+func (m *Mock) completeChallenge(t *mockTransaction, passed bool) {
+	t.ChallengeURL = ""
+	if passed {
+		t.Status = StatusAuthorized
+		m.record(EventAuthorized, t)
+		return
+	}
+	t.decline("authentication_failed", "3-D Secure authentication failed")
+	m.record(EventDeclined, t)
+}
+
+func (t *mockTransaction) decline(code, message string) {
+	t.Status = StatusDeclined
+	t.FailureCode = code
+	t.FailureMessage = message
+}
+
+// snapshot copies the transaction so callers never share the mock's state.
+func (t *mockTransaction) snapshot() *Transaction {
+	copied := t.Transaction
+	return &copied
+}
+
+// luhn reports whether number is a plausible card number.
+func luhn(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+	var sum int
+	for i := range number {
+		digit := int(number[len(number)-1-i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// parseExpiry returns the first moment a card given as MM/YY or MM/YYYY is
+// no longer valid.
+func parseExpiry(value string) (time.Time, error) {
+	month, year, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return time.Time{}, fmt.Errorf("expiry date %q must be MM/YY", value)
+	}
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return time.Time{}, fmt.Errorf("expiry date %q has no valid month", value)
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil || (len(year) != 2 && len(year) != 4) {
+		return time.Time{}, fmt.Errorf("expiry date %q has no valid year", value)
+	}
+	if len(year) == 2 {
+		y += 2000
+	}
+	return time.Date(y, time.Month(m)+1, 1, 0, 0, 0, 0, time.UTC), nil
+}
@@ -0,0 +1,110 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"armiya/equipment-service/internal/config"
+)
+
+const (
+	BackendMock = "mock"
+	BackendHTTP = "http"
+)
+
+// Statuses of a transaction at the provider.
+const (
+	StatusRequiresAction    = "requires_action"
+	StatusAuthorized        = "authorized"
+	StatusDeclined          = "declined"
+	StatusCaptured          = "captured"
+	StatusVoided            = "voided"
+	StatusPartiallyRefunded = "partially_refunded"
+	StatusRefunded          = "refunded"
+	// StatusChargedBack means the card's bank took the money back.
+	StatusChargedBack = "charged_back"
+)
+
+var (
+	// ErrInvalidRequest means the provider refused the request itself, for
+	// example a malformed card or a capture of a voided transaction.
+	ErrInvalidRequest = errors.New("invalid payment request")
+	ErrNotFound       = errors.New("transaction not found")
+	// ErrTimeout means the provider did not answer in time; the outcome is
+	// unknown until its status is checked.
+	ErrTimeout = errors.New("payment provider timed out")
+)
+
+// Card is what a buyer hands the provider to get a Token. It never passes
+// through our services.
+type Card struct {
+	Number string `json:"number"`
+	// Expiry is MM/YY or MM/YYYY.
+	Expiry string `json:"expiry"`
+	CVV    string `json:"cvv"`
+}
+
+// Token stands in for a card. Clients get one from the provider and pay
+// with its ID.
+type Token struct {
+	ID     string `json:"id"`
+	Last4  string `json:"last4"`
+	Expiry string `json:"expiry"`
+}
+
+type AuthorizeRequest struct {
+	// Reference ties the transaction to the order it pays for.
+	Reference string
+	Amount    int64
+	Currency  string
+	Method    string
+	// Token is the ID of the card's Token.
+	Token string
+	// IdempotencyKey makes an authorization sent again, after a timeout for
+	// instance, return the first transaction instead of making another.
+	IdempotencyKey string
+}
+
+// Transaction is the provider's view of a payment. A declined
+// authorization is a transaction too, with FailureCode saying why.
+type Transaction struct {
+	ID             string `json:"id"`
+	Reference      string `json:"reference"`
+	Status         string `json:"status"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	Captured       int64  `json:"captured"`
+	Refunded       int64  `json:"refunded"`
+	FailureCode    string `json:"failure_code,omitempty"`
+	FailureMessage string `json:"failure_message,omitempty"`
+	// ChallengeURL is where the buyer completes 3-D Secure while the
+	// transaction requires action.
+	ChallengeURL string `json:"challenge_url,omitempty"`
+}
+
+// Provider authorizes card payments and moves the money. Amounts are in
+// minor units of the transaction's currency.
+type Provider interface {
+	// Authorize holds the payment's amount on the card.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error)
+	// Capture takes amount of an authorized transaction.
+	Capture(ctx context.Context, id string, amount int64) (*Transaction, error)
+	// Void releases a transaction that was not captured.
+	Void(ctx context.Context, id string) (*Transaction, error)
+	// Refund gives back amount of a captured transaction. A refund asked
+	// for again with the same key is only made once.
+	Refund(ctx context.Context, id string, amount int64, key string) (*Transaction, error)
+	Status(ctx context.Context, id string) (*Transaction, error)
+}
+
+func NewProvider(cfg config.PaymentConfig) (Provider, error) {
+	switch cfg.Backend {
+	case BackendMock:
+		return NewMock(""), nil
+	case BackendHTTP:
+		return NewHTTPProvider(cfg.URL, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown payment backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,162 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"time"
+)
+
+// stubTimeout is how long the stub keeps a request for CardTimeout open
+// before giving up on the client.
+const stubTimeout = 2 * time.Minute
+
+// NewStubServer serves mock's transactions over the REST API HTTPProvider
+// speaks, plus the 3-D Secure challenge pages its challenge URLs point at
+// (mounted under /challenge/). Requests for CardTimeout hang until the
+// client gives up. Clients swap cards for tokens at POST /v1/tokens, which
+// browsers may call from any origin. The events the mock recorded are
+// listed at GET /v1/events. cmd/paystub serves it on its own, and the
+// service serves it for its mock backend at PAYMENT_MOCK_ADDR.
+func NewStubServer(mock *Mock) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("OPTIONS /v1/tokens", func(w http.ResponseWriter, r *http.Request) {
+		allowOrigin(w)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /v1/tokens", func(w http.ResponseWriter, r *http.Request) {
+		allowOrigin(w)
+		var card Card
+		if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+			writeStubError(w, r, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+			return
+		}
+		token, err := mock.Tokenize(card)
+		if err != nil {
+			writeStubError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(token)
+	})
+
+	mux.HandleFunc("POST /v1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		var body authorizeBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeStubError(w, r, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+			return
+		}
+		txn, err := mock.Authorize(r.Context(), AuthorizeRequest{
+			Reference:      body.Reference,
+			Amount:         body.Amount,
+			Currency:       body.Currency,
+			Method:         body.Method,
+			Token:          body.Token,
+			IdempotencyKey: r.Header.Get("Idempotency-Key"),
+		})
+		writeStubResult(w, r, txn, err)
+	})
+	mux.HandleFunc("GET /v1/transactions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		txn, err := mock.Status(r.Context(), r.PathValue("id"))
+		writeStubResult(w, r, txn, err)
+	})
+	mux.HandleFunc("POST /v1/transactions/{id}/capture", func(w http.ResponseWriter, r *http.Request) {
+		var body amountBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeStubError(w, r, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+			return
+		}
+		txn, err := mock.Capture(r.Context(), r.PathValue("id"), body.Amount)
+		writeStubResult(w, r, txn, err)
+	})
+	mux.HandleFunc("POST /v1/transactions/{id}/void", func(w http.ResponseWriter, r *http.Request) {
+		txn, err := mock.Void(r.Context(), r.PathValue("id"))
+		writeStubResult(w, r, txn, err)
+	})
+	mux.HandleFunc("POST /v1/transactions/{id}/refund", func(w http.ResponseWriter, r *http.Request) {
+		var body amountBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeStubError(w, r, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+			return
+		}
+		txn, err := mock.Refund(r.Context(), r.PathValue("id"), body.Amount, r.Header.Get("Idempotency-Key"))
+		writeStubResult(w, r, txn, err)
+	})
+	// Chargebacks come from the buyer's bank; the stub lets you simulate one.
+	mux.HandleFunc("POST /v1/transactions/{id}/chargeback", func(w http.ResponseWriter, r *http.Request) {
+		txn, err := mock.Chargeback(r.PathValue("id"))
+		writeStubResult(w, r, txn, err)
+	})
+	mux.HandleFunc("GET /v1/events", func(w http.ResponseWriter, r *http.Request) {
+		events := mock.Events()
+		if id := r.URL.Query().Get("transaction"); id != "" {
+			filtered := make([]Event, 0, len(events))
+			for _, event := range events {
+				if event.Data.ID == id {
+					filtered = append(filtered, event)
+				}
+			}
+			events = filtered
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+	})
+
+	mux.HandleFunc("GET /challenge/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := html.EscapeString(r.PathValue("id"))
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<!doctype html>
+<title>3-D Secure</title>
+<p>Authenticate payment %s</p>
+<form method="post"><button name="result" value="pass">Complete</button> <button name="result" value="fail">Fail</button></form>
+`, id)
+	})
+	mux.HandleFunc("POST /challenge/{id}", func(w http.ResponseWriter, r *http.Request) {
+		txn, err := mock.CompleteChallenge(r.PathValue("id"), r.FormValue("result") != "fail")
+		if err != nil {
+			writeStubError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "Payment %s is %s; you can close this page.\n", txn.ID, txn.Status)
+	})
+
+	return mux
+}
+
+func allowOrigin(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+}
+
+func writeStubResult(w http.ResponseWriter, r *http.Request, txn *Transaction, err error) {
+	if err != nil {
+		writeStubError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(txn)
+}
+
+func writeStubError(w http.ResponseWriter, r *http.Request, err error) {
+	code, status := "internal", http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrTimeout):
+		ctx, cancel := context.WithTimeout(r.Context(), stubTimeout)
+		defer cancel()
+		<-ctx.Done()
+		code, status = "timeout", http.StatusGatewayTimeout
+	case errors.Is(err, ErrInvalidRequest):
+		code, status = "invalid_request", http.StatusBadRequest
+	case errors.Is(err, ErrNotFound):
+		code, status = "not_found", http.StatusNotFound
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiError{Code: code, Message: err.Error()})
+}
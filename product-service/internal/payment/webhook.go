@@ -0,0 +1,75 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries a webhook's signature: "t=<unix seconds>,v1=<hex
+// HMAC-SHA256 of "<t>.<body>" keyed with the webhook secret>".
+const SignatureHeader = "Payment-Signature"
+
+// Types of the events a provider sends when a transaction changes.
+const (
+	EventAuthorized  = "transaction.authorized"
+	EventDeclined    = "transaction.declined"
+	EventCaptured    = "transaction.captured"
+	EventVoided      = "transaction.voided"
+	EventRefunded    = "transaction.refunded"
+	EventChargedBack = "transaction.charged_back"
+)
+
+// Event tells that a transaction changed. Data is the transaction as it was
+// right after the change.
+type Event struct {
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	Created int64       `json:"created"`
+	Data    Transaction `json:"data"`
+}
+
+// Sign returns the SignatureHeader value for a webhook body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// DeliverWebhook posts event to url signed with secret as of signedAt, and
+// fails unless the receiver answers with a 2xx status.
+func DeliverWebhook(ctx context.Context, client *http.Client, url, secret string, event Event, signedAt time.Time) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %v", event.ID, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(secret, signedAt, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver event %s: %v", event.ID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		answer, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("event %s was refused with %s: %s", event.ID, resp.Status, strings.TrimSpace(string(answer)))
+	}
+	return nil
+}
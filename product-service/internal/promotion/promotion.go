@@ -0,0 +1,238 @@
+// Package promotion works out the discounts an order gets from the
+// promotions that apply to it. It knows nothing about storage or
+// currencies: every amount is in minor units of the order currency.
+package promotion
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	KindPercentage   = "percentage"
+	KindFixed        = "fixed"
+	KindFreeShipping = "free_shipping"
+	KindBuyXGetY     = "buy_x_get_y"
+)
+
+// Reasons an order does not qualify for a rule, phrased to follow the
+// rule's name or code.
+var (
+	ErrNoMatchingItems = errors.New("applies to none of the items")
+	ErrBelowMinimum    = errors.New("requires a higher subtotal")
+	ErrNotEnoughItems  = errors.New("requires more of one item")
+	ErrNoShipping      = errors.New("only takes off shipping, which the order has none of")
+)
+
+// kindOrder is the order discounts are taken in when they stack: free
+// items first, then percentages of what is left, then fixed amounts, and
+// shipping last since it does not depend on the items.
+var kindOrder = map[string]int{
+	KindBuyXGetY:     0,
+	KindPercentage:   1,
+	KindFixed:        2,
+	KindFreeShipping: 3,
+}
+
+// Rule is a promotion with its amounts converted to the order currency.
+type Rule struct {
+	ID        string
+	Name      string
+	Code      string
+	Kind      string
+	Percent   int64
+	Amount    int64
+	Buy       uint64
+	Get       uint64
+	ArtisanID string
+	// Categories holds the category the rule is limited to and every
+	// category below it; nil means any category.
+	Categories  map[string]bool
+	MinSubtotal int64
+	Stackable   bool
+}
+
+// Line is one product on the order.
+type Line struct {
+	ProductID  string
+	ArtisanID  string
+	CategoryID string
+	Quantity   uint64
+	UnitPrice  int64
+}
+
+// Total is the line total. Callers check totals for overflow before asking
+// for discounts.
+func (l Line) Total() int64 {
+	return l.UnitPrice * int64(l.Quantity)
+}
+
+// Discount is the amount one rule takes off the order. Lines holds what it
+// takes off each line, in the order the lines were given, and Shipping what
+// it takes off shipping; together they make up Amount.
+type Discount struct {
+	Rule     *Rule
+	Amount   int64
+	Lines    []int64
+	Shipping int64
+}
+
+// Result is the best set of discounts for an order.
+type Result struct {
+	Discounts []Discount
+	Total     int64
+	// Skipped are rules the order qualified for that were left out because
+	// a combination without them is worth more.
+	Skipped []*Rule
+}
+
+// Covers reports whether the rule applies to a line.
+func (r *Rule) Covers(line Line) bool {
+	if r.ArtisanID != "" && r.ArtisanID != line.ArtisanID {
+		return false
+	}
+	if r.Categories != nil && !r.Categories[line.CategoryID] {
+		return false
+	}
+	return true
+}
+
+// Check reports why an order does not qualify for the rule, or nil when it
+// does. The minimum subtotal only counts the lines the rule covers.
+func (r *Rule) Check(lines []Line, shipping int64) error {
+	var (
+		subtotal int64
+		covered  bool
+		enough   bool
+	)
+	for _, line := range lines {
+		if !r.Covers(line) {
+			continue
+		}
+		covered = true
+		subtotal += line.Total()
+		if r.Buy+r.Get > 0 && line.Quantity >= r.Buy+r.Get {
+			enough = true
+		}
+	}
+	switch {
+	case !covered:
+		return ErrNoMatchingItems
+	case subtotal < r.MinSubtotal:
+		return ErrBelowMinimum
+	case r.Kind == KindBuyXGetY && !enough:
+		return ErrNotEnoughItems
+	case r.Kind == KindFreeShipping && shipping == 0:
+		return ErrNoShipping
+	}
+	return nil
+}
+
+// Best picks the discounts for an order. Stackable rules are combined with
+// each other; a rule that does not stack is only used on its own. Whichever
+// of those choices takes the most off wins, and no choice can take off more
+// than the items and shipping cost.
+func Best(rules []*Rule, lines []Line, shipping int64) Result {
+	var eligible []*Rule
+	for _, rule := range rules {
+		if rule.Check(lines, shipping) == nil {
+			eligible = append(eligible, rule)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return kindOrder[eligible[i].Kind] < kindOrder[eligible[j].Kind]
+	})
+
+	var (
+		stackable []*Rule
+		options   [][]*Rule
+	)
+	for _, rule := range eligible {
+		if rule.Stackable {
+			stackable = append(stackable, rule)
+		} else {
+			options = append(options, []*Rule{rule})
+		}
+	}
+	if len(stackable) > 0 {
+		options = append([][]*Rule{stackable}, options...)
+	}
+
+	var best Result
+	for _, option := range options {
+		result := apply(option, lines, shipping)
+		if result.Total > best.Total {
+			best = result
+		}
+	}
+
+	used := make(map[*Rule]bool, len(best.Discounts))
+	for _, discount := range best.Discounts {
+		used[discount.Rule] = true
+	}
+	for _, rule := range eligible {
+		if !used[rule] {
+			best.Skipped = append(best.Skipped, rule)
+		}
+	}
+	return best
+}
+
+// apply takes the rules off the order one after the other, each from what
+// the previous ones left.
+func apply(rules []*Rule, lines []Line, shipping int64) Result {
+	remaining := make([]int64, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Total()
+	}
+
+	var result Result
+	for _, rule := range rules {
+		var (
+			amount int64
+			off    = make([]int64, len(lines))
+			freed  int64
+		)
+		switch rule.Kind {
+		case KindBuyXGetY:
+			for i, line := range lines {
+				if !rule.Covers(line) || rule.Buy+rule.Get == 0 {
+					continue
+				}
+				free := line.Quantity / (rule.Buy + rule.Get) * rule.Get
+				off[i] = min(line.UnitPrice*int64(free), remaining[i])
+				remaining[i] -= off[i]
+				amount += off[i]
+			}
+		case KindPercentage:
+			for i, line := range lines {
+				if !rule.Covers(line) {
+					continue
+				}
+				off[i] = remaining[i]/100*rule.Percent + remaining[i]%100*rule.Percent/100
+				remaining[i] -= off[i]
+				amount += off[i]
+			}
+		case KindFixed:
+			left := rule.Amount
+			for i, line := range lines {
+				if !rule.Covers(line) || left == 0 {
+					continue
+				}
+				off[i] = min(left, remaining[i])
+				remaining[i] -= off[i]
+				left -= off[i]
+				amount += off[i]
+			}
+		case KindFreeShipping:
+			amount = shipping
+			freed = shipping
+			shipping = 0
+		}
+		if amount > 0 {
+			result.Discounts = append(result.Discounts, Discount{Rule: rule, Amount: amount, Lines: off, Shipping: freed})
+			result.Total += amount
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+// Package redact keeps card numbers out of logs and the database. Anything
+// that looks like a primary account number (13 to 19 digits, written
+// together or grouped as on a card, passing the Luhn check) is masked down
+// to its last four digits.
+//
+// The gateway keeps a copy of the matching in its own redact package; this
+// one is the source of truth.
+package redact
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// panLike matches 13 to 19 digits written together or in the groupings
+// printed on cards (4-4-4-4 up to 4-4-4-7, and 4-6-5). Loose groupings are
+// left alone so that ids such as UUIDs are never taken for card numbers.
+var panLike = regexp.MustCompile(`\b(?:\d{13,19}|\d{4}(?:[ -]\d{4}){2}[ -]\d{1,7}|\d{4}[ -]\d{6}[ -]\d{4,5})\b`)
+
+// PAN masks the card numbers in s.
+func PAN(s string) string {
+	if !panLike.MatchString(s) {
+		return s
+	}
+	return panLike.ReplaceAllStringFunc(s, maskPAN)
+}
+
+// maskPAN masks a run of digits when it starts with a card number. Runs that
+// continue past the card number, such as one followed by its expiry date,
+// are masked up to where the card number ends.
+func maskPAN(match string) string {
+	digits := make([]byte, 0, len(match))
+	ends := make([]int, 0, len(match))
+	for i := 0; i < len(match); i++ {
+		if match[i] >= '0' && match[i] <= '9' {
+			digits = append(digits, match[i])
+			ends = append(ends, i+1)
+		}
+	}
+	for n := len(digits); n >= 13; n-- {
+		if luhn(digits[:n]) {
+			return strings.Repeat("*", n-4) + string(digits[n-4:n]) + match[ends[n-1]:]
+		}
+	}
+	return match
+}
+
+func luhn(digits []byte) bool {
+	var sum int
+	for i := range digits {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter masks card numbers in everything written to w. Each write is
+// redacted on its own, which suits loggers that write a line at a time.
+func NewWriter(w io.Writer) io.Writer {
+	return writer{w: w}
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, PAN(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/redact"
 	"armiya/equipment-service/internal/storage"
 	"context"
 	"log"
@@ -19,7 +20,7 @@ type (
 func New(service storage.Product) *Productservice {
 	return &Productservice{
 		productService: service,
-		logger:         log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile),
+		logger:         log.New(redact.NewWriter(os.Stdout), "INFO: ", log.Ldate|log.Ltime|log.Lshortfile),
 	}
 }
 
@@ -43,6 +44,21 @@ func (s *Productservice) GetProduct(ctx context.Context, req *genprotos.GetProdu
 	return s.productService.GetProduct(ctx, req)
 }
 
+func (s *Productservice) ChangeProductStatus(ctx context.Context, req *genprotos.ChangeProductStatusRequest) (*genprotos.ProductStatusResponse, error) {
+	s.logger.Println("Change Product Status Request")
+	return s.productService.ChangeProductStatus(ctx, req)
+}
+
+func (s *Productservice) ListProductRevisions(ctx context.Context, req *genprotos.ListProductRevisionsRequest) (*genprotos.ListProductRevisionsResponse, error) {
+	s.logger.Println("List Product Revisions Request")
+	return s.productService.ListProductRevisions(ctx, req)
+}
+
+func (s *Productservice) RevertProduct(ctx context.Context, req *genprotos.RevertProductRequest) (*genprotos.ProductRevision, error) {
+	s.logger.Println("Revert Product Request")
+	return s.productService.RevertProduct(ctx, req)
+}
+
 func (s *Productservice) GetAllProducts(ctx context.Context, req *genprotos.GetAllProductsRequest) (*genprotos.GetAllProductsResponse, error) {
 	s.logger.Println("Get All Products Request")
 	return s.productService.GetAllProducts(ctx, req)
@@ -99,6 +115,46 @@ func (s *Productservice) AddCategory(ctx context.Context, req *genprotos.AddCate
 	return s.productService.AddCategory(ctx, req)
 }
 
+func (s *Productservice) GetCategory(ctx context.Context, req *genprotos.GetCategoryRequest) (*genprotos.Category, error) {
+	s.logger.Println("Get Category Request")
+	return s.productService.GetCategory(ctx, req)
+}
+
+func (s *Productservice) ListCategories(ctx context.Context, req *genprotos.ListCategoriesRequest) (*genprotos.ListCategoriesResponse, error) {
+	s.logger.Println("List Categories Request")
+	return s.productService.ListCategories(ctx, req)
+}
+
+func (s *Productservice) GetCategoryTree(ctx context.Context, req *genprotos.GetCategoryTreeRequest) (*genprotos.GetCategoryTreeResponse, error) {
+	s.logger.Println("Get Category Tree Request")
+	return s.productService.GetCategoryTree(ctx, req)
+}
+
+func (s *Productservice) UpdateCategory(ctx context.Context, req *genprotos.UpdateCategoryRequest) (*genprotos.Category, error) {
+	s.logger.Println("Update Category Request")
+	return s.productService.UpdateCategory(ctx, req)
+}
+
+func (s *Productservice) DeleteCategory(ctx context.Context, req *genprotos.DeleteCategoryRequest) (*genprotos.Message, error) {
+	s.logger.Println("Delete Category Request")
+	return s.productService.DeleteCategory(ctx, req)
+}
+
+func (s *Productservice) SetCategoryAttribute(ctx context.Context, req *genprotos.AttributeDefinition) (*genprotos.AttributeDefinition, error) {
+	s.logger.Println("Set Category Attribute Request")
+	return s.productService.SetCategoryAttribute(ctx, req)
+}
+
+func (s *Productservice) ListCategoryAttributes(ctx context.Context, req *genprotos.ListCategoryAttributesRequest) (*genprotos.ListCategoryAttributesResponse, error) {
+	s.logger.Println("List Category Attributes Request")
+	return s.productService.ListCategoryAttributes(ctx, req)
+}
+
+func (s *Productservice) DeleteCategoryAttribute(ctx context.Context, req *genprotos.DeleteCategoryAttributeRequest) (*genprotos.Message, error) {
+	s.logger.Println("Delete Category Attribute Request")
+	return s.productService.DeleteCategoryAttribute(ctx, req)
+}
+
 func (s *Productservice) GetStatistics(ctx context.Context, req *genprotos.GetStatisticsRequest) (*genprotos.GetStatisticsResponse, error) {
 	return s.productService.GetStatistics(ctx, req)
 }
@@ -118,3 +174,273 @@ func (s *Productservice) GetRecommendations(ctx context.Context, req *genprotos.
 func (s *Productservice) CheckPaymentStatus(ctx context.Context, req *genprotos.CheckPaymentStatusRequest) (*genprotos.CheckPaymentStatusResponse, error) {
 	return s.productService.CheckPaymentStatus(ctx, req)
 }
+
+func (s *Productservice) ConfirmPayment(ctx context.Context, req *genprotos.ConfirmPaymentRequest) (*genprotos.PayResponse, error) {
+	s.logger.Println("Confirm Payment Request")
+	return s.productService.ConfirmPayment(ctx, req)
+}
+
+func (s *Productservice) HandlePaymentEvent(ctx context.Context, req *genprotos.PaymentEvent) (*genprotos.PaymentEventResponse, error) {
+	s.logger.Println("Handle Payment Event Request")
+	return s.productService.HandlePaymentEvent(ctx, req)
+}
+
+func (s *Productservice) UploadProductImage(ctx context.Context, req *genprotos.UploadProductImageRequest) (*genprotos.ProductImage, error) {
+	s.logger.Println("Upload Product Image Request")
+	return s.productService.UploadProductImage(ctx, req)
+}
+
+func (s *Productservice) ListProductImages(ctx context.Context, req *genprotos.ListProductImagesRequest) (*genprotos.ListProductImagesResponse, error) {
+	s.logger.Println("List Product Images Request")
+	return s.productService.ListProductImages(ctx, req)
+}
+
+func (s *Productservice) ReorderProductImages(ctx context.Context, req *genprotos.ReorderProductImagesRequest) (*genprotos.ListProductImagesResponse, error) {
+	s.logger.Println("Reorder Product Images Request")
+	return s.productService.ReorderProductImages(ctx, req)
+}
+
+func (s *Productservice) SetPrimaryProductImage(ctx context.Context, req *genprotos.SetPrimaryProductImageRequest) (*genprotos.ProductImage, error) {
+	s.logger.Println("Set Primary Product Image Request")
+	return s.productService.SetPrimaryProductImage(ctx, req)
+}
+
+func (s *Productservice) DeleteProductImage(ctx context.Context, req *genprotos.DeleteProductImageRequest) (*genprotos.Message, error) {
+	s.logger.Println("Delete Product Image Request")
+	return s.productService.DeleteProductImage(ctx, req)
+}
+
+func (s *Productservice) SetExchangeRate(ctx context.Context, req *genprotos.SetExchangeRateRequest) (*genprotos.ExchangeRate, error) {
+	s.logger.Println("Set Exchange Rate Request")
+	return s.productService.SetExchangeRate(ctx, req)
+}
+
+func (s *Productservice) ListExchangeRates(ctx context.Context, req *genprotos.ListExchangeRatesRequest) (*genprotos.ListExchangeRatesResponse, error) {
+	s.logger.Println("List Exchange Rates Request")
+	return s.productService.ListExchangeRates(ctx, req)
+}
+
+func (s *Productservice) DeleteExchangeRate(ctx context.Context, req *genprotos.DeleteExchangeRateRequest) (*genprotos.Message, error) {
+	s.logger.Println("Delete Exchange Rate Request")
+	return s.productService.DeleteExchangeRate(ctx, req)
+}
+
+func (s *Productservice) ImportProducts(ctx context.Context, req *genprotos.ImportProductsRequest) (*genprotos.ProductImport, error) {
+	s.logger.Println("Import Products Request")
+	return s.productService.ImportProducts(ctx, req)
+}
+
+func (s *Productservice) GetProductImport(ctx context.Context, req *genprotos.GetProductImportRequest) (*genprotos.ProductImport, error) {
+	s.logger.Println("Get Product Import Request")
+	return s.productService.GetProductImport(ctx, req)
+}
+
+func (s *Productservice) ListProductImports(ctx context.Context, req *genprotos.ListProductImportsRequest) (*genprotos.ListProductImportsResponse, error) {
+	s.logger.Println("List Product Imports Request")
+	return s.productService.ListProductImports(ctx, req)
+}
+
+func (s *Productservice) ExportProducts(req *genprotos.ExportProductsRequest, stream genprotos.ProductService_ExportProductsServer) error {
+	s.logger.Println("Export Products Request")
+	return s.productService.ExportProducts(req, stream)
+}
+
+func (s *Productservice) ListStockMovements(ctx context.Context, req *genprotos.ListStockMovementsRequest) (*genprotos.ListStockMovementsResponse, error) {
+	s.logger.Println("List Stock Movements Request")
+	return s.productService.ListStockMovements(ctx, req)
+}
+
+func (s *Productservice) AdjustStock(ctx context.Context, req *genprotos.AdjustStockRequest) (*genprotos.StockMovement, error) {
+	s.logger.Println("Adjust Stock Request")
+	return s.productService.AdjustStock(ctx, req)
+}
+
+func (s *Productservice) SetLowStockThreshold(ctx context.Context, req *genprotos.SetLowStockThresholdRequest) (*genprotos.StockLevel, error) {
+	s.logger.Println("Set Low Stock Threshold Request")
+	return s.productService.SetLowStockThreshold(ctx, req)
+}
+
+func (s *Productservice) ListLowStockAlerts(ctx context.Context, req *genprotos.ListLowStockAlertsRequest) (*genprotos.ListLowStockAlertsResponse, error) {
+	s.logger.Println("List Low Stock Alerts Request")
+	return s.productService.ListLowStockAlerts(ctx, req)
+}
+
+func (s *Productservice) ReconcileStock(ctx context.Context, req *genprotos.ReconcileStockRequest) (*genprotos.ReconcileStockResponse, error) {
+	s.logger.Println("Reconcile Stock Request")
+	return s.productService.ReconcileStock(ctx, req)
+}
+
+func (s *Productservice) AddCartItem(ctx context.Context, req *genprotos.CartItemRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Add Cart Item Request")
+	return s.productService.AddCartItem(ctx, req)
+}
+
+func (s *Productservice) UpdateCartItem(ctx context.Context, req *genprotos.CartItemRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Update Cart Item Request")
+	return s.productService.UpdateCartItem(ctx, req)
+}
+
+func (s *Productservice) RemoveCartItem(ctx context.Context, req *genprotos.RemoveCartItemRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Remove Cart Item Request")
+	return s.productService.RemoveCartItem(ctx, req)
+}
+
+func (s *Productservice) GetCart(ctx context.Context, req *genprotos.CartRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Get Cart Request")
+	return s.productService.GetCart(ctx, req)
+}
+
+func (s *Productservice) ClearCart(ctx context.Context, req *genprotos.CartRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Clear Cart Request")
+	return s.productService.ClearCart(ctx, req)
+}
+
+func (s *Productservice) MergeCart(ctx context.Context, req *genprotos.MergeCartRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Merge Cart Request")
+	return s.productService.MergeCart(ctx, req)
+}
+
+func (s *Productservice) CheckoutCart(ctx context.Context, req *genprotos.CheckoutCartRequest) (*genprotos.OrderResponse, error) {
+	s.logger.Println("Checkout Cart Request")
+	return s.productService.CheckoutCart(ctx, req)
+}
+
+func (s *Productservice) CreateWishlist(ctx context.Context, req *genprotos.CreateWishlistRequest) (*genprotos.Wishlist, error) {
+	s.logger.Println("Create Wishlist Request")
+	return s.productService.CreateWishlist(ctx, req)
+}
+
+func (s *Productservice) ListWishlists(ctx context.Context, req *genprotos.ListWishlistsRequest) (*genprotos.ListWishlistsResponse, error) {
+	s.logger.Println("List Wishlists Request")
+	return s.productService.ListWishlists(ctx, req)
+}
+
+func (s *Productservice) GetWishlist(ctx context.Context, req *genprotos.GetWishlistRequest) (*genprotos.Wishlist, error) {
+	s.logger.Println("Get Wishlist Request")
+	return s.productService.GetWishlist(ctx, req)
+}
+
+func (s *Productservice) DeleteWishlist(ctx context.Context, req *genprotos.DeleteWishlistRequest) (*genprotos.Message, error) {
+	s.logger.Println("Delete Wishlist Request")
+	return s.productService.DeleteWishlist(ctx, req)
+}
+
+func (s *Productservice) AddWishlistItem(ctx context.Context, req *genprotos.WishlistItemRequest) (*genprotos.Wishlist, error) {
+	s.logger.Println("Add Wishlist Item Request")
+	return s.productService.AddWishlistItem(ctx, req)
+}
+
+func (s *Productservice) RemoveWishlistItem(ctx context.Context, req *genprotos.WishlistItemRequest) (*genprotos.Wishlist, error) {
+	s.logger.Println("Remove Wishlist Item Request")
+	return s.productService.RemoveWishlistItem(ctx, req)
+}
+
+func (s *Productservice) ShareWishlist(ctx context.Context, req *genprotos.ShareWishlistRequest) (*genprotos.Wishlist, error) {
+	s.logger.Println("Share Wishlist Request")
+	return s.productService.ShareWishlist(ctx, req)
+}
+
+func (s *Productservice) MoveWishlistItemToCart(ctx context.Context, req *genprotos.MoveWishlistItemRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Move Wishlist Item To Cart Request")
+	return s.productService.MoveWishlistItemToCart(ctx, req)
+}
+
+func (s *Productservice) GetProductSaveCounts(ctx context.Context, req *genprotos.GetProductSaveCountsRequest) (*genprotos.GetProductSaveCountsResponse, error) {
+	s.logger.Println("Get Product Save Counts Request")
+	return s.productService.GetProductSaveCounts(ctx, req)
+}
+
+func (s *Productservice) ListNotifications(ctx context.Context, req *genprotos.ListNotificationsRequest) (*genprotos.ListNotificationsResponse, error) {
+	s.logger.Println("List Notifications Request")
+	return s.productService.ListNotifications(ctx, req)
+}
+
+func (s *Productservice) MarkNotificationsRead(ctx context.Context, req *genprotos.MarkNotificationsReadRequest) (*genprotos.Message, error) {
+	s.logger.Println("Mark Notifications Read Request")
+	return s.productService.MarkNotificationsRead(ctx, req)
+}
+
+func (s *Productservice) CreatePromotion(ctx context.Context, req *genprotos.CreatePromotionRequest) (*genprotos.Promotion, error) {
+	s.logger.Println("Create Promotion Request")
+	return s.productService.CreatePromotion(ctx, req)
+}
+
+func (s *Productservice) ListPromotions(ctx context.Context, req *genprotos.ListPromotionsRequest) (*genprotos.ListPromotionsResponse, error) {
+	s.logger.Println("List Promotions Request")
+	return s.productService.ListPromotions(ctx, req)
+}
+
+func (s *Productservice) SetPromotionActive(ctx context.Context, req *genprotos.SetPromotionActiveRequest) (*genprotos.Promotion, error) {
+	s.logger.Println("Set Promotion Active Request")
+	return s.productService.SetPromotionActive(ctx, req)
+}
+
+func (s *Productservice) ApplyCartCoupon(ctx context.Context, req *genprotos.CartCouponRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Apply Cart Coupon Request")
+	return s.productService.ApplyCartCoupon(ctx, req)
+}
+
+func (s *Productservice) RemoveCartCoupon(ctx context.Context, req *genprotos.CartCouponRequest) (*genprotos.Cart, error) {
+	s.logger.Println("Remove Cart Coupon Request")
+	return s.productService.RemoveCartCoupon(ctx, req)
+}
+
+func (s *Productservice) GetOrderTimeline(ctx context.Context, req *genprotos.GetOrderTimelineRequest) (*genprotos.GetOrderTimelineResponse, error) {
+	s.logger.Println("Get Order Timeline Request")
+	return s.productService.GetOrderTimeline(ctx, req)
+}
+
+func (s *Productservice) ListArtisanOrders(ctx context.Context, req *genprotos.ListArtisanOrdersRequest) (*genprotos.ListArtisanOrdersResponse, error) {
+	s.logger.Println("List Artisan Orders Request")
+	return s.productService.ListArtisanOrders(ctx, req)
+}
+
+func (s *Productservice) BulkUpdateArtisanOrders(ctx context.Context, req *genprotos.BulkUpdateArtisanOrdersRequest) (*genprotos.BulkUpdateArtisanOrdersResponse, error) {
+	s.logger.Println("Bulk Update Artisan Orders Request")
+	return s.productService.BulkUpdateArtisanOrders(ctx, req)
+}
+
+func (s *Productservice) GetPackingSlips(ctx context.Context, req *genprotos.GetPackingSlipsRequest) (*genprotos.GetPackingSlipsResponse, error) {
+	s.logger.Println("Get Packing Slips Request")
+	return s.productService.GetPackingSlips(ctx, req)
+}
+
+func (s *Productservice) ListMyOrders(ctx context.Context, req *genprotos.ListMyOrdersRequest) (*genprotos.ListMyOrdersResponse, error) {
+	s.logger.Println("List My Orders Request")
+	return s.productService.ListMyOrders(ctx, req)
+}
+
+func (s *Productservice) RequestReturn(ctx context.Context, req *genprotos.RequestReturnRequest) (*genprotos.Return, error) {
+	s.logger.Println("Request Return Request")
+	return s.productService.RequestReturn(ctx, req)
+}
+
+func (s *Productservice) GetReturn(ctx context.Context, req *genprotos.GetReturnRequest) (*genprotos.Return, error) {
+	s.logger.Println("Get Return Request")
+	return s.productService.GetReturn(ctx, req)
+}
+
+func (s *Productservice) ListReturns(ctx context.Context, req *genprotos.ListReturnsRequest) (*genprotos.ListReturnsResponse, error) {
+	s.logger.Println("List Returns Request")
+	return s.productService.ListReturns(ctx, req)
+}
+
+func (s *Productservice) ReviewReturn(ctx context.Context, req *genprotos.ReviewReturnRequest) (*genprotos.Return, error) {
+	s.logger.Println("Review Return Request")
+	return s.productService.ReviewReturn(ctx, req)
+}
+
+func (s *Productservice) ShipReturn(ctx context.Context, req *genprotos.ShipReturnRequest) (*genprotos.Return, error) {
+	s.logger.Println("Ship Return Request")
+	return s.productService.ShipReturn(ctx, req)
+}
+
+func (s *Productservice) ReceiveReturn(ctx context.Context, req *genprotos.ReceiveReturnRequest) (*genprotos.Return, error) {
+	s.logger.Println("Receive Return Request")
+	return s.productService.ReceiveReturn(ctx, req)
+}
+
+func (s *Productservice) CancelReturn(ctx context.Context, req *genprotos.CancelReturnRequest) (*genprotos.Return, error) {
+	s.logger.Println("Cancel Return Request")
+	return s.productService.CancelReturn(ctx, req)
+}
@@ -0,0 +1,388 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+// maxBulkOrders caps how many parts of orders one bulk request may touch.
+const maxBulkOrders = 100
+
+// artisanOrderSorts maps the sort keys of ListArtisanOrders to columns.
+var artisanOrderSorts = map[string]string{
+	"created_at": "o.created_at",
+	"updated_at": "f.updated_at",
+	"status":     "f.status",
+	"payout":     "f.payout_amount",
+}
+
+func (p *Product) ListArtisanOrders(ctx context.Context, req *genprotos.ListArtisanOrdersRequest) (*genprotos.ListArtisanOrdersResponse, error) {
+	if _, err := uuid.Parse(req.ArtisanId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+	}
+	for _, wanted := range req.Statuses {
+		if _, ok := orderTransitions[wanted]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown order status %q", wanted)
+		}
+	}
+	from, err := parseDateBound("from", req.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDateBound("to", req.To, true)
+	if err != nil {
+		return nil, err
+	}
+
+	sortKey, direction := strings.TrimPrefix(req.Sort, "-"), "ASC"
+	if strings.HasPrefix(req.Sort, "-") {
+		direction = "DESC"
+	}
+	if req.Sort == "" {
+		sortKey, direction = "created_at", "DESC"
+	}
+	column, ok := artisanOrderSorts[sortKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot sort by %q; use created_at, updated_at, status or payout", sortKey)
+	}
+
+	query := p.queryBuilder.
+		Select(fulfillmentColumns, "o.status", "COALESCE(o.user_id::text, '')", "o.shipping_address", "o.created_at").
+		From("order_fulfillments f").
+		Join("orders o ON o.id = f.order_id").
+		Where("f.artisan_id = ?", req.ArtisanId).
+		OrderBy(column+" "+direction, "f.id")
+	count := p.queryBuilder.
+		Select("COUNT(*)").
+		From("order_fulfillments f").
+		Join("orders o ON o.id = f.order_id").
+		Where("f.artisan_id = ?", req.ArtisanId)
+	if len(req.Statuses) > 0 {
+		query = query.Where("f.status = ANY(?)", pq.Array(req.Statuses))
+		count = count.Where("f.status = ANY(?)", pq.Array(req.Statuses))
+	}
+	if from != nil {
+		query = query.Where("o.created_at >= ?", *from)
+		count = count.Where("o.created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("o.created_at < ?", *to)
+		count = count.Where("o.created_at < ?", *to)
+	}
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artisan orders: %v", err)
+	}
+	defer rows.Close()
+
+	response := genprotos.ListArtisanOrdersResponse{
+		Orders: make([]*genprotos.ArtisanOrder, 0),
+		Page:   req.Page,
+		Limit:  req.Limit,
+	}
+	for rows.Next() {
+		order, err := scanArtisanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan artisan order row: %v", err)
+		}
+		response.Orders = append(response.Orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over artisan order rows: %v", err)
+	}
+	rows.Close()
+
+	// Only the artisan's own lines of each order are shown.
+	for _, order := range response.Orders {
+		lines, err := p.orderLines(ctx, p.db, order.Fulfillment.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		order.Lines = make([]*genprotos.OrderLine, 0, len(lines))
+		order.Fulfillment.Items = make([]*genprotos.Item, 0, len(lines))
+		for _, line := range lines {
+			if line.FulfillmentId != order.Fulfillment.Id {
+				continue
+			}
+			order.Lines = append(order.Lines, line)
+			order.Fulfillment.Items = append(order.Fulfillment.Items, &genprotos.Item{ProductId: line.ProductId, Quantity: line.Quantity})
+		}
+	}
+
+	sqlQuery, args, err = count.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if err := p.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&response.Total); err != nil {
+		return nil, fmt.Errorf("failed to count artisan orders: %v", err)
+	}
+
+	return &response, nil
+}
+
+func (p *Product) BulkUpdateArtisanOrders(ctx context.Context, req *genprotos.BulkUpdateArtisanOrdersRequest) (*genprotos.BulkUpdateArtisanOrdersResponse, error) {
+	actor, err := requestActor(req.ArtisanId, roleArtisan, "")
+	if err != nil {
+		return nil, err
+	}
+	target := strings.ToLower(strings.TrimSpace(req.Status))
+	if _, ok := orderTransitions[target]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown order status %q", req.Status)
+	}
+	if target == orderPaid {
+		return nil, status.Error(codes.InvalidArgument, "orders become paid by paying for them")
+	}
+	ids, err := fulfillmentIDs(req.FulfillmentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	response := genprotos.BulkUpdateArtisanOrdersResponse{Results: make([]*genprotos.BulkOrderResult, 0, len(ids))}
+	for _, id := range ids {
+		result := &genprotos.BulkOrderResult{FulfillmentId: id}
+		if err := p.moveArtisanFulfillment(ctx, result, target, actor, req.Note); err != nil {
+			result.Error = status.Convert(err).Message()
+			response.Failed++
+		} else {
+			response.Updated++
+		}
+		response.Results = append(response.Results, result)
+	}
+	return &response, nil
+}
+
+// moveArtisanFulfillment moves one of the actor's parts of an order in a
+// transaction of its own and fills in result.
+func (p *Product) moveArtisanFulfillment(ctx context.Context, result *genprotos.BulkOrderResult, target string, actor orderActor, note string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, "SELECT order_id FROM order_fulfillments WHERE id = $1 AND artisan_id = $2", result.FulfillmentId, actor.id).Scan(&result.OrderId)
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "fulfillment %s not found", result.FulfillmentId)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch fulfillment: %v", err)
+	}
+
+	move, err := p.transitionFulfillment(ctx, tx, result.OrderId, result.FulfillmentId, target, actor, note)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	p.queuePayments()
+	result.Status = target
+	result.OrderStatus = move.status
+	return nil
+}
+
+func (p *Product) GetPackingSlips(ctx context.Context, req *genprotos.GetPackingSlipsRequest) (*genprotos.GetPackingSlipsResponse, error) {
+	if _, err := uuid.Parse(req.ArtisanId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+	}
+	ids, err := fulfillmentIDs(req.FulfillmentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT f.id, f.order_id, COALESCE(f.carrier, ''), COALESCE(f.tracking_number, ''), o.shipping_address, o.created_at
+		FROM order_fulfillments f
+		JOIN orders o ON o.id = f.order_id
+		WHERE f.id = ANY($1) AND f.artisan_id = $2
+	`, pq.Array(ids), req.ArtisanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fulfillments: %v", err)
+	}
+	defer rows.Close()
+
+	slips := make(map[string]*genprotos.PackingSlip, len(ids))
+	for rows.Next() {
+		var (
+			slip     = genprotos.PackingSlip{ShipTo: &genprotos.ShippingAddress{}}
+			address  []byte
+			placedAt time.Time
+		)
+		if err := rows.Scan(&slip.FulfillmentId, &slip.OrderId, &slip.Carrier, &slip.TrackingNumber, &address, &placedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fulfillment row: %v", err)
+		}
+		if err := json.Unmarshal(address, slip.ShipTo); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal shipping address: %v", err)
+		}
+		slip.PlacedAt = placedAt.Format(time.RFC3339)
+		slips[slip.FulfillmentId] = &slip
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over fulfillment rows: %v", err)
+	}
+	rows.Close()
+
+	response := genprotos.GetPackingSlipsResponse{Slips: make([]*genprotos.PackingSlip, 0, len(ids))}
+	for _, id := range ids {
+		slip, ok := slips[id]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "fulfillment %s not found", id)
+		}
+		if slip.Lines, err = packingSlipLines(ctx, p.db, id); err != nil {
+			return nil, err
+		}
+		slip.Text = renderPackingSlip(slip)
+		response.Slips = append(response.Slips, slip)
+	}
+	return &response, nil
+}
+
+// packingSlipLines lists what goes in a parcel, one line per product.
+func packingSlipLines(ctx context.Context, q queryer, fulfillmentID string) ([]*genprotos.PackingSlipLine, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT oi.product_id, COALESCE(p.sku, ''), COALESCE(p.name, ''), SUM(oi.quantity)
+		FROM order_items oi
+		LEFT JOIN products p ON p.id = oi.product_id
+		WHERE oi.fulfillment_id = $1
+		GROUP BY oi.product_id, p.sku, p.name
+		ORDER BY p.name, oi.product_id
+	`, fulfillmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch packing slip lines: %v", err)
+	}
+	defer rows.Close()
+
+	lines := make([]*genprotos.PackingSlipLine, 0)
+	for rows.Next() {
+		var line genprotos.PackingSlipLine
+		if err := rows.Scan(&line.ProductId, &line.Sku, &line.Name, &line.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan packing slip line: %v", err)
+		}
+		lines = append(lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over packing slip lines: %v", err)
+	}
+	return lines, nil
+}
+
+// renderPackingSlip lays a packing slip out as plain text for printing.
+func renderPackingSlip(slip *genprotos.PackingSlip) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "PACKING SLIP\n")
+	fmt.Fprintf(&buf, "Order:       %s\n", slip.OrderId)
+	fmt.Fprintf(&buf, "Fulfillment: %s\n", slip.FulfillmentId)
+	fmt.Fprintf(&buf, "Placed:      %s\n\n", slip.PlacedAt)
+
+	fmt.Fprintf(&buf, "Ship to:\n")
+	for _, part := range []string{slip.ShipTo.Street, strings.TrimSpace(slip.ShipTo.City + " " + slip.ShipTo.ZipCode), slip.ShipTo.Country} {
+		if part != "" {
+			fmt.Fprintf(&buf, "  %s\n", part)
+		}
+	}
+	buf.WriteString("\n")
+
+	table := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "QTY\tSKU\tITEM\n")
+	for _, line := range slip.Lines {
+		name := line.Name
+		if name == "" {
+			name = line.ProductId
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\n", line.Quantity, line.Sku, name)
+	}
+	table.Flush()
+
+	if slip.Carrier != "" || slip.TrackingNumber != "" {
+		fmt.Fprintf(&buf, "\nCarrier: %s  Tracking: %s\n", slip.Carrier, slip.TrackingNumber)
+	}
+	return buf.String()
+}
+
+// fulfillmentIDs checks and de-duplicates the ids of a bulk request,
+// keeping their order.
+func fulfillmentIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "fulfillment_ids is required")
+	}
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "fulfillment_id %q is not a valid id", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > maxBulkOrders {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d fulfillments at once", maxBulkOrders)
+	}
+	return unique, nil
+}
+
+// parseDateBound reads an RFC 3339 timestamp or a YYYY-MM-DD date. A date
+// used as an upper bound stands for the end of that day.
+func parseDateBound(field, value string, upper bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		if upper {
+			day = day.AddDate(0, 0, 1)
+		}
+		return &day, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", field)
+	}
+	return &at, nil
+}
+
+// scanArtisanOrder reads a row selected with fulfillmentColumns followed by
+// the order's status, buyer, shipping address and placing time.
+func scanArtisanOrder(rows *sql.Rows) (*genprotos.ArtisanOrder, error) {
+	var (
+		order    = genprotos.ArtisanOrder{ShippingAddress: &genprotos.ShippingAddress{}}
+		address  []byte
+		placedAt time.Time
+	)
+	fulfillment, err := scanFulfillment(rows, &order.OrderStatus, &order.BuyerId, &address, &placedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(address, order.ShippingAddress); err != nil {
+		return nil, err
+	}
+	order.Fulfillment = fulfillment
+	order.PlacedAt = placedAt.Format(time.RFC3339)
+	return &order, nil
+}
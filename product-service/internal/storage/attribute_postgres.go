@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+const (
+	attributeString  = "string"
+	attributeNumber  = "number"
+	attributeBoolean = "boolean"
+	attributeEnum    = "enum"
+
+	maxTags      = 30
+	maxTagLength = 50
+)
+
+const attributeColumns = "id, category_id, key, label, type, options, required, position"
+
+// categoryAncestorsQuery returns the category $1 and every category above it.
+const categoryAncestorsQuery = `
+	WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id, 0 AS depth FROM product_categories WHERE id = $1
+		UNION ALL
+		SELECT c.id, c.parent_id, a.depth + 1 FROM product_categories c JOIN ancestors a ON c.id = a.parent_id
+	)`
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+func (p *Product) SetCategoryAttribute(ctx context.Context, req *genprotos.AttributeDefinition) (*genprotos.AttributeDefinition, error) {
+	category, err := p.categoryByKey(ctx, p.db, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	def := &genprotos.AttributeDefinition{
+		CategoryId: category.Id,
+		Key:        strings.ToLower(strings.TrimSpace(req.Key)),
+		Label:      strings.TrimSpace(req.Label),
+		Type:       strings.ToLower(strings.TrimSpace(req.Type)),
+		Required:   req.Required,
+		Position:   req.Position,
+	}
+	if !attributeKeyPattern.MatchString(def.Key) {
+		return nil, status.Errorf(codes.InvalidArgument, "attribute key %q must be lower case letters, digits and underscores", req.Key)
+	}
+	if def.Label == "" {
+		def.Label = def.Key
+	}
+	switch def.Type {
+	case attributeString, attributeNumber, attributeBoolean:
+	case attributeEnum:
+		for _, option := range req.Options {
+			if option = strings.TrimSpace(option); option != "" {
+				def.Options = append(def.Options, option)
+			}
+		}
+		if len(def.Options) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "enum attributes need at least one option")
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown attribute type %q", req.Type)
+	}
+
+	err = p.db.QueryRowContext(ctx, `
+		INSERT INTO category_attributes (id, category_id, key, label, type, options, required, position, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (category_id, key)
+		DO UPDATE SET label = EXCLUDED.label, type = EXCLUDED.type, options = EXCLUDED.options,
+			required = EXCLUDED.required, position = EXCLUDED.position
+		RETURNING id
+	`, uuid.NewString(), def.CategoryId, def.Key, def.Label, def.Type, pq.Array(def.Options), def.Required, def.Position, time.Now()).Scan(&def.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save category attribute: %v", err)
+	}
+
+	return def, nil
+}
+
+func (p *Product) ListCategoryAttributes(ctx context.Context, req *genprotos.ListCategoryAttributesRequest) (*genprotos.ListCategoryAttributesResponse, error) {
+	category, err := p.categoryByKey(ctx, p.db, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := p.categoryAttributes(ctx, p.db, category.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &genprotos.ListCategoryAttributesResponse{Attributes: defs}, nil
+}
+
+func (p *Product) DeleteCategoryAttribute(ctx context.Context, req *genprotos.DeleteCategoryAttributeRequest) (*genprotos.Message, error) {
+	category, err := p.categoryByKey(ctx, p.db, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.db.ExecContext(ctx, "DELETE FROM category_attributes WHERE category_id = $1 AND key = $2", category.Id, strings.ToLower(req.Key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete category attribute: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.NotFound, "category %s has no attribute %q", category.Id, req.Key)
+	}
+
+	return &genprotos.Message{Message: fmt.Sprintf("Attribute %s deleted successfully", req.Key)}, nil
+}
+
+// categoryAttributes returns the attribute schema that applies to a category:
+// its own definitions plus those inherited from its ancestors. A definition
+// on a subcategory overrides an inherited one with the same key.
+func (p *Product) categoryAttributes(ctx context.Context, q queryer, categoryID string) ([]*genprotos.AttributeDefinition, error) {
+	rows, err := q.QueryContext(ctx, categoryAncestorsQuery+`
+		SELECT DISTINCT ON (ca.key) `+prefixColumns("ca", attributeColumns)+`
+		FROM category_attributes ca JOIN ancestors a ON ca.category_id = a.id
+		ORDER BY ca.key, a.depth`, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category attributes: %v", err)
+	}
+	defer rows.Close()
+
+	defs := make([]*genprotos.AttributeDefinition, 0)
+	for rows.Next() {
+		var (
+			def     genprotos.AttributeDefinition
+			options pq.StringArray
+		)
+		err := rows.Scan(&def.Id, &def.CategoryId, &def.Key, &def.Label, &def.Type, &options, &def.Required, &def.Position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category attribute row: %v", err)
+		}
+		def.Options = options
+		defs = append(defs, &def)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over category attribute rows: %v", err)
+	}
+
+	sort.SliceStable(defs, func(i, j int) bool {
+		if defs[i].Position != defs[j].Position {
+			return defs[i].Position < defs[j].Position
+		}
+		return defs[i].Key < defs[j].Key
+	})
+	return defs, nil
+}
+
+// validateAttributes checks product attribute values against the category
+// schema and returns them as the JSON document stored on the product, with
+// numbers and booleans stored as JSON numbers and booleans.
+func (p *Product) validateAttributes(ctx context.Context, q queryer, categoryID string, values map[string]string) (string, error) {
+	defs, err := p.categoryAttributes(ctx, q, categoryID)
+	if err != nil {
+		return "", err
+	}
+
+	byKey := make(map[string]*genprotos.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		byKey[def.Key] = def
+	}
+
+	typed := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		def, ok := byKey[key]
+		if !ok {
+			return "", status.Errorf(codes.InvalidArgument, "attribute %q is not defined for this category", key)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch def.Type {
+		case attributeNumber:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return "", status.Errorf(codes.InvalidArgument, "attribute %q must be a number, got %q", key, value)
+			}
+			typed[key] = json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+		case attributeBoolean:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return "", status.Errorf(codes.InvalidArgument, "attribute %q must be true or false, got %q", key, value)
+			}
+			typed[key] = b
+		case attributeEnum:
+			if !containsString(def.Options, value) {
+				return "", status.Errorf(codes.InvalidArgument, "attribute %q must be one of %s, got %q", key, strings.Join(def.Options, ", "), value)
+			}
+			typed[key] = value
+		default:
+			typed[key] = value
+		}
+	}
+
+	for _, def := range defs {
+		if _, ok := typed[def.Key]; def.Required && !ok {
+			return "", status.Errorf(codes.InvalidArgument, "attribute %q is required", def.Key)
+		}
+	}
+
+	document, err := json.Marshal(typed)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode attributes: %v", err)
+	}
+	return string(document), nil
+}
+
+// decodeAttributes turns a stored attributes document back into the string
+// map exposed over the API.
+func decodeAttributes(document []byte) (map[string]string, error) {
+	if len(document) == 0 {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode attributes: %v", err)
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	attributes := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			attributes[key] = v
+		case bool:
+			attributes[key] = strconv.FormatBool(v)
+		default:
+			attributes[key] = fmt.Sprint(v)
+		}
+	}
+	return attributes, nil
+}
+
+// attributeFilters turns the tag and attribute filters of a search into
+// conditions on the products table.
+func attributeFilters(req *genprotos.SearchAndFilterRequest) ([]squirrel.Sqlizer, error) {
+	var conditions []squirrel.Sqlizer
+
+	if len(req.Tags) > 0 {
+		tags, err := normalizeTags(req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, squirrel.Expr("tags @> ?", pq.Array(tags)))
+	}
+
+	// ->> renders numbers and booleans as text, so one comparison covers
+	// every attribute type.
+	keys := make([]string, 0, len(req.Attributes))
+	for key := range req.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		conditions = append(conditions, squirrel.Expr("attributes ->> ? = ?", key, strings.TrimSpace(req.Attributes[key])))
+	}
+
+	for _, r := range req.AttributeRanges {
+		// The CASE keeps non-numeric values away from the cast.
+		value := "CASE WHEN jsonb_typeof(attributes -> ?) = 'number' THEN (attributes ->> ?)::numeric END"
+		if r.Min != nil {
+			conditions = append(conditions, squirrel.Expr(value+" >= ?", r.Key, r.Key, *r.Min))
+		}
+		if r.Max != nil {
+			conditions = append(conditions, squirrel.Expr(value+" <= ?", r.Key, r.Key, *r.Max))
+		}
+	}
+
+	return conditions, nil
+}
+
+// normalizeTags lower-cases, trims and de-duplicates tags, keeping their order.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, status.Errorf(codes.InvalidArgument, "tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return nil, status.Errorf(codes.InvalidArgument, "a product can have at most %d tags", maxTags)
+	}
+	return normalized, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixColumns qualifies a comma separated column list with a table alias.
+func prefixColumns(alias, columns string) string {
+	parts := strings.Split(columns, ", ")
+	for i, column := range parts {
+		parts[i] = alias + "." + column
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+)
+
+const (
+	defaultOrderHistoryLimit = 20
+	maxOrderHistoryLimit     = 100
+)
+
+func (p *Product) ListMyOrders(ctx context.Context, req *genprotos.ListMyOrdersRequest) (*genprotos.ListMyOrdersResponse, error) {
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", req.UserId)
+	}
+	for _, wanted := range req.Statuses {
+		if _, ok := orderTransitions[wanted]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown order status %q", wanted)
+		}
+	}
+	from, err := parseDateBound("from", req.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDateBound("to", req.To, true)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultOrderHistoryLimit
+	}
+	if limit > maxOrderHistoryLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be at most %d", maxOrderHistoryLimit)
+	}
+
+	query := p.queryBuilder.
+		Select("id", "status", "total_amount", "subtotal_amount", "shipping_amount", "discount_amount", "currency", "shipping_address", "created_at", "updated_at").
+		From("orders o").
+		Where("user_id = ?", req.UserId).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(limit + 1)
+	if len(req.Statuses) > 0 {
+		query = query.Where("status = ANY(?)", pq.Array(req.Statuses))
+	}
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at < ?", *to)
+	}
+	if search := strings.TrimSpace(req.Query); search != "" {
+		query = query.Where("EXISTS(SELECT 1 FROM order_items oi WHERE oi.order_id = o.id AND oi.product_name ILIKE ?)", "%"+search+"%")
+	}
+	if req.Cursor != "" {
+		createdAt, id, err := decodeOrderCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(created_at, id) < (?, ?)", createdAt, id)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %v", err)
+	}
+	defer rows.Close()
+
+	var (
+		response  = genprotos.ListMyOrdersResponse{Orders: make([]*genprotos.BuyerOrder, 0, limit)}
+		createdAt []time.Time
+	)
+	for rows.Next() {
+		var (
+			order                               = genprotos.BuyerOrder{ShippingAddress: &genprotos.ShippingAddress{}}
+			total, subtotal, shipping, discount int64
+			currency                            string
+			address                             []byte
+			placedAt                            time.Time
+			updatedAt                           sql.NullTime
+		)
+		err := rows.Scan(&order.Id, &order.Status, &total, &subtotal, &shipping, &discount, &currency, &address, &placedAt, &updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order row: %v", err)
+		}
+		if err := json.Unmarshal(address, order.ShippingAddress); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal shipping address: %v", err)
+		}
+		order.TotalAmount = money.New(total, currency)
+		order.Subtotal = money.New(subtotal, currency)
+		order.Shipping = money.New(shipping, currency)
+		order.DiscountTotal = money.New(discount, currency)
+		order.CreatedAt = placedAt.Format(time.RFC3339)
+		order.UpdatedAt = formatNullTime(updatedAt)
+		response.Orders = append(response.Orders, &order)
+		createdAt = append(createdAt, placedAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over order rows: %v", err)
+	}
+	rows.Close()
+
+	// One row past the page says there is another page.
+	if uint64(len(response.Orders)) > limit {
+		response.Orders = response.Orders[:limit]
+		last := response.Orders[limit-1]
+		response.NextCursor = encodeOrderCursor(createdAt[limit-1], last.Id)
+	}
+
+	for _, order := range response.Orders {
+		if order.Lines, err = p.orderLines(ctx, p.db, order.Id); err != nil {
+			return nil, err
+		}
+		if order.Fulfillments, err = orderFulfillments(ctx, p.db, order.Id); err != nil {
+			return nil, err
+		}
+	}
+	return &response, nil
+}
+
+// encodeOrderCursor points just past an order in a newest-first listing.
+func encodeOrderCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeOrderCursor(cursor string) (time.Time, string, error) {
+	invalid := status.Error(codes.InvalidArgument, "cursor is not valid; pass next_cursor from the previous page")
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, "", invalid
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", invalid
+	}
+	return createdAt, id, nil
+}
@@ -0,0 +1,575 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/promotion"
+)
+
+const maxCartQuantity = 1<<31 - 1
+
+func (p *Product) AddCartItem(ctx context.Context, req *genprotos.CartItemRequest) (*genprotos.Cart, error) {
+	if req.Quantity == 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be at least 1")
+	}
+	if req.Quantity > maxCartQuantity {
+		return nil, status.Error(codes.InvalidArgument, "quantity is too large")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	cartID, err := p.resolveCart(ctx, tx, req.CartId, req.UserId, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := addToCart(ctx, tx, cartID, req.ProductId, req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+func (p *Product) UpdateCartItem(ctx context.Context, req *genprotos.CartItemRequest) (*genprotos.Cart, error) {
+	if req.Quantity == 0 {
+		return p.RemoveCartItem(ctx, &genprotos.RemoveCartItemRequest{
+			CartId:    req.CartId,
+			UserId:    req.UserId,
+			ProductId: req.ProductId,
+			Currency:  req.Currency,
+		})
+	}
+	if req.Quantity > maxCartQuantity {
+		return nil, status.Error(codes.InvalidArgument, "quantity is too large")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	cartID, err := p.resolveCart(ctx, tx, req.CartId, req.UserId, false)
+	if err != nil {
+		return nil, err
+	}
+	price, currency, err := shoppableProductPrice(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The buyer has seen the current price by now, so it becomes the one
+	// later changes are compared with.
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE cart_items SET quantity = $1, price_amount = $2, currency = $3, updated_at = $4
+		WHERE cart_id = $5 AND product_id = $6
+	`, req.Quantity, price, currency, now, cartID, req.ProductId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update cart item: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to update cart item: %v", err)
+	} else if affected == 0 {
+		return nil, status.Errorf(codes.NotFound, "product %s is not in the cart", req.ProductId)
+	}
+	if err := touchCart(ctx, tx, cartID, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+func (p *Product) RemoveCartItem(ctx context.Context, req *genprotos.RemoveCartItemRequest) (*genprotos.Cart, error) {
+	cartID, err := p.resolveCart(ctx, p.db, req.CartId, req.UserId, false)
+	if err != nil {
+		return nil, err
+	}
+	if cartID != "" {
+		if _, err := p.db.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2", cartID, req.ProductId); err != nil {
+			return nil, fmt.Errorf("failed to remove cart item: %v", err)
+		}
+		if err := touchCart(ctx, p.db, cartID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+func (p *Product) GetCart(ctx context.Context, req *genprotos.CartRequest) (*genprotos.Cart, error) {
+	cartID, err := p.resolveCart(ctx, p.db, req.CartId, req.UserId, false)
+	if err != nil {
+		return nil, err
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+func (p *Product) ClearCart(ctx context.Context, req *genprotos.CartRequest) (*genprotos.Cart, error) {
+	cartID, err := p.resolveCart(ctx, p.db, req.CartId, req.UserId, false)
+	if err != nil {
+		return nil, err
+	}
+	if cartID != "" {
+		if _, err := p.db.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id = $1", cartID); err != nil {
+			return nil, fmt.Errorf("failed to clear cart: %v", err)
+		}
+		if err := touchCart(ctx, p.db, cartID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+// MergeCart hands an anonymous cart to the user who just signed in. When
+// the user already has a cart the items are added to it, otherwise the
+// anonymous cart simply becomes theirs.
+func (p *Product) MergeCart(ctx context.Context, req *genprotos.MergeCartRequest) (*genprotos.Cart, error) {
+	if req.CartId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "cart_id and user_id are required")
+	}
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", req.UserId)
+	}
+	if _, err := uuid.Parse(req.CartId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cart_id %q is not a valid id", req.CartId)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var owner sql.NullString
+	err = tx.QueryRowContext(ctx, "SELECT user_id FROM carts WHERE id = $1 FOR UPDATE", req.CartId).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "cart %s not found", req.CartId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cart: %v", err)
+	}
+	if owner.Valid {
+		if owner.String != req.UserId {
+			return nil, status.Errorf(codes.FailedPrecondition, "cart %s belongs to another user", req.CartId)
+		}
+		// Merged already, e.g. a retried login.
+		return p.loadCart(ctx, tx, req.CartId, req.UserId, req.Currency)
+	}
+
+	now := time.Now()
+	var userCartID string
+	err = tx.QueryRowContext(ctx, "SELECT id FROM carts WHERE user_id = $1 FOR UPDATE", req.UserId).Scan(&userCartID)
+	if err == sql.ErrNoRows {
+		if _, err := tx.ExecContext(ctx, "UPDATE carts SET user_id = $1, updated_at = $2 WHERE id = $3", req.UserId, now, req.CartId); err != nil {
+			return nil, fmt.Errorf("failed to assign cart: %v", err)
+		}
+		userCartID = req.CartId
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch cart: %v", err)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO cart_items (cart_id, product_id, quantity, price_amount, currency, added_at, updated_at)
+			SELECT $1, product_id, quantity, price_amount, currency, added_at, $3
+			FROM cart_items WHERE cart_id = $2
+			ON CONFLICT (cart_id, product_id) DO UPDATE
+			SET quantity = LEAST(cart_items.quantity::bigint + EXCLUDED.quantity, $4), updated_at = EXCLUDED.updated_at
+		`, userCartID, req.CartId, now, maxCartQuantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge cart items: %v", err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE carts SET coupon_codes = ARRAY(
+				SELECT DISTINCT code FROM unnest(coupon_codes || (SELECT coupon_codes FROM carts WHERE id = $2)) AS code
+			)
+			WHERE id = $1
+		`, userCartID, req.CartId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge cart coupons: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM carts WHERE id = $1", req.CartId); err != nil {
+			return nil, fmt.Errorf("failed to delete merged cart: %v", err)
+		}
+		if err := touchCart(ctx, tx, userCartID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return p.loadCart(ctx, p.db, userCartID, req.UserId, req.Currency)
+}
+
+// CheckoutCart places an order for everything in the user's cart and
+// empties it, all or nothing.
+func (p *Product) CheckoutCart(ctx context.Context, req *genprotos.CheckoutCartRequest) (*genprotos.OrderResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required; merge an anonymous cart before checking out")
+	}
+	key, err := idempotencyKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// A retried checkout finds the cart already emptied; with the same
+	// idempotency key it gets the order it placed instead.
+	var replay genprotos.OrderResponse
+	replayed, err := claimIdempotencyKey(ctx, tx, idempotentCheckout, req.UserId, key, req, &replay)
+	if err != nil {
+		return nil, err
+	}
+	if replayed {
+		return &replay, nil
+	}
+
+	// Locking the cart keeps a double-submitted checkout from ordering twice.
+	var (
+		cartID      string
+		couponCodes []string
+	)
+	err = tx.QueryRowContext(ctx, "SELECT id, coupon_codes FROM carts WHERE user_id = $1 FOR UPDATE", req.UserId).Scan(&cartID, pq.Array(&couponCodes))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch cart: %v", err)
+	}
+
+	var items []*genprotos.Item
+	if cartID != "" {
+		rows, err := tx.QueryContext(ctx, "SELECT product_id, quantity FROM cart_items WHERE cart_id = $1 ORDER BY added_at, product_id", cartID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch cart items: %v", err)
+		}
+		for rows.Next() {
+			var item genprotos.Item
+			if err := rows.Scan(&item.ProductId, &item.Quantity); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan cart item: %v", err)
+			}
+			items = append(items, &item)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating over cart items: %v", err)
+		}
+	}
+	if len(items) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "the cart is empty")
+	}
+
+	order, err := p.placeOrder(ctx, tx, &genprotos.OrderRequest{
+		UserId:          req.UserId,
+		ShippingAddress: req.ShippingAddress,
+		Items:           items,
+		Currency:        req.Currency,
+		CouponCodes:     couponCodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id = $1", cartID); err != nil {
+		return nil, fmt.Errorf("failed to empty cart: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE carts SET coupon_codes = '{}', updated_at = $1 WHERE id = $2", time.Now(), cartID); err != nil {
+		return nil, fmt.Errorf("failed to clear cart coupons: %v", err)
+	}
+	if err := saveIdempotentResponse(ctx, tx, idempotentCheckout, req.UserId, key, order); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return order, nil
+}
+
+// resolveCart finds the cart a request refers to: the user's cart when
+// user_id is set, otherwise the anonymous cart_id. With create set a missing
+// cart is created; without it a user who has no cart yet gets "".
+func (p *Product) resolveCart(ctx context.Context, q queryer, cartID, userID string, create bool) (string, error) {
+	if userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", userID)
+		}
+		var id string
+		err := q.QueryRowContext(ctx, "SELECT id FROM carts WHERE user_id = $1", userID).Scan(&id)
+		if err == nil {
+			return id, nil
+		}
+		if err != sql.ErrNoRows {
+			return "", fmt.Errorf("failed to fetch cart: %v", err)
+		}
+		if !create {
+			return "", nil
+		}
+		// Two first adds may race; the loser picks up the winner's cart.
+		if _, err := q.ExecContext(ctx, "INSERT INTO carts (id, user_id, created_at, updated_at) VALUES ($1, $2, $3, $3) ON CONFLICT (user_id) WHERE user_id IS NOT NULL DO NOTHING", uuid.NewString(), userID, time.Now()); err != nil {
+			return "", fmt.Errorf("failed to create cart: %v", err)
+		}
+		if err := q.QueryRowContext(ctx, "SELECT id FROM carts WHERE user_id = $1", userID).Scan(&id); err != nil {
+			return "", fmt.Errorf("failed to fetch cart: %v", err)
+		}
+		return id, nil
+	}
+
+	if cartID == "" {
+		if !create {
+			return "", status.Error(codes.InvalidArgument, "cart_id or user_id is required")
+		}
+		id := uuid.NewString()
+		if _, err := q.ExecContext(ctx, "INSERT INTO carts (id, created_at, updated_at) VALUES ($1, $2, $2)", id, time.Now()); err != nil {
+			return "", fmt.Errorf("failed to create cart: %v", err)
+		}
+		return id, nil
+	}
+
+	if _, err := uuid.Parse(cartID); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "cart_id %q is not a valid id", cartID)
+	}
+	var owner sql.NullString
+	err := q.QueryRowContext(ctx, "SELECT user_id FROM carts WHERE id = $1", cartID).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return "", status.Errorf(codes.NotFound, "cart %s not found", cartID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch cart: %v", err)
+	}
+	if owner.Valid {
+		return "", status.Errorf(codes.PermissionDenied, "cart %s belongs to a user; pass user_id instead", cartID)
+	}
+	return cartID, nil
+}
+
+// loadCart prices a cart in the requested currency with the current product
+// prices and stock. An empty cartID is a user without a cart yet.
+func (p *Product) loadCart(ctx context.Context, q queryer, cartID, userID, requested string) (*genprotos.Cart, error) {
+	currency, err := displayCurrency(requested)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = p.currency.Default
+	}
+
+	cart := &genprotos.Cart{
+		Id:            cartID,
+		UserId:        userID,
+		Items:         make([]*genprotos.CartItem, 0),
+		Subtotal:      money.New(0, currency),
+		Discounts:     make([]*genprotos.OrderDiscount, 0),
+		Shipping:      money.New(0, currency),
+		DiscountTotal: money.New(0, currency),
+		Total:         money.New(0, currency),
+		CouponCodes:   make([]string, 0),
+	}
+	if cartID == "" {
+		return cart, nil
+	}
+
+	var (
+		owner     sql.NullString
+		updatedAt time.Time
+	)
+	err = q.QueryRowContext(ctx, "SELECT user_id, updated_at, coupon_codes FROM carts WHERE id = $1", cartID).Scan(&owner, &updatedAt, pq.Array(&cart.CouponCodes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cart: %v", err)
+	}
+	cart.UserId = owner.String
+	cart.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	rates, err := p.loadRateTable(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT ci.product_id, p.name, ci.quantity, ci.price_amount, ci.currency, ci.added_at,
+			p.price_amount, p.currency, p.quantity, p.status, COALESCE(p.artisan_id::text, ''), COALESCE(p.category_id::text, '')
+		FROM cart_items ci
+		JOIN products p ON p.id = ci.product_id
+		WHERE ci.cart_id = $1
+		ORDER BY ci.added_at, ci.product_id
+	`, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cart items: %v", err)
+	}
+	defer rows.Close()
+
+	var (
+		subtotal int64
+		lines    []promotion.Line
+	)
+	cart.CanCheckout = true
+	for rows.Next() {
+		var (
+			item                        genprotos.CartItem
+			addedAmount, priceAmount    int64
+			addedCurrency, baseCurrency string
+			productStatus               string
+			artisanID, categoryID       string
+			addedAt                     time.Time
+		)
+		err := rows.Scan(&item.ProductId, &item.Name, &item.Quantity, &addedAmount, &addedCurrency, &addedAt,
+			&priceAmount, &baseCurrency, &item.InStock, &productStatus, &artisanID, &categoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cart item: %v", err)
+		}
+		item.AddedAt = addedAt.Format(time.RFC3339)
+
+		price, _, err := p.convertPrice(rates, priceAmount, baseCurrency, currency)
+		if err != nil {
+			return nil, err
+		}
+		line, err := money.Multiply(price, item.Quantity)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "line total for product %s: %v", item.ProductId, err)
+		}
+		item.UnitPrice = money.New(price, currency)
+		item.LineTotal = money.New(line, currency)
+
+		item.Available = true
+		switch {
+		case productStatus != productPublished && productStatus != productOutOfStock:
+			item.Available = false
+			item.Warning = "this product is no longer available"
+		case item.InStock <= 0:
+			item.Available = false
+			item.Warning = "out of stock"
+		case uint64(item.InStock) < item.Quantity:
+			item.Available = false
+			item.Warning = fmt.Sprintf("only %d left in stock", item.InStock)
+		case addedAmount != priceAmount || addedCurrency != baseCurrency:
+			item.Warning = fmt.Sprintf("price changed from %s to %s", money.Format(addedAmount, addedCurrency), money.Format(priceAmount, baseCurrency))
+		}
+		if !item.Available {
+			cart.CanCheckout = false
+		}
+
+		if subtotal, err = money.Add(subtotal, line); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cart subtotal: %v", err)
+		}
+		cart.ItemCount += item.Quantity
+		cart.Items = append(cart.Items, &item)
+		lines = append(lines, promotion.Line{
+			ProductID:  item.ProductId,
+			ArtisanID:  artisanID,
+			CategoryID: categoryID,
+			Quantity:   item.Quantity,
+			UnitPrice:  price,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over cart items: %v", err)
+	}
+	rows.Close()
+	cart.Subtotal = money.New(subtotal, currency)
+	if len(cart.Items) == 0 {
+		cart.CanCheckout = false
+		return cart, nil
+	}
+
+	shipping, err := p.shippingFee(rates, currency)
+	if err != nil {
+		return nil, err
+	}
+	discounts, problems, err := p.evaluatePromotions(ctx, q, cart.UserId, cart.CouponCodes, lines, shipping, currency, rates, false)
+	if err != nil {
+		return nil, err
+	}
+	// Checkout refuses codes that cannot be used, but not ones that merely
+	// lost out to a better offer.
+	if len(problems) > 0 {
+		cart.CanCheckout = false
+	}
+	for _, rule := range discounts.Skipped {
+		if rule.Code != "" {
+			problems = append(problems, fmt.Sprintf("coupon %s cannot be combined with a better offer", rule.Code))
+		}
+	}
+
+	cart.Discounts = discountLines(discounts, currency)
+	cart.CouponWarnings = problems
+	cart.Shipping = money.New(shipping, currency)
+	cart.DiscountTotal = money.New(discounts.Total, currency)
+	cart.Total = money.New(subtotal+shipping-discounts.Total, currency)
+	return cart, nil
+}
+
+// shoppableProductPrice returns the current price of a product that can be
+// put in a cart or saved. Sold-out products can, so buyers can wait for a
+// restock.
+func shoppableProductPrice(ctx context.Context, q queryer, productID string) (int64, string, error) {
+	if _, err := uuid.Parse(productID); err != nil {
+		return 0, "", status.Errorf(codes.InvalidArgument, "product_id %q is not a valid id", productID)
+	}
+
+	var (
+		price         int64
+		currency      string
+		productStatus string
+	)
+	err := q.QueryRowContext(ctx, "SELECT price_amount, currency, status FROM products WHERE id = $1", productID).Scan(&price, &currency, &productStatus)
+	if err == sql.ErrNoRows {
+		return 0, "", status.Errorf(codes.NotFound, "product %s not found", productID)
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to fetch product price: %v", err)
+	}
+	if productStatus != productPublished && productStatus != productOutOfStock {
+		return 0, "", status.Errorf(codes.FailedPrecondition, "product %s is %s and not for sale", productID, productStatus)
+	}
+	return price, currency, nil
+}
+
+// addToCart adds quantity of a product to a cart, on top of what is already
+// there.
+func addToCart(ctx context.Context, q queryer, cartID, productID string, quantity uint64) error {
+	price, currency, err := shoppableProductPrice(ctx, q, productID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO cart_items (cart_id, product_id, quantity, price_amount, currency, added_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (cart_id, product_id) DO UPDATE
+		SET quantity = LEAST(cart_items.quantity::bigint + EXCLUDED.quantity, $7),
+			price_amount = EXCLUDED.price_amount, currency = EXCLUDED.currency, updated_at = EXCLUDED.updated_at
+	`, cartID, productID, quantity, price, currency, now, maxCartQuantity)
+	if err != nil {
+		return fmt.Errorf("failed to add cart item: %v", err)
+	}
+	return touchCart(ctx, q, cartID, now)
+}
+
+func touchCart(ctx context.Context, q queryer, cartID string, at time.Time) error {
+	if _, err := q.ExecContext(ctx, "UPDATE carts SET updated_at = $1 WHERE id = $2", at, cartID); err != nil {
+		return fmt.Errorf("failed to update cart: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,421 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+const categoryColumns = "id, parent_id, name, slug, description, position, created_at, updated_at"
+
+// categorySubtreeQuery returns the category $1 and every category below it.
+const categorySubtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM product_categories WHERE id = $1
+		UNION ALL
+		SELECT c.id FROM product_categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func (p *Product) AddCategory(ctx context.Context, req *genprotos.AddCategoryRequest) (*genprotos.AddCategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "category name is required")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var parentID sql.NullString
+	if req.ParentId != "" {
+		parent, err := p.categoryByKey(ctx, tx, req.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		parentID = sql.NullString{String: parent.Id, Valid: true}
+	}
+
+	slug, err := p.categorySlug(ctx, tx, req.Slug, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+	now := time.Now()
+	var categoryID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO product_categories (id, parent_id, name, slug, description, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING id
+	`, id, parentID, name, slug, req.Description, req.Position, now).Scan(&categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add category: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &genprotos.AddCategoryResponse{
+		Id:          categoryID,
+		Name:        name,
+		Description: req.Description,
+		CreatedAt:   now.Format(time.RFC3339),
+		ParentId:    parentID.String,
+		Slug:        slug,
+		Position:    req.Position,
+	}, nil
+}
+
+func (p *Product) GetCategory(ctx context.Context, req *genprotos.GetCategoryRequest) (*genprotos.Category, error) {
+	return p.categoryByKey(ctx, p.db, req.Id)
+}
+
+func (p *Product) ListCategories(ctx context.Context, req *genprotos.ListCategoriesRequest) (*genprotos.ListCategoriesResponse, error) {
+	query := p.queryBuilder.
+		Select(categoryColumns).
+		From("product_categories").
+		OrderBy("position", "name")
+	if req.ParentId == "" {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		parent, err := p.categoryByKey(ctx, p.db, req.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("parent_id = ?", parent.Id)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	categories, err := p.queryCategories(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &genprotos.ListCategoriesResponse{Categories: categories}, nil
+}
+
+func (p *Product) GetCategoryTree(ctx context.Context, req *genprotos.GetCategoryTreeRequest) (*genprotos.GetCategoryTreeResponse, error) {
+	categories, err := p.queryCategories(ctx, "SELECT "+categoryColumns+" FROM product_categories ORDER BY position, name")
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*genprotos.Category, len(categories))
+	for _, category := range categories {
+		byID[category.Id] = category
+	}
+
+	roots := make([]*genprotos.Category, 0)
+	for _, category := range categories {
+		if parent, ok := byID[category.ParentId]; ok {
+			parent.Children = append(parent.Children, category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	if req.RootId != "" {
+		root, err := p.categoryByKey(ctx, p.db, req.RootId)
+		if err != nil {
+			return nil, err
+		}
+		roots = []*genprotos.Category{byID[root.Id]}
+	}
+
+	return &genprotos.GetCategoryTreeResponse{Roots: roots}, nil
+}
+
+func (p *Product) UpdateCategory(ctx context.Context, req *genprotos.UpdateCategoryRequest) (*genprotos.Category, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	category, err := p.categoryByKey(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"updated_at": time.Now(),
+	}
+	name := category.Name
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, status.Error(codes.InvalidArgument, "category name is required")
+		}
+		data["name"] = name
+	}
+	if req.Slug != nil {
+		// An empty slug is generated from the name, the new one if it changes.
+		slug, err := p.categorySlug(ctx, tx, *req.Slug, name, category.Id)
+		if err != nil {
+			return nil, err
+		}
+		data["slug"] = slug
+	}
+	if req.Description != nil {
+		data["description"] = *req.Description
+	}
+	if req.Position != nil {
+		data["position"] = *req.Position
+	}
+	if req.ParentId != nil {
+		if *req.ParentId == "" {
+			data["parent_id"] = nil
+		} else {
+			parent, err := p.categoryByKey(ctx, tx, *req.ParentId)
+			if err != nil {
+				return nil, err
+			}
+			// Moving a category below itself would detach the subtree into a cycle.
+			subtree, err := categorySubtree(ctx, tx, category.Id)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range subtree {
+				if id == parent.Id {
+					return nil, status.Error(codes.InvalidArgument, "a category cannot be moved under itself or one of its descendants")
+				}
+			}
+			data["parent_id"] = parent.Id
+		}
+	}
+
+	query, args, err := p.queryBuilder.Update("product_categories").
+		SetMap(data).
+		Where("id = ?", category.Id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to update category: %v", err)
+	}
+
+	updated, err := p.categoryByKey(ctx, tx, category.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return updated, nil
+}
+
+func (p *Product) DeleteCategory(ctx context.Context, req *genprotos.DeleteCategoryRequest) (*genprotos.Message, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	category, err := p.categoryByKey(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var productCount int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM products WHERE category_id = $1", category.Id).Scan(&productCount); err != nil {
+		return nil, fmt.Errorf("failed to count category products: %v", err)
+	}
+	if productCount > 0 {
+		if req.ReassignProductsTo == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "category %s still has %d products; reassign them first", category.Id, productCount)
+		}
+		target, err := p.categoryByKey(ctx, tx, req.ReassignProductsTo)
+		if err != nil {
+			return nil, err
+		}
+		if target.Id == category.Id {
+			return nil, status.Error(codes.InvalidArgument, "cannot reassign products to the category being deleted")
+		}
+		ids, err := updatedProductIDs(ctx, tx, "UPDATE products SET category_id = $1, version = version + 1, updated_at = $2 WHERE category_id = $3 RETURNING id", target.Id, time.Now(), category.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reassign products: %v", err)
+		}
+		for _, id := range ids {
+			if _, err := p.recordRevision(ctx, tx, id, "", revisionCategory, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var parentID sql.NullString
+	if category.ParentId != "" {
+		parentID = sql.NullString{String: category.ParentId, Valid: true}
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE product_categories SET parent_id = $1, updated_at = $2 WHERE parent_id = $3", parentID, time.Now(), category.Id); err != nil {
+		return nil, fmt.Errorf("failed to move child categories: %v", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_categories WHERE id = $1", category.Id); err != nil {
+		return nil, fmt.Errorf("failed to delete category: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &genprotos.Message{Message: "Category deleted successfully"}, nil
+}
+
+// categoryByKey loads a category by id or slug.
+func (p *Product) categoryByKey(ctx context.Context, q queryer, key string) (*genprotos.Category, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, status.Error(codes.InvalidArgument, "category id is required")
+	}
+
+	condition, arg := "slug = $1", interface{}(strings.ToLower(key))
+	if _, err := uuid.Parse(key); err == nil {
+		condition, arg = "id = $1", key
+	}
+
+	category, err := scanCategory(q.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM product_categories WHERE "+condition, arg))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "category %s not found", key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category: %v", err)
+	}
+	return category, nil
+}
+
+func (p *Product) queryCategories(ctx context.Context, query string, args ...interface{}) ([]*genprotos.Category, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := make([]*genprotos.Category, 0)
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category row: %v", err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over category rows: %v", err)
+	}
+	return categories, nil
+}
+
+// categorySlug normalises a requested slug, or derives one from the name,
+// making sure no other category uses it. excludeID is the category being
+// updated, if any.
+func (p *Product) categorySlug(ctx context.Context, q queryer, requested, name, excludeID string) (string, error) {
+	explicit := strings.TrimSpace(requested) != ""
+	base := slugify(requested)
+	if !explicit {
+		base = slugify(name)
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		var taken bool
+		err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM product_categories WHERE slug = $1 AND id::text <> $2)", slug, excludeID).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("failed to check category slug: %v", err)
+		}
+		if !taken {
+			return slug, nil
+		}
+		if explicit {
+			return "", status.Errorf(codes.AlreadyExists, "category slug %q is already in use", slug)
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// categorySubtree returns the ids of a category and all of its descendants.
+func categorySubtree(ctx context.Context, q queryer, id string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, categorySubtreeQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category subtree: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var childID string
+		if err := rows.Scan(&childID); err != nil {
+			return nil, fmt.Errorf("failed to scan category id: %v", err)
+		}
+		ids = append(ids, childID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over category rows: %v", err)
+	}
+	return ids, nil
+}
+
+// categoryFilter resolves a search category (id or slug) into a condition
+// matching products in it or any of its subcategories.
+func (p *Product) categoryFilter(ctx context.Context, key string) (string, error) {
+	category, err := p.categoryByKey(ctx, p.db, key)
+	if err != nil {
+		return "", err
+	}
+	ids, err := categorySubtree(ctx, p.db, category.Id)
+	if err != nil {
+		return "", err
+	}
+	// The ids come from the database, so quoting them inline is safe.
+	return "category_id IN ('" + strings.Join(ids, "', '") + "')", nil
+}
+
+func slugify(value string) string {
+	slug := strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+	if slug == "" {
+		return "category"
+	}
+	if len(slug) > 120 {
+		slug = strings.TrimRight(slug[:120], "-")
+	}
+	return slug
+}
+
+func scanCategory(row rowScanner) (*genprotos.Category, error) {
+	var (
+		category    genprotos.Category
+		parentID    sql.NullString
+		description sql.NullString
+		createdAt   time.Time
+		updatedAt   time.Time
+	)
+	err := row.Scan(&category.Id, &parentID, &category.Name, &category.Slug, &description, &category.Position, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	category.ParentId = parentID.String
+	category.Description = description.String
+	category.CreatedAt = createdAt.Format(time.RFC3339)
+	category.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return &category, nil
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+// validateOrder checks a whole order before anything is written and
+// reports every problem at once. The products are locked until the caller's
+// transaction ends, so the prices, status and stock it saw are the ones the
+// order is placed with.
+func validateOrder(ctx context.Context, tx *sql.Tx, req *genprotos.OrderRequest) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	violate := func(field, format string, args ...interface{}) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if address := req.ShippingAddress; address == nil {
+		violate("shipping_address", "is required")
+	} else {
+		required := []struct{ field, value string }{
+			{"street", address.Street},
+			{"city", address.City},
+			{"country", address.Country},
+		}
+		for _, part := range required {
+			if strings.TrimSpace(part.value) == "" {
+				violate("shipping_address."+part.field, "is required")
+			}
+		}
+	}
+	if len(req.Items) == 0 {
+		violate("items", "an order needs at least one item")
+	}
+
+	var (
+		ids       []string
+		fields    = make(map[string]string)
+		requested = make(map[string]uint64)
+	)
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if _, err := uuid.Parse(item.ProductId); err != nil {
+			violate(field+".product_id", "%q is not a valid product id", item.ProductId)
+			continue
+		}
+		if item.Quantity == 0 {
+			violate(field+".quantity", "must be at least 1")
+			continue
+		}
+		if _, ok := fields[item.ProductId]; !ok {
+			ids = append(ids, item.ProductId)
+			fields[item.ProductId] = field
+		}
+		requested[item.ProductId] += item.Quantity
+		if item.Quantity > 1<<31-1 || requested[item.ProductId] > 1<<31-1 {
+			violate(field+".quantity", "is too large")
+		}
+	}
+	malformed := len(violations) > 0
+
+	if len(ids) > 0 {
+		type lockedProduct struct {
+			status   string
+			quantity int64
+		}
+		products := make(map[string]lockedProduct, len(ids))
+		rows, err := tx.QueryContext(ctx, "SELECT id, status, quantity FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(ids))
+		if err != nil {
+			return fmt.Errorf("failed to lock order products: %v", err)
+		}
+		for rows.Next() {
+			var (
+				id      string
+				product lockedProduct
+			)
+			if err := rows.Scan(&id, &product.status, &product.quantity); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan product row: %v", err)
+			}
+			products[id] = product
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating over product rows: %v", err)
+		}
+
+		for _, id := range ids {
+			product, ok := products[id]
+			switch {
+			case !ok:
+				violate(fields[id]+".product_id", "product %s not found", id)
+			case product.status != productPublished:
+				violate(fields[id]+".product_id", "product %s is %s and cannot be ordered", id, product.status)
+			case product.quantity < int64(requested[id]):
+				violate(fields[id]+".quantity", "product %s has %d in stock, %d requested", id, product.quantity, requested[id])
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	code := codes.FailedPrecondition
+	if malformed {
+		code = codes.InvalidArgument
+	}
+	return badRequest(code, "the order cannot be placed", violations)
+}
+
+// badRequest builds an error listing every violation in its message and as
+// BadRequest details, so clients can point at each field.
+func badRequest(code codes.Code, summary string, violations []*errdetails.BadRequest_FieldViolation) error {
+	problems := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		problems = append(problems, violation.Field+": "+violation.Description)
+	}
+	st := status.New(code, summary+": "+strings.Join(problems, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
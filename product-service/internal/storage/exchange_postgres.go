@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+)
+
+const (
+	rateSourceAdmin = "admin"
+	rateSourceFile  = "file"
+)
+
+func (p *Product) SetExchangeRate(ctx context.Context, req *genprotos.SetExchangeRateRequest) (*genprotos.ExchangeRate, error) {
+	base, quote, err := currencyPair(req.BaseCurrency, req.QuoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := money.ParseRate(req.Rate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := p.upsertExchangeRate(ctx, p.db, money.Rate{Base: base, Quote: quote, Rate: rate}, rateSourceAdmin); err != nil {
+		return nil, err
+	}
+
+	return &genprotos.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          money.FormatRate(rate),
+		Source:        rateSourceAdmin,
+		UpdatedAt:     time.Now().Format(time.RFC3339),
+	}, nil
+}
+
+func (p *Product) ListExchangeRates(ctx context.Context, req *genprotos.ListExchangeRatesRequest) (*genprotos.ListExchangeRatesResponse, error) {
+	query := p.queryBuilder.
+		Select("base_currency", "quote_currency", "rate", "source", "updated_at").
+		From("exchange_rates").
+		OrderBy("base_currency", "quote_currency")
+	if req.BaseCurrency != "" {
+		query = query.Where("base_currency = ?", strings.ToUpper(req.BaseCurrency))
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %v", err)
+	}
+	defer rows.Close()
+
+	rates := make([]*genprotos.ExchangeRate, 0)
+	for rows.Next() {
+		var (
+			rate      genprotos.ExchangeRate
+			updatedAt time.Time
+		)
+		if err := rows.Scan(&rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.Source, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate row: %v", err)
+		}
+		if parsed, err := money.ParseRate(rate.Rate); err == nil {
+			rate.Rate = money.FormatRate(parsed)
+		}
+		rate.UpdatedAt = updatedAt.Format(time.RFC3339)
+		rates = append(rates, &rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over exchange rate rows: %v", err)
+	}
+
+	return &genprotos.ListExchangeRatesResponse{Rates: rates}, nil
+}
+
+func (p *Product) DeleteExchangeRate(ctx context.Context, req *genprotos.DeleteExchangeRateRequest) (*genprotos.Message, error) {
+	base, quote, err := currencyPair(req.BaseCurrency, req.QuoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.db.ExecContext(ctx, "DELETE FROM exchange_rates WHERE base_currency = $1 AND quote_currency = $2", base, quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete exchange rate: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, status.Errorf(codes.NotFound, "no exchange rate from %s to %s", base, quote)
+	}
+
+	return &genprotos.Message{Message: fmt.Sprintf("Exchange rate %s/%s deleted successfully", base, quote)}, nil
+}
+
+// ImportExchangeRates stores a batch of rates from a feed in one transaction.
+func (p *Product) ImportExchangeRates(ctx context.Context, rates []money.Rate, source string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		if err := p.upsertExchangeRate(ctx, tx, rate, source); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// WatchRatesFile loads the exchange rate feed at path and, when interval is
+// positive, reloads it on that interval until ctx is done.
+func (p *Product) WatchRatesFile(ctx context.Context, path string, interval time.Duration) {
+	load := func() {
+		rates, err := money.LoadRatesFile(path)
+		if err == nil {
+			err = p.ImportExchangeRates(ctx, rates, rateSourceFile)
+		}
+		if err != nil {
+			log.Printf("failed to load exchange rates from %s: %v", path, err)
+			return
+		}
+		log.Printf("loaded %d exchange rates from %s", len(rates), path)
+	}
+
+	load()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			load()
+		}
+	}
+}
+
+func (p *Product) upsertExchangeRate(ctx context.Context, q queryer, rate money.Rate, source string) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO exchange_rates (base_currency, quote_currency, rate, source, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (base_currency, quote_currency)
+		DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at
+	`, rate.Base, rate.Quote, money.FormatRate(rate.Rate), source, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to store exchange rate %s/%s: %v", rate.Base, rate.Quote, err)
+	}
+	return nil
+}
+
+// loadRateTable snapshots all stored rates. Checkout takes one snapshot and
+// uses it for every line so the whole order sees the same rates.
+func (p *Product) loadRateTable(ctx context.Context, q queryer) (*money.RateTable, error) {
+	rows, err := q.QueryContext(ctx, "SELECT base_currency, quote_currency, rate FROM exchange_rates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %v", err)
+	}
+	defer rows.Close()
+
+	var rates []money.Rate
+	for rows.Next() {
+		var base, quote, value string
+		if err := rows.Scan(&base, &quote, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate row: %v", err)
+		}
+		rate, err := money.ParseRate(value)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, money.Rate{Base: base, Quote: quote, Rate: rate})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over exchange rate rows: %v", err)
+	}
+
+	return money.NewRateTable(p.currency.Default, rates), nil
+}
+
+// convertPrice converts amount into the target currency, returning the rate
+// it used so callers can record it.
+func (p *Product) convertPrice(rates *money.RateTable, amount int64, from, to string) (int64, *big.Rat, error) {
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return 0, nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	converted, err := money.Convert(amount, from, to, rate, p.rounding)
+	if err != nil {
+		return 0, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return converted, rate, nil
+}
+
+// applyDisplayPrices fills DisplayPrice on listing results when the caller
+// asked for a display currency. Products priced in a currency we have no
+// rate for are left without one rather than failing the whole page.
+func (p *Product) applyDisplayPrices(ctx context.Context, products []*genprotos.Product, currency string) error {
+	if currency == "" || len(products) == 0 {
+		return nil
+	}
+
+	rates, err := p.loadRateTable(ctx, p.db)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		amount, _, err := p.convertPrice(rates, product.Price.Amount, product.Price.Currency, currency)
+		if status.Code(err) == codes.FailedPrecondition {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		product.DisplayPrice = money.New(amount, currency)
+	}
+	return nil
+}
+
+// priceBoundClause turns a price bound into a condition that works across
+// base currencies by converting the bound into every currency we have rates for.
+func (p *Product) priceBoundClause(rates *money.RateTable, bound *genprotos.Money, op string) string {
+	var conditions []string
+	for _, currency := range rates.Currencies() {
+		if _, err := money.Exponent(currency); err != nil {
+			continue
+		}
+		amount, _, err := p.convertPrice(rates, bound.Amount, bound.Currency, currency)
+		if err != nil {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("(currency = '%s' AND price_amount %s %d)", currency, op, amount))
+	}
+	if len(conditions) == 0 {
+		return fmt.Sprintf("(currency = '%s' AND price_amount %s %d)", bound.Currency, op, bound.Amount)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
+
+// displayCurrency normalises and validates a requested display currency.
+func displayCurrency(value string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	if currency == "" {
+		return "", nil
+	}
+	if _, err := money.Exponent(currency); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return currency, nil
+}
+
+func currencyPair(base, quote string) (string, string, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	for _, currency := range []string{base, quote} {
+		if _, err := money.Exponent(currency); err != nil {
+			return "", "", status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if base == quote {
+		return "", "", status.Error(codes.InvalidArgument, "base and quote currency must differ")
+	}
+	return base, quote, nil
+}
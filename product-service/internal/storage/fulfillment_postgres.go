@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/promotion"
+)
+
+// fulfillmentColumns selects from order_fulfillments aliased as f.
+const fulfillmentColumns = "f.id, f.order_id, COALESCE(f.artisan_id::text, ''), f.status, f.currency, f.subtotal_amount, f.shipping_amount, f.discount_amount, f.payout_amount, f.refunded_amount, COALESCE(f.tracking_number, ''), COALESCE(f.carrier, ''), COALESCE(f.estimated_delivery_date, ''), f.created_at, f.updated_at"
+
+// fulfillmentPlan is one artisan's part of an order being placed.
+type fulfillmentPlan struct {
+	id        string
+	artisanID string
+	items     []*genprotos.Item
+	subtotal  int64
+	shipping  int64
+	discount  int64
+}
+
+// payout is what the artisan is owed for their part.
+func (f *fulfillmentPlan) payout() int64 {
+	return f.subtotal + f.shipping - f.discount
+}
+
+// planFulfillments splits an order into one part per artisan, in the order
+// the artisans first appear. Each part keeps the discounts taken off its own
+// items; shipping, and what was taken off it, is split by subtotal. The
+// parts add up to the order's totals exactly. promoLines and discounts are
+// what evaluatePromotions was given and returned for lines.
+func planFulfillments(lines []orderItemPrice, promoLines []promotion.Line, discounts promotion.Result, shipping int64) ([]*fulfillmentPlan, map[string]*fulfillmentPlan) {
+	var plans []*fulfillmentPlan
+	byArtisan := make(map[string]*fulfillmentPlan)
+	for _, line := range lines {
+		plan, ok := byArtisan[line.artisanID]
+		if !ok {
+			plan = &fulfillmentPlan{id: uuid.NewString(), artisanID: line.artisanID}
+			byArtisan[line.artisanID] = plan
+			plans = append(plans, plan)
+		}
+		plan.items = append(plan.items, line.item)
+		// Totals were checked for overflow when the order was priced.
+		plan.subtotal += line.price * int64(line.item.Quantity)
+	}
+
+	subtotals := make([]int64, len(plans))
+	for i, plan := range plans {
+		subtotals[i] = plan.subtotal
+	}
+	shares := money.Allocate(shipping, subtotals)
+
+	var shippingOff int64
+	for _, discount := range discounts.Discounts {
+		for i, off := range discount.Lines {
+			byArtisan[promoLines[i].ArtisanID].discount += off
+		}
+		shippingOff += discount.Shipping
+	}
+	shippingOffShares := money.Allocate(shippingOff, shares)
+	for i, plan := range plans {
+		plan.shipping = shares[i]
+		plan.discount += shippingOffShares[i]
+	}
+	return plans, byArtisan
+}
+
+// insertFulfillments stores the parts of a new order.
+func insertFulfillments(ctx context.Context, tx *sql.Tx, orderID, currency string, plans []*fulfillmentPlan) error {
+	now := time.Now()
+	for _, plan := range plans {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO order_fulfillments (id, order_id, artisan_id, status, currency, subtotal_amount, shipping_amount, discount_amount, payout_amount, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		`, plan.id, orderID, sql.NullString{String: plan.artisanID, Valid: plan.artisanID != ""}, orderPending, currency,
+			plan.subtotal, plan.shipping, plan.discount, plan.payout(), now)
+		if err != nil {
+			return fmt.Errorf("failed to insert order fulfillment: %v", err)
+		}
+	}
+	return nil
+}
+
+// plannedFulfillments describes the parts of an order that was just placed.
+func plannedFulfillments(orderID, currency string, plans []*fulfillmentPlan) []*genprotos.Fulfillment {
+	now := time.Now().Format(time.RFC3339)
+	fulfillments := make([]*genprotos.Fulfillment, 0, len(plans))
+	for _, plan := range plans {
+		fulfillments = append(fulfillments, &genprotos.Fulfillment{
+			Id:            plan.id,
+			OrderId:       orderID,
+			ArtisanId:     plan.artisanID,
+			Status:        orderPending,
+			Items:         plan.items,
+			Subtotal:      money.New(plan.subtotal, currency),
+			Shipping:      money.New(plan.shipping, currency),
+			DiscountTotal: money.New(plan.discount, currency),
+			Payout:        money.New(plan.payout(), currency),
+			Refunded:      money.New(0, currency),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return fulfillments
+}
+
+// orderFulfillments returns the parts of an order with their items.
+func orderFulfillments(ctx context.Context, q queryer, orderID string) ([]*genprotos.Fulfillment, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+fulfillmentColumns+" FROM order_fulfillments f WHERE f.order_id = $1 ORDER BY f.created_at, f.id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order fulfillments: %v", err)
+	}
+	defer rows.Close()
+
+	fulfillments := make([]*genprotos.Fulfillment, 0)
+	byID := make(map[string]*genprotos.Fulfillment)
+	for rows.Next() {
+		fulfillment, err := scanFulfillment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fulfillment row: %v", err)
+		}
+		fulfillment.Items = make([]*genprotos.Item, 0)
+		fulfillments = append(fulfillments, fulfillment)
+		byID[fulfillment.Id] = fulfillment
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over fulfillment rows: %v", err)
+	}
+	rows.Close()
+
+	items, err := q.QueryContext(ctx, "SELECT fulfillment_id, product_id, quantity FROM order_items WHERE order_id = $1 AND fulfillment_id IS NOT NULL ORDER BY id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fulfillment items: %v", err)
+	}
+	defer items.Close()
+	for items.Next() {
+		var (
+			fulfillmentID string
+			item          genprotos.Item
+		)
+		if err := items.Scan(&fulfillmentID, &item.ProductId, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan fulfillment item: %v", err)
+		}
+		if fulfillment, ok := byID[fulfillmentID]; ok {
+			fulfillment.Items = append(fulfillment.Items, &item)
+		}
+	}
+	if err := items.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over fulfillment items: %v", err)
+	}
+	return fulfillments, nil
+}
+
+// scanFulfillment reads a row selected with fulfillmentColumns, followed by
+// any extra columns into extra.
+func scanFulfillment(row rowScanner, extra ...interface{}) (*genprotos.Fulfillment, error) {
+	var (
+		fulfillment                                  genprotos.Fulfillment
+		currency                                     string
+		subtotal, shipping, discount, payout, refund int64
+		createdAt, updatedAt                         time.Time
+	)
+	dest := []interface{}{&fulfillment.Id, &fulfillment.OrderId, &fulfillment.ArtisanId, &fulfillment.Status, &currency,
+		&subtotal, &shipping, &discount, &payout, &refund,
+		&fulfillment.TrackingNumber, &fulfillment.Carrier, &fulfillment.EstimatedDeliveryDate, &createdAt, &updatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	fulfillment.Subtotal = money.New(subtotal, currency)
+	fulfillment.Shipping = money.New(shipping, currency)
+	fulfillment.DiscountTotal = money.New(discount, currency)
+	fulfillment.Payout = money.New(payout, currency)
+	fulfillment.Refunded = money.New(refund, currency)
+	fulfillment.CreatedAt = createdAt.Format(time.RFC3339)
+	fulfillment.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return &fulfillment, nil
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Operations an idempotency key is used for. Keys are unique per operation
+// and scope, the user or order the caller sent them for, so callers who
+// happen to pick the same key never see each other's responses.
+const (
+	idempotentOrder    = "order"
+	idempotentCheckout = "checkout"
+	idempotentPay      = "pay"
+)
+
+const (
+	// idempotencyHeader is the metadata the gateway forwards the
+	// Idempotency-Key header in.
+	idempotencyHeader       = "idempotency-key"
+	maxIdempotencyKeyLength = 255
+	idempotencyKeyTTL       = 24 * time.Hour
+)
+
+// idempotencyKey returns the idempotency key the caller sent, or "" when
+// there is none.
+func idempotencyKey(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	values := md.Get(idempotencyHeader)
+	if len(values) == 0 {
+		return "", nil
+	}
+	key := strings.TrimSpace(values[0])
+	if len(key) > maxIdempotencyKeyLength {
+		return "", status.Errorf(codes.InvalidArgument, "the idempotency key must be at most %d characters", maxIdempotencyKeyLength)
+	}
+	return key, nil
+}
+
+// claimIdempotencyKey takes key within scope for req inside tx. When a request under the
+// key already went through, its response is decoded into resp and replayed
+// is true. A request still running under the key is waited for; one that
+// failed left nothing behind, so the key can be retried. Reusing a key for
+// different input is refused. Without a key nothing happens.
+func claimIdempotencyKey(ctx context.Context, tx *sql.Tx, operation, scope, key string, req, resp proto.Message) (replayed bool, err error) {
+	if key == "" {
+		return false, nil
+	}
+	hash, err := requestHash(req)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE operation = $1 AND scope = $2 AND key = $3 AND created_at <= $4", operation, scope, key, time.Now().Add(-idempotencyKeyTTL)); err != nil {
+		return false, fmt.Errorf("failed to expire idempotency key: %v", err)
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (operation, scope, key, request_hash, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (operation, scope, key) DO NOTHING
+	`, operation, scope, key, hash, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %v", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %v", err)
+	} else if n == 1 {
+		return false, nil
+	}
+
+	var (
+		storedHash string
+		response   []byte
+	)
+	err = tx.QueryRowContext(ctx, "SELECT request_hash, response FROM idempotency_keys WHERE operation = $1 AND scope = $2 AND key = $3", operation, scope, key).Scan(&storedHash, &response)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch idempotency key: %v", err)
+	}
+	if storedHash != hash {
+		return false, status.Errorf(codes.AlreadyExists, "idempotency key %q was already used for a different request", key)
+	}
+	if response == nil {
+		return false, status.Errorf(codes.Aborted, "the request with idempotency key %q has not finished; try again", key)
+	}
+	if err := proto.Unmarshal(response, resp); err != nil {
+		return false, fmt.Errorf("failed to decode stored response: %v", err)
+	}
+	return true, nil
+}
+
+// saveIdempotentResponse stores the response of the request that claimed
+// key, to be replayed.
+func saveIdempotentResponse(ctx context.Context, tx *sql.Tx, operation, scope, key string, resp proto.Message) error {
+	if key == "" {
+		return nil
+	}
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE idempotency_keys SET response = $1 WHERE operation = $2 AND scope = $3 AND key = $4", data, operation, scope, key); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %v", err)
+	}
+	return nil
+}
+
+// releaseIdempotencyKey gives key up, for requests that commit but whose
+// outcome should not be replayed.
+func releaseIdempotencyKey(ctx context.Context, tx *sql.Tx, operation, scope, key string) error {
+	if key == "" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE operation = $1 AND scope = $2 AND key = $3", operation, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %v", err)
+	}
+	return nil
+}
+
+// purgeIdempotencyKeys forgets keys older than idempotencyKeyTTL.
+func (p *Product) purgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := p.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at <= $1", time.Now().Add(-idempotencyKeyTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %v", err)
+	}
+	return result.RowsAffected()
+}
+
+func requestHash(req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
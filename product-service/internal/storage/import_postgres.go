@@ -0,0 +1,700 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/bulk"
+	"armiya/equipment-service/internal/money"
+)
+
+const (
+	importPending   = "pending"
+	importRunning   = "running"
+	importCompleted = "completed"
+	importFailed    = "failed"
+
+	maxSKULength = 64
+
+	// importProgressEvery is how many rows a running import handles between
+	// progress updates.
+	importProgressEvery = 100
+	exportChunkSize     = 32 << 10
+)
+
+const importColumns = "id, artisan_id, format, dry_run, status, total_rows, created_count, updated_count, failed_count, row_errors, COALESCE(error, ''), created_at, started_at, finished_at"
+
+type importJob struct {
+	id        string
+	artisanID string
+	actorID   string
+	format    string
+	dryRun    bool
+	payload   []byte
+}
+
+type importResult struct {
+	created int
+	updated int
+	errors  []bulk.RowError
+}
+
+func (p *Product) ImportProducts(ctx context.Context, req *genprotos.ImportProductsRequest) (*genprotos.ProductImport, error) {
+	format, err := bulk.ParseFormat(req.Format)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := uuid.Parse(req.ArtisanId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+	}
+	actorID := req.ActorId
+	if actorID == "" {
+		actorID = req.ArtisanId
+	}
+	if _, err := uuid.Parse(actorID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "actor_id %q is not a valid id", actorID)
+	}
+	if len(req.Data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the import file is empty")
+	}
+	if int64(len(req.Data)) > p.catalog.ImportMaxBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "the import file is %d bytes, the limit is %d", len(req.Data), p.catalog.ImportMaxBytes)
+	}
+
+	job, err := scanImport(p.db.QueryRowContext(ctx, `
+		INSERT INTO product_import_jobs (id, artisan_id, actor_id, format, dry_run, status, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+importColumns,
+		uuid.NewString(), req.ArtisanId, actorID, format, req.DryRun, importPending, req.Data, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue product import: %v", err)
+	}
+
+	// Wake the worker; a full channel means it is already due to look.
+	select {
+	case p.importQueued <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+func (p *Product) GetProductImport(ctx context.Context, req *genprotos.GetProductImportRequest) (*genprotos.ProductImport, error) {
+	if _, err := uuid.Parse(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "import id %q is not a valid id", req.Id)
+	}
+
+	job, err := scanImport(p.db.QueryRowContext(ctx, "SELECT "+importColumns+" FROM product_import_jobs WHERE id = $1", req.Id))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product import %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product import: %v", err)
+	}
+
+	return job, nil
+}
+
+func (p *Product) ListProductImports(ctx context.Context, req *genprotos.ListProductImportsRequest) (*genprotos.ListProductImportsResponse, error) {
+	if _, err := uuid.Parse(req.ArtisanId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+	}
+
+	query := p.queryBuilder.
+		Select(importColumns).
+		From("product_import_jobs").
+		Where("artisan_id = ?", req.ArtisanId).
+		OrderBy("created_at DESC")
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product imports: %v", err)
+	}
+	defer rows.Close()
+
+	imports := make([]*genprotos.ProductImport, 0)
+	for rows.Next() {
+		job, err := scanImport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product import row: %v", err)
+		}
+		imports = append(imports, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over product import rows: %v", err)
+	}
+
+	return &genprotos.ListProductImportsResponse{Imports: imports}, nil
+}
+
+// RunImportWorker processes queued imports one at a time, checking the queue
+// once per interval and whenever a new import arrives, until ctx is done.
+// Imports that were running when the service stopped are started again.
+func (p *Product) RunImportWorker(ctx context.Context, interval time.Duration) {
+	result, err := p.db.ExecContext(ctx, "UPDATE product_import_jobs SET status = $1, started_at = NULL WHERE status = $2", importPending, importRunning)
+	if err != nil {
+		log.Printf("failed to requeue interrupted product imports: %v", err)
+	} else if n, _ := result.RowsAffected(); n > 0 {
+		log.Printf("requeued %d interrupted product imports", n)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			ran, err := p.runNextImport(ctx)
+			if err != nil {
+				log.Printf("failed to run product import: %v", err)
+			}
+			if !ran || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.importQueued:
+		}
+	}
+}
+
+// runNextImport claims the oldest pending import and runs it. It reports
+// false when there was nothing to do.
+func (p *Product) runNextImport(ctx context.Context) (bool, error) {
+	var (
+		job   importJob
+		actor sql.NullString
+	)
+	err := p.db.QueryRowContext(ctx, `
+		UPDATE product_import_jobs SET status = $1, started_at = $2
+		WHERE id = (
+			SELECT id FROM product_import_jobs WHERE status = $3
+			ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, artisan_id, actor_id, format, dry_run, payload
+	`, importRunning, time.Now(), importPending).Scan(&job.id, &job.artisanID, &actor, &job.format, &job.dryRun, &job.payload)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim product import: %v", err)
+	}
+	job.actorID = actor.String
+
+	return true, p.runImport(ctx, &job)
+}
+
+func (p *Product) runImport(ctx context.Context, job *importJob) error {
+	records, rowErrors, err := bulk.Decode(job.format, job.payload)
+	if err != nil {
+		return p.finishImport(ctx, job.id, importFailed, importResult{}, err.Error())
+	}
+	total := len(records) + len(rowErrors)
+	if total > p.catalog.ImportMaxRows {
+		return p.finishImport(ctx, job.id, importFailed, importResult{}, fmt.Sprintf("the file has %d rows, the limit is %d", total, p.catalog.ImportMaxRows))
+	}
+	if _, err := p.db.ExecContext(ctx, "UPDATE product_import_jobs SET total_rows = $1 WHERE id = $2", total, job.id); err != nil {
+		return fmt.Errorf("failed to update product import: %v", err)
+	}
+
+	result := importResult{errors: rowErrors}
+	seen := make(map[string]int, len(records))
+	for i, record := range records {
+		sku, err := normalizeSKU(record.SKU)
+		switch {
+		case err != nil:
+			// reported below
+		case sku == "":
+			err = status.Error(codes.InvalidArgument, "sku is required")
+		case seen[sku] != 0:
+			err = status.Errorf(codes.InvalidArgument, "sku %q already appears in row %d", sku, seen[sku])
+		default:
+			seen[sku] = record.Row
+			record.SKU = sku
+
+			var created bool
+			if created, err = p.importRecord(ctx, job, record); err == nil && created {
+				result.created++
+			} else if err == nil {
+				result.updated++
+			}
+		}
+		if err != nil {
+			result.errors = append(result.errors, bulk.RowError{Row: record.Row, SKU: record.SKU, Message: status.Convert(err).Message()})
+		}
+
+		if (i+1)%importProgressEvery == 0 {
+			if err := p.saveImportProgress(ctx, job.id, result); err != nil {
+				log.Printf("failed to save progress of product import %s: %v", job.id, err)
+			}
+		}
+	}
+
+	return p.finishImport(ctx, job.id, importCompleted, result, "")
+}
+
+// importRecord upserts one row by sku inside its own transaction, so a bad
+// row never undoes the good ones. A dry run rolls every row back. It reports
+// whether the row created a product.
+func (p *Product) importRecord(ctx context.Context, job *importJob, record *bulk.Record) (bool, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		id            string
+		categoryID    string
+		currency      string
+		currentStatus string
+		stored        []byte
+		oldPrice      int64
+	)
+	err = tx.QueryRowContext(ctx, "SELECT id, COALESCE(category_id::text, ''), currency, status, attributes, price_amount FROM products WHERE artisan_id = $1 AND sku = $2 FOR UPDATE", job.artisanID, record.SKU).
+		Scan(&id, &categoryID, &currency, &currentStatus, &stored, &oldPrice)
+	exists := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to fetch product: %v", err)
+	}
+	oldCurrency := currency
+
+	if !exists {
+		var missing []string
+		if record.Name == nil {
+			missing = append(missing, "name")
+		}
+		if record.Price == nil {
+			missing = append(missing, "price")
+		}
+		if record.Category == nil {
+			missing = append(missing, "category")
+		}
+		if len(missing) > 0 {
+			return false, status.Errorf(codes.InvalidArgument, "new products need %s", strings.Join(missing, ", "))
+		}
+		currency = p.currency.Default
+	} else if record.Status != nil && strings.ToLower(strings.TrimSpace(*record.Status)) != currentStatus {
+		return false, status.Errorf(codes.FailedPrecondition, "the product is %s; change its status through the status endpoint, not an import", currentStatus)
+	}
+
+	data := make(map[string]interface{})
+	// Stock goes through the ledger once the product exists.
+	var quantity *int
+	if record.Name != nil {
+		name := strings.TrimSpace(*record.Name)
+		if name == "" || len(name) > 100 {
+			return false, status.Error(codes.InvalidArgument, "name must be between 1 and 100 characters")
+		}
+		data["name"] = name
+	}
+	if record.Description != nil {
+		data["description"] = *record.Description
+	}
+	if record.Currency != nil {
+		if record.Price == nil {
+			return false, status.Error(codes.InvalidArgument, "currency can only be changed together with price")
+		}
+		currency = strings.ToUpper(strings.TrimSpace(*record.Currency))
+	}
+	if record.Price != nil {
+		amount, err := money.Parse(*record.Price, currency)
+		if err != nil {
+			return false, status.Errorf(codes.InvalidArgument, "price: %v", err)
+		}
+		if amount < 0 {
+			return false, status.Error(codes.InvalidArgument, "price cannot be negative")
+		}
+		data["price_amount"] = amount
+		data["currency"] = currency
+	}
+	if record.Category != nil {
+		category, err := p.categoryByKey(ctx, tx, *record.Category)
+		if err != nil {
+			return false, err
+		}
+		categoryID = category.Id
+		data["category_id"] = categoryID
+	}
+	if record.Quantity != nil {
+		value, err := strconv.Atoi(strings.TrimSpace(*record.Quantity))
+		if err != nil || value < 0 {
+			return false, status.Errorf(codes.InvalidArgument, "quantity must be a non-negative integer, got %q", *record.Quantity)
+		}
+		quantity = &value
+	}
+	if record.Tags != nil {
+		tags, err := normalizeTags(record.Tags)
+		if err != nil {
+			return false, err
+		}
+		data["tags"] = pq.Array(tags)
+	}
+
+	// Attributes in a row are merged into the ones the product already has;
+	// a category change re-checks them all against the new schema.
+	_, categoryChanged := data["category_id"]
+	if !exists || record.Attributes != nil || categoryChanged {
+		attributes, err := decodeAttributes(stored)
+		if err != nil {
+			return false, err
+		}
+		if attributes == nil {
+			attributes = make(map[string]string, len(record.Attributes))
+		}
+		for key, value := range record.Attributes {
+			attributes[key] = value
+		}
+		document, err := p.validateAttributes(ctx, tx, categoryID, attributes)
+		if err != nil {
+			return false, err
+		}
+		data["attributes"] = document
+	}
+
+	stock := stockMovement{reason: movementImport, actorID: job.actorID, reference: job.id}
+	now := time.Now()
+	if !exists {
+		productStatus, _, err := initialProductStatus(stringValue(record.Status), "")
+		if err != nil {
+			return false, err
+		}
+		id = uuid.NewString()
+		data["id"] = id
+		data["artisan_id"] = job.artisanID
+		data["sku"] = record.SKU
+		data["status"] = productStatus
+		data["created_at"] = now
+		data["updated_at"] = now
+		if productStatus == productPublished {
+			data["published_at"] = now
+		}
+		data["quantity"] = 0
+		if _, ok := data["tags"]; !ok {
+			data["tags"] = pq.Array([]string{})
+		}
+
+		query, args, err := p.queryBuilder.Insert("products").SetMap(data).ToSql()
+		if err != nil {
+			return false, fmt.Errorf("failed to build SQL query: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			if conflict := skuConflict(err, record.SKU); conflict != nil {
+				return false, conflict
+			}
+			return false, fmt.Errorf("failed to create product: %v", err)
+		}
+		if quantity != nil {
+			if err := p.setStock(ctx, tx, id, int64(*quantity), stock); err != nil {
+				return false, err
+			}
+		}
+		if err := p.syncStockStatus(ctx, tx, id); err != nil {
+			return false, err
+		}
+		if _, err := p.recordRevision(ctx, tx, id, job.actorID, revisionCreated, 0); err != nil {
+			return false, err
+		}
+	} else {
+		if len(data) == 0 && quantity == nil {
+			return false, status.Error(codes.InvalidArgument, "the row has nothing to update")
+		}
+		data["updated_at"] = now
+		data["version"] = squirrel.Expr("version + 1")
+
+		query, args, err := p.queryBuilder.Update("products").SetMap(data).Where(squirrel.Eq{"id": id}).ToSql()
+		if err != nil {
+			return false, fmt.Errorf("failed to build SQL query: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return false, fmt.Errorf("failed to update product: %v", err)
+		}
+		if quantity != nil {
+			if err := p.setStock(ctx, tx, id, int64(*quantity), stock); err != nil {
+				return false, err
+			}
+			if err := p.syncStockStatus(ctx, tx, id); err != nil {
+				return false, err
+			}
+		}
+		if record.Price != nil {
+			if err := p.notifyPriceDrop(ctx, tx, id, oldPrice, oldCurrency); err != nil {
+				return false, err
+			}
+		}
+		if _, err := p.recordRevision(ctx, tx, id, job.actorID, revisionEdited, 0); err != nil {
+			return false, err
+		}
+	}
+
+	if job.dryRun {
+		return !exists, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return !exists, nil
+}
+
+func (p *Product) saveImportProgress(ctx context.Context, id string, result importResult) error {
+	document, err := json.Marshal(result.errors)
+	if err != nil {
+		return fmt.Errorf("failed to encode import errors: %v", err)
+	}
+	_, err = p.db.ExecContext(ctx, `
+		UPDATE product_import_jobs
+		SET created_count = $1, updated_count = $2, failed_count = $3, row_errors = $4
+		WHERE id = $5
+	`, result.created, result.updated, len(result.errors), document, id)
+	if err != nil {
+		return fmt.Errorf("failed to update product import: %v", err)
+	}
+	return nil
+}
+
+// finishImport records the outcome of an import and drops its payload.
+func (p *Product) finishImport(ctx context.Context, id, outcome string, result importResult, message string) error {
+	sort.SliceStable(result.errors, func(i, j int) bool {
+		return result.errors[i].Row < result.errors[j].Row
+	})
+	if result.errors == nil {
+		result.errors = []bulk.RowError{}
+	}
+	document, err := json.Marshal(result.errors)
+	if err != nil {
+		return fmt.Errorf("failed to encode import errors: %v", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		UPDATE product_import_jobs
+		SET status = $1, created_count = $2, updated_count = $3, failed_count = $4, row_errors = $5,
+			error = $6, payload = NULL, finished_at = $7
+		WHERE id = $8
+	`, outcome, result.created, result.updated, len(result.errors), document,
+		sql.NullString{String: message, Valid: message != ""}, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to finish product import: %v", err)
+	}
+	return nil
+}
+
+func (p *Product) ExportProducts(req *genprotos.ExportProductsRequest, stream genprotos.ProductService_ExportProductsServer) error {
+	ctx := stream.Context()
+
+	format, err := bulk.ParseFormat(req.Format)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := uuid.Parse(req.ArtisanId); err != nil {
+		return status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+	}
+	conditions := squirrel.Eq{"p.artisan_id": req.ArtisanId}
+	if req.Status != "" {
+		productStatus := strings.ToLower(strings.TrimSpace(req.Status))
+		if _, ok := productTransitions[productStatus]; !ok {
+			return status.Errorf(codes.InvalidArgument, "unknown product status %q", req.Status)
+		}
+		conditions["p.status"] = productStatus
+	}
+
+	keys, err := p.exportAttributeKeys(ctx, conditions)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := p.queryBuilder.
+		Select("COALESCE(p.sku, '')", "p.name", "COALESCE(p.description, '')", "p.price_amount", "p.currency",
+			"COALESCE(c.slug, p.category_id::text, '')", "p.quantity", "p.status", "p.tags", "p.attributes").
+		From("products p").
+		LeftJoin("product_categories c ON c.id = p.category_id").
+		Where(conditions).
+		OrderBy("p.created_at", "p.id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch products: %v", err)
+	}
+	defer rows.Close()
+
+	buffered := bufio.NewWriterSize(exportStream{stream}, exportChunkSize)
+	writer, err := bulk.NewWriter(format, buffered, keys)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	for rows.Next() {
+		var (
+			record                                               bulk.Record
+			name, description, currency, category, productStatus string
+			priceAmount, quantity                                int64
+			tags                                                 pq.StringArray
+			attributes                                           []byte
+		)
+		if err := rows.Scan(&record.SKU, &name, &description, &priceAmount, &currency, &category, &quantity, &productStatus, &tags, &attributes); err != nil {
+			return fmt.Errorf("failed to scan product row: %v", err)
+		}
+		price := money.Format(priceAmount, currency)
+		quantityText := strconv.FormatInt(quantity, 10)
+		record.Name = &name
+		record.Description = &description
+		record.Price = &price
+		record.Currency = &currency
+		record.Category = &category
+		record.Quantity = &quantityText
+		record.Status = &productStatus
+		record.Tags = tags
+		if record.Attributes, err = decodeAttributes(attributes); err != nil {
+			return err
+		}
+
+		if err := writer.Write(&record); err != nil {
+			return fmt.Errorf("failed to write export: %v", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over product rows: %v", err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %v", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to send export: %v", err)
+	}
+	return nil
+}
+
+// exportAttributeKeys lists every attribute key used by the exported
+// products, which become the attr.<key> columns of a CSV export.
+func (p *Product) exportAttributeKeys(ctx context.Context, conditions squirrel.Eq) ([]string, error) {
+	query, args, err := p.queryBuilder.
+		Select("DISTINCT jsonb_object_keys(p.attributes)").
+		From("products p").
+		Where(conditions).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attribute keys: %v", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan attribute key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over attribute keys: %v", err)
+	}
+	return keys, nil
+}
+
+// exportStream sends everything written to it as export chunks.
+type exportStream struct {
+	stream genprotos.ProductService_ExportProductsServer
+}
+
+func (s exportStream) Write(data []byte) (int, error) {
+	if err := s.stream.Send(&genprotos.ExportChunk{Data: append([]byte(nil), data...)}); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func scanImport(row rowScanner) (*genprotos.ProductImport, error) {
+	var (
+		job                   genprotos.ProductImport
+		document              []byte
+		createdAt             time.Time
+		startedAt, finishedAt sql.NullTime
+	)
+	err := row.Scan(&job.Id, &job.ArtisanId, &job.Format, &job.DryRun, &job.Status, &job.TotalRows,
+		&job.Created, &job.Updated, &job.Failed, &document, &job.Error, &createdAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.CreatedAt = createdAt.Format(time.RFC3339)
+	job.StartedAt = formatNullTime(startedAt)
+	job.FinishedAt = formatNullTime(finishedAt)
+
+	var rowErrors []bulk.RowError
+	if err := json.Unmarshal(document, &rowErrors); err != nil {
+		return nil, fmt.Errorf("failed to decode import errors: %v", err)
+	}
+	for _, rowError := range rowErrors {
+		job.Errors = append(job.Errors, &genprotos.ImportRowError{Row: uint32(rowError.Row), Sku: rowError.SKU, Message: rowError.Message})
+	}
+	return &job, nil
+}
+
+// normalizeSKU trims a sku and checks it is short and free of whitespace.
+func normalizeSKU(value string) (string, error) {
+	sku := strings.TrimSpace(value)
+	if len(sku) > maxSKULength {
+		return "", status.Errorf(codes.InvalidArgument, "sku %q is longer than %d characters", sku, maxSKULength)
+	}
+	if strings.IndexFunc(sku, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return "", status.Errorf(codes.InvalidArgument, "sku %q must not contain spaces", sku)
+	}
+	return sku, nil
+}
+
+// skuConflict turns a unique violation on the artisan's skus into
+// ALREADY_EXISTS. Other errors give nil.
+func skuConflict(err error, sku string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "products_artisan_sku_idx" {
+		return status.Errorf(codes.AlreadyExists, "sku %q is already used by another product", strings.TrimSpace(sku))
+	}
+	return nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+const (
+	reservationHeld      = "held"
+	reservationCommitted = "committed"
+	reservationReleased  = "released"
+)
+
+// stockLine is a quantity of one product taken from or returned to stock.
+type stockLine struct {
+	productID string
+	quantity  int64
+}
+
+// reserveStock takes the ordered quantities out of stock and holds them for
+// the order until expiresAt, recording each as a sale by the buyer. Each
+// decrement only succeeds while enough stock is left, so two checkouts can
+// never sell the same unit. Every short item is reported in one
+// FAILED_PRECONDITION error and nothing is taken.
+func (p *Product) reserveStock(ctx context.Context, tx *sql.Tx, orderID, userID string, items []*genprotos.Item, expiresAt time.Time) error {
+	lines, err := stockLines(items)
+	if err != nil {
+		return err
+	}
+
+	var (
+		shortages []string
+		taken     []string
+	)
+	for _, line := range lines {
+		var left int64
+		err := tx.QueryRowContext(ctx, "UPDATE products SET quantity = quantity - $1 WHERE id = $2 AND quantity >= $1 RETURNING quantity", line.quantity, line.productID).Scan(&left)
+		if err == sql.ErrNoRows {
+			var available sql.NullInt64
+			if err := tx.QueryRowContext(ctx, "SELECT quantity FROM products WHERE id = $1", line.productID).Scan(&available); err == sql.ErrNoRows {
+				return status.Errorf(codes.NotFound, "product %s not found", line.productID)
+			} else if err != nil {
+				return fmt.Errorf("failed to fetch product stock: %v", err)
+			}
+			shortages = append(shortages, fmt.Sprintf("product %s has %d in stock, %d requested", line.productID, available.Int64, line.quantity))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to take product stock: %v", err)
+		}
+		taken = append(taken, line.productID)
+
+		if _, err := p.recordMovement(ctx, tx, line.productID, -line.quantity, left, stockMovement{reason: movementSale, actorID: userID, reference: orderID}); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO stock_reservations (id, order_id, product_id, quantity, status, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, uuid.NewString(), orderID, line.productID, line.quantity, reservationHeld, expiresAt, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to reserve product stock: %v", err)
+		}
+	}
+	if len(shortages) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "insufficient stock: %s", strings.Join(shortages, "; "))
+	}
+
+	// Selling the last unit takes a product out of stock.
+	return p.syncStockStatus(ctx, tx, taken...)
+}
+
+// commitReservations keeps an order's stock for good once it is paid. It
+// refuses orders whose hold ran out before the expiry worker got to them.
+func (p *Product) commitReservations(ctx context.Context, tx *sql.Tx, orderID string) error {
+	if err := checkReservationsHeld(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE stock_reservations SET status = $1 WHERE order_id = $2 AND status = $3", reservationCommitted, orderID, reservationHeld); err != nil {
+		return fmt.Errorf("failed to commit stock reservations: %v", err)
+	}
+	return nil
+}
+
+// checkReservationsHeld refuses orders whose stock hold ran out.
+func checkReservationsHeld(ctx context.Context, tx *sql.Tx, orderID string) error {
+	var expired bool
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM stock_reservations WHERE order_id = $1 AND status = $2 AND expires_at <= $3)", orderID, reservationHeld, time.Now()).Scan(&expired)
+	if err != nil {
+		return fmt.Errorf("failed to check stock reservations: %v", err)
+	}
+	if expired {
+		return status.Errorf(codes.FailedPrecondition, "the stock held for order %s has expired; place the order again", orderID)
+	}
+	return nil
+}
+
+// releaseReservations puts what is still reserved for the given products of
+// an order back in stock, recorded as reason (a cancellation or a return).
+// Orders placed before reservations existed never took stock and have
+// nothing to return; released reservations are never returned twice.
+func (p *Product) releaseReservations(ctx context.Context, tx *sql.Tx, orderID, reason string, productIDs []string) error {
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE stock_reservations SET status = $1, released_at = $2
+		WHERE order_id = $3 AND status <> $1 AND product_id = ANY($4)
+		RETURNING product_id, quantity
+	`, reservationReleased, time.Now(), orderID, pq.Array(productIDs))
+	if err != nil {
+		return fmt.Errorf("failed to release stock reservations: %v", err)
+	}
+	defer rows.Close()
+
+	var lines []stockLine
+	for rows.Next() {
+		var line stockLine
+		if err := rows.Scan(&line.productID, &line.quantity); err != nil {
+			return fmt.Errorf("failed to scan stock reservation: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over stock reservations: %v", err)
+	}
+	rows.Close()
+
+	// Same order as reserveStock so concurrent orders lock rows alike.
+	sort.Slice(lines, func(i, j int) bool { return lines[i].productID < lines[j].productID })
+	ids := make([]string, 0, len(lines))
+	for _, line := range lines {
+		var quantity int64
+		if err := tx.QueryRowContext(ctx, "UPDATE products SET quantity = quantity + $1 WHERE id = $2 RETURNING quantity", line.quantity, line.productID).Scan(&quantity); err != nil {
+			return fmt.Errorf("failed to restock product: %v", err)
+		}
+		if _, err := p.recordMovement(ctx, tx, line.productID, line.quantity, quantity, stockMovement{reason: reason, reference: orderID}); err != nil {
+			return err
+		}
+		ids = append(ids, line.productID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return p.syncStockStatus(ctx, tx, ids...)
+}
+
+// restockReturn puts the items of a received return back in stock and takes
+// them off what is still reserved for the order, so a later cancellation or
+// return of the rest never puts them back twice. Orders placed before
+// reservations existed never took stock and have nothing to put back.
+func (p *Product) restockReturn(ctx context.Context, tx *sql.Tx, ret lockedReturn, actor orderActor) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT oi.product_id, SUM(ri.quantity)
+		FROM return_items ri JOIN order_items oi ON oi.id = ri.order_item_id
+		WHERE ri.return_id = $1 AND oi.product_id IS NOT NULL
+		GROUP BY oi.product_id ORDER BY oi.product_id
+	`, ret.id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch return items: %v", err)
+	}
+	var lines []stockLine
+	for rows.Next() {
+		var line stockLine
+		if err := rows.Scan(&line.productID, &line.quantity); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan return item: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over return items: %v", err)
+	}
+
+	now := time.Now()
+	var ids []string
+	for _, line := range lines {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE stock_reservations SET
+				quantity = CASE WHEN quantity > $1 THEN quantity - $1 ELSE quantity END,
+				status = CASE WHEN quantity > $1 THEN status ELSE $2 END,
+				released_at = CASE WHEN quantity > $1 THEN released_at ELSE $3 END
+			WHERE order_id = $4 AND product_id = $5 AND status <> $2
+		`, line.quantity, reservationReleased, now, ret.orderID, line.productID)
+		if err != nil {
+			return fmt.Errorf("failed to update stock reservation: %v", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to update stock reservation: %v", err)
+		} else if affected == 0 {
+			continue
+		}
+
+		var quantity int64
+		if err := tx.QueryRowContext(ctx, "UPDATE products SET quantity = quantity + $1 WHERE id = $2 RETURNING quantity", line.quantity, line.productID).Scan(&quantity); err != nil {
+			return fmt.Errorf("failed to restock product: %v", err)
+		}
+		movement := stockMovement{reason: movementReturn, actorID: actor.id, reference: ret.orderID, note: "return " + ret.id}
+		if _, err := p.recordMovement(ctx, tx, line.productID, line.quantity, quantity, movement); err != nil {
+			return err
+		}
+		ids = append(ids, line.productID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return p.syncStockStatus(ctx, tx, ids...)
+}
+
+// RunReservationWorker cancels unpaid orders whose stock hold has run out and
+// returns their stock, once per interval until ctx is done. It also forgets
+// expired idempotency keys.
+func (p *Product) RunReservationWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		released, err := p.releaseExpiredReservations(ctx)
+		if err != nil {
+			log.Printf("failed to release expired stock reservations: %v", err)
+		} else if released > 0 {
+			log.Printf("cancelled %d unpaid orders and released their stock", released)
+		}
+		if _, err := p.purgeIdempotencyKeys(ctx); err != nil {
+			log.Printf("%v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Product) releaseExpiredReservations(ctx context.Context) (int, error) {
+	rows, err := p.db.QueryContext(ctx, "SELECT DISTINCT order_id FROM stock_reservations WHERE status = $1 AND expires_at <= $2", reservationHeld, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch expired stock reservations: %v", err)
+	}
+	var orderIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan order id: %v", err)
+		}
+		orderIDs = append(orderIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating over expired stock reservations: %v", err)
+	}
+
+	released := 0
+	for _, orderID := range orderIDs {
+		ok, err := p.expireOrder(ctx, orderID)
+		if err != nil {
+			return released, err
+		}
+		if ok {
+			released++
+		}
+	}
+	return released, nil
+}
+
+// expireOrder cancels one unpaid order, re-checking under the order lock in
+// case it was paid or cancelled since it was picked.
+func (p *Product) expireOrder(ctx context.Context, orderID string) (bool, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOrder(ctx, tx, orderID); err != nil {
+		return false, err
+	}
+	var expired bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM stock_reservations WHERE order_id = $1 AND status = $2 AND expires_at <= $3)", orderID, reservationHeld, time.Now()).Scan(&expired)
+	if err != nil {
+		return false, fmt.Errorf("failed to check stock reservations: %v", err)
+	}
+	if !expired {
+		return false, nil
+	}
+
+	if _, err := p.transitionOrder(ctx, tx, orderID, orderCancelled, orderActor{role: roleSystem}, "stock hold expired before payment"); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	p.queuePayments()
+	return true, nil
+}
+
+// lockOrder locks an order row for the rest of the transaction and returns
+// its status.
+func lockOrder(ctx context.Context, tx *sql.Tx, orderID string) (string, error) {
+	var orderStatus string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&orderStatus)
+	if err == sql.ErrNoRows {
+		return "", status.Errorf(codes.NotFound, "order %s not found", orderID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch order: %v", err)
+	}
+	return orderStatus, nil
+}
+
+// stockLines adds up the quantity ordered per product, sorted by product id
+// so concurrent orders always lock products in the same order.
+func stockLines(items []*genprotos.Item) ([]stockLine, error) {
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "an order needs at least one item")
+	}
+
+	totals := make(map[string]int64, len(items))
+	for _, item := range items {
+		if item.Quantity == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "quantity of product %s must be at least 1", item.ProductId)
+		}
+		if _, err := uuid.Parse(item.ProductId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "product_id %q is not a valid id", item.ProductId)
+		}
+		if item.Quantity > 1<<31-1 || totals[item.ProductId]+int64(item.Quantity) > 1<<31-1 {
+			return nil, status.Errorf(codes.InvalidArgument, "quantity of product %s is too large", item.ProductId)
+		}
+		totals[item.ProductId] += int64(item.Quantity)
+	}
+
+	lines := make([]stockLine, 0, len(totals))
+	for productID, quantity := range totals {
+		lines = append(lines, stockLine{productID: productID, quantity: quantity})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].productID < lines[j].productID })
+	return lines, nil
+}
@@ -0,0 +1,439 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+const (
+	movementInitial      = "initial"
+	movementSale         = "sale"
+	movementCancellation = "cancellation"
+	movementReturn       = "return"
+	movementAdjustment   = "adjustment"
+	movementImport       = "import"
+	movementCorrection   = "correction"
+)
+
+var movementReasons = []string{movementInitial, movementSale, movementCancellation, movementReturn, movementAdjustment, movementImport, movementCorrection}
+
+const movementColumns = "id, product_id, change, quantity_after, reason, actor_id, reference, note, created_at"
+
+// stockMovement says why a product's stock changed.
+type stockMovement struct {
+	reason    string
+	actorID   string
+	reference string
+	note      string
+}
+
+func (p *Product) ListStockMovements(ctx context.Context, req *genprotos.ListStockMovementsRequest) (*genprotos.ListStockMovementsResponse, error) {
+	if req.Reason != "" && !containsString(movementReasons, req.Reason) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown reason %q", req.Reason)
+	}
+
+	var response genprotos.ListStockMovementsResponse
+	err := p.db.QueryRowContext(ctx, `
+		SELECT p.quantity, COALESCE((SELECT SUM(change) FROM inventory_movements WHERE product_id = p.id), 0)
+		FROM products p WHERE p.id = $1
+	`, req.ProductId).Scan(&response.Quantity, &response.LedgerQuantity)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product stock: %v", err)
+	}
+
+	query := p.queryBuilder.
+		Select(movementColumns).
+		From("inventory_movements").
+		Where("product_id = ?", req.ProductId).
+		OrderBy("created_at DESC", "id")
+	count := p.queryBuilder.
+		Select("COUNT(*)").
+		From("inventory_movements").
+		Where("product_id = ?", req.ProductId)
+	if req.Reason != "" {
+		query = query.Where("reason = ?", req.Reason)
+		count = count.Where("reason = ?", req.Reason)
+	}
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock movements: %v", err)
+	}
+	defer rows.Close()
+
+	response.Movements = make([]*genprotos.StockMovement, 0)
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement row: %v", err)
+		}
+		response.Movements = append(response.Movements, movement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stock movement rows: %v", err)
+	}
+
+	sqlQuery, args, err = count.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if err := p.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&response.Total); err != nil {
+		return nil, fmt.Errorf("failed to count stock movements: %v", err)
+	}
+
+	return &response, nil
+}
+
+func (p *Product) AdjustStock(ctx context.Context, req *genprotos.AdjustStockRequest) (*genprotos.StockMovement, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = movementAdjustment
+	}
+	if reason != movementAdjustment && reason != movementReturn {
+		return nil, status.Errorf(codes.InvalidArgument, "reason must be %s or %s", movementAdjustment, movementReturn)
+	}
+	if req.Change == 0 {
+		return nil, status.Error(codes.InvalidArgument, "change must not be zero")
+	}
+	if req.Change > 1<<31-1 || req.Change < -(1<<31-1) {
+		return nil, status.Error(codes.InvalidArgument, "change is too large")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	movement, err := p.changeStock(ctx, tx, req.ProductId, req.Change, stockMovement{
+		reason:    reason,
+		actorID:   req.ActorId,
+		reference: req.Reference,
+		note:      req.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.syncStockStatus(ctx, tx, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return movement, nil
+}
+
+func (p *Product) SetLowStockThreshold(ctx context.Context, req *genprotos.SetLowStockThresholdRequest) (*genprotos.StockLevel, error) {
+	if req.Threshold > 1<<31-1 {
+		return nil, status.Error(codes.InvalidArgument, "threshold is too large")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	threshold := sql.NullInt64{Int64: int64(req.Threshold), Valid: req.Threshold > 0}
+	level := genprotos.StockLevel{ProductId: req.ProductId, LowStockThreshold: req.Threshold}
+	err = tx.QueryRowContext(ctx, "UPDATE products SET low_stock_threshold = $1 WHERE id = $2 RETURNING quantity", threshold, req.ProductId).Scan(&level.Quantity)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set low stock threshold: %v", err)
+	}
+	if err := p.checkLowStock(ctx, tx, req.ProductId, level.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	level.LowStock = threshold.Valid && level.Quantity <= threshold.Int64
+	return &level, nil
+}
+
+func (p *Product) ListLowStockAlerts(ctx context.Context, req *genprotos.ListLowStockAlertsRequest) (*genprotos.ListLowStockAlertsResponse, error) {
+	if req.ArtisanId == "" {
+		return nil, status.Error(codes.InvalidArgument, "artisan_id is required")
+	}
+
+	query := p.queryBuilder.
+		Select("a.id", "a.product_id", "a.artisan_id", "p.name", "a.threshold", "a.quantity", "a.created_at", "a.updated_at", "a.resolved_at").
+		From("low_stock_alerts a").
+		Join("products p ON p.id = a.product_id").
+		Where("a.artisan_id = ?", req.ArtisanId).
+		OrderBy("a.created_at DESC")
+	if !req.IncludeResolved {
+		query = query.Where("a.resolved_at IS NULL")
+	}
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch low stock alerts: %v", err)
+	}
+	defer rows.Close()
+
+	alerts := make([]*genprotos.LowStockAlert, 0)
+	for rows.Next() {
+		var (
+			alert                genprotos.LowStockAlert
+			createdAt, updatedAt time.Time
+			resolvedAt           sql.NullTime
+		)
+		if err := rows.Scan(&alert.Id, &alert.ProductId, &alert.ArtisanId, &alert.ProductName, &alert.Threshold, &alert.Quantity, &createdAt, &updatedAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan low stock alert row: %v", err)
+		}
+		alert.CreatedAt = createdAt.Format(time.RFC3339)
+		alert.UpdatedAt = updatedAt.Format(time.RFC3339)
+		alert.ResolvedAt = formatNullTime(resolvedAt)
+		alerts = append(alerts, &alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over low stock alert rows: %v", err)
+	}
+
+	return &genprotos.ListLowStockAlertsResponse{Alerts: alerts}, nil
+}
+
+// ReconcileStock finds products whose quantity no longer matches the sum of
+// their ledger, which only happens when stock is written around it. The
+// product's quantity is taken as the truth: repairing records the gap as a
+// correction instead of changing stock.
+func (p *Product) ReconcileStock(ctx context.Context, req *genprotos.ReconcileStockRequest) (*genprotos.ReconcileStockResponse, error) {
+	if req.ArtisanId == "" && req.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "artisan_id or product_id is required")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	filter := "($1 = '' OR p.artisan_id::text = $1) AND ($2 = '' OR p.id::text = $2)"
+	if req.Repair {
+		// Hold the products still so no sale lands between counting and
+		// correcting.
+		if _, err := tx.ExecContext(ctx, "SELECT p.id FROM products p WHERE "+filter+" ORDER BY p.id FOR UPDATE", req.ArtisanId, req.ProductId); err != nil {
+			return nil, fmt.Errorf("failed to lock products: %v", err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT p.id, p.quantity, COALESCE(SUM(m.change), 0)
+		FROM products p
+		LEFT JOIN inventory_movements m ON m.product_id = p.id
+		WHERE `+filter+`
+		GROUP BY p.id, p.quantity
+		HAVING p.quantity <> COALESCE(SUM(m.change), 0)
+		ORDER BY p.id
+	`, req.ArtisanId, req.ProductId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare stock with the ledger: %v", err)
+	}
+	defer rows.Close()
+
+	discrepancies := make([]*genprotos.StockDiscrepancy, 0)
+	for rows.Next() {
+		var discrepancy genprotos.StockDiscrepancy
+		if err := rows.Scan(&discrepancy.ProductId, &discrepancy.Quantity, &discrepancy.LedgerQuantity); err != nil {
+			return nil, fmt.Errorf("failed to scan stock discrepancy: %v", err)
+		}
+		discrepancies = append(discrepancies, &discrepancy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stock discrepancies: %v", err)
+	}
+	rows.Close()
+
+	if !req.Repair || len(discrepancies) == 0 {
+		return &genprotos.ReconcileStockResponse{Discrepancies: discrepancies}, nil
+	}
+
+	for _, discrepancy := range discrepancies {
+		_, err := p.recordMovement(ctx, tx, discrepancy.ProductId, discrepancy.Quantity-discrepancy.LedgerQuantity, discrepancy.Quantity, stockMovement{
+			reason:  movementCorrection,
+			actorID: req.ActorId,
+			note:    fmt.Sprintf("ledger was %d, stock is %d", discrepancy.LedgerQuantity, discrepancy.Quantity),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &genprotos.ReconcileStockResponse{Discrepancies: discrepancies, Repaired: true}, nil
+}
+
+// changeStock moves a product's stock by change, refusing to take it below
+// zero, and records the movement.
+func (p *Product) changeStock(ctx context.Context, q queryer, productID string, change int64, movement stockMovement) (*genprotos.StockMovement, error) {
+	var quantity int64
+	err := q.QueryRowContext(ctx, "UPDATE products SET quantity = quantity + $1 WHERE id = $2 AND quantity + $1 >= 0 RETURNING quantity", change, productID).Scan(&quantity)
+	if err == sql.ErrNoRows {
+		var available int64
+		if err := q.QueryRowContext(ctx, "SELECT quantity FROM products WHERE id = $1", productID).Scan(&available); err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "product %s not found", productID)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to fetch product stock: %v", err)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "product %s has %d in stock, cannot remove %d", productID, available, -change)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to change product stock: %v", err)
+	}
+	return p.recordMovement(ctx, q, productID, change, quantity, movement)
+}
+
+// setStock sets a product's stock to an absolute quantity, as edits and
+// imports do, and records the difference.
+func (p *Product) setStock(ctx context.Context, q queryer, productID string, quantity int64, movement stockMovement) error {
+	var before int64
+	err := q.QueryRowContext(ctx, "SELECT quantity FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&before)
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "product %s not found", productID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch product stock: %v", err)
+	}
+	if before == quantity {
+		return nil
+	}
+
+	if _, err := q.ExecContext(ctx, "UPDATE products SET quantity = $1 WHERE id = $2", quantity, productID); err != nil {
+		return fmt.Errorf("failed to update product stock: %v", err)
+	}
+	_, err = p.recordMovement(ctx, q, productID, quantity-before, quantity, movement)
+	return err
+}
+
+// recordMovement appends a stock change to the product's ledger, inside the
+// caller's transaction, raises or resolves its low-stock alert and lets
+// shoppers who saved a sold-out product know it is back.
+// quantityAfter is the stock the change left the product with.
+func (p *Product) recordMovement(ctx context.Context, q queryer, productID string, change, quantityAfter int64, movement stockMovement) (*genprotos.StockMovement, error) {
+	var actor sql.NullString
+	if movement.actorID != "" {
+		if _, err := uuid.Parse(movement.actorID); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "actor_id %q is not a valid id", movement.actorID)
+		}
+		actor = sql.NullString{String: movement.actorID, Valid: true}
+	}
+
+	recorded, err := scanMovement(q.QueryRowContext(ctx, `
+		INSERT INTO inventory_movements (id, product_id, change, quantity_after, reason, actor_id, reference, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+movementColumns,
+		uuid.NewString(), productID, change, quantityAfter, movement.reason, actor,
+		sql.NullString{String: movement.reference, Valid: movement.reference != ""},
+		sql.NullString{String: movement.note, Valid: movement.note != ""}, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record stock movement: %v", err)
+	}
+
+	if err := p.checkLowStock(ctx, q, productID, quantityAfter); err != nil {
+		return nil, err
+	}
+	if quantityAfter > 0 && quantityAfter-change <= 0 {
+		if err := p.notifyBackInStock(ctx, q, productID); err != nil {
+			return nil, err
+		}
+	}
+	return recorded, nil
+}
+
+// checkLowStock opens an alert when a product is at or below its artisan's
+// threshold, keeps an open alert's quantity current, and resolves it once
+// the product is restocked above the threshold or the threshold is removed.
+func (p *Product) checkLowStock(ctx context.Context, q queryer, productID string, quantity int64) error {
+	var (
+		threshold sql.NullInt64
+		artisanID sql.NullString
+	)
+	err := q.QueryRowContext(ctx, "SELECT low_stock_threshold, artisan_id FROM products WHERE id = $1", productID).Scan(&threshold, &artisanID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch low stock threshold: %v", err)
+	}
+
+	now := time.Now()
+	if !threshold.Valid || !artisanID.Valid || quantity > threshold.Int64 {
+		_, err := q.ExecContext(ctx, "UPDATE low_stock_alerts SET quantity = $1, updated_at = $2, resolved_at = $2 WHERE product_id = $3 AND resolved_at IS NULL", quantity, now, productID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve low stock alert: %v", err)
+		}
+		return nil
+	}
+
+	var opened bool
+	err = q.QueryRowContext(ctx, `
+		INSERT INTO low_stock_alerts (id, product_id, artisan_id, threshold, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (product_id) WHERE resolved_at IS NULL
+		DO UPDATE SET threshold = EXCLUDED.threshold, quantity = EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+		RETURNING xmax = 0
+	`, uuid.NewString(), productID, artisanID.String, threshold.Int64, quantity, now).Scan(&opened)
+	if err != nil {
+		return fmt.Errorf("failed to raise low stock alert: %v", err)
+	}
+	if opened {
+		log.Printf("product %s is low on stock: %d left, threshold %d", productID, quantity, threshold.Int64)
+	}
+	return nil
+}
+
+func scanMovement(row rowScanner) (*genprotos.StockMovement, error) {
+	var (
+		movement               genprotos.StockMovement
+		actor, reference, note sql.NullString
+		createdAt              time.Time
+	)
+	err := row.Scan(&movement.Id, &movement.ProductId, &movement.Change, &movement.QuantityAfter, &movement.Reason, &actor, &reference, &note, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	movement.ActorId = actor.String
+	movement.Reference = reference.String
+	movement.Note = note.String
+	movement.CreatedAt = createdAt.Format(time.RFC3339)
+	return &movement, nil
+}
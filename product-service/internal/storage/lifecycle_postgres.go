@@ -0,0 +1,338 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+)
+
+const (
+	productDraft      = "draft"
+	productPublished  = "published"
+	productArchived   = "archived"
+	productOutOfStock = "out_of_stock"
+)
+
+// productTransitions lists the statuses a product may move to from each status.
+var productTransitions = map[string][]string{
+	productDraft:      {productPublished, productArchived},
+	productPublished:  {productArchived, productOutOfStock},
+	productOutOfStock: {productPublished, productArchived},
+	productArchived:   {productDraft},
+}
+
+func (p *Product) ChangeProductStatus(ctx context.Context, req *genprotos.ChangeProductStatusRequest) (*genprotos.ProductStatusResponse, error) {
+	target := strings.ToLower(strings.TrimSpace(req.Status))
+	if _, ok := productTransitions[target]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown product status %q", req.Status)
+	}
+	publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	if publishAt != nil && target != productPublished {
+		return nil, status.Error(codes.InvalidArgument, "publish_at only applies when publishing")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM products WHERE id = $1 FOR UPDATE", req.Id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product status: %v", err)
+	}
+
+	now := time.Now()
+	data := map[string]interface{}{
+		"updated_at": now,
+		"version":    squirrel.Expr("version + 1"),
+	}
+	switch {
+	case publishAt != nil && publishAt.After(now):
+		if current != productDraft {
+			return nil, status.Errorf(codes.FailedPrecondition, "only drafts can be scheduled; product is %s", current)
+		}
+		data["publish_at"] = *publishAt
+	case current == target:
+		return p.productStatus(ctx, tx, req.Id)
+	case !containsString(productTransitions[current], target):
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot move product from %s to %s", current, target)
+	default:
+		data["status"] = target
+		switch target {
+		case productPublished:
+			// Publishing something with no stock lands it in out_of_stock.
+			data["status"] = squirrel.Expr("CASE WHEN quantity > 0 THEN ? ELSE ? END", productPublished, productOutOfStock)
+			data["publish_at"] = nil
+			if current == productDraft {
+				data["published_at"] = now
+			}
+		case productArchived:
+			data["archived_at"] = now
+			data["publish_at"] = nil
+		case productDraft:
+			data["archived_at"] = nil
+		}
+	}
+
+	query, args, err := p.queryBuilder.Update("products").
+		SetMap(data).
+		Where(squirrel.Eq{"id": req.Id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to update product status: %v", err)
+	}
+	if _, err := p.recordRevision(ctx, tx, req.Id, req.ActorId, revisionStatus, 0); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.productStatus(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return resp, nil
+}
+
+// RunLifecycleWorker publishes scheduled drafts and keeps published and
+// out_of_stock in line with stock levels, once per interval until ctx is done.
+func (p *Product) RunLifecycleWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := p.publishScheduled(ctx)
+		if err != nil {
+			log.Printf("failed to publish scheduled products: %v", err)
+		} else if published > 0 {
+			log.Printf("published %d scheduled products", published)
+		}
+		if err := p.syncStockStatus(ctx, p.db); err != nil {
+			log.Printf("failed to sync product stock status: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Product) publishScheduled(ctx context.Context) (int64, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := updatedProductIDs(ctx, tx, `
+		UPDATE products
+		SET status = CASE WHEN quantity > 0 THEN $1 ELSE $2 END,
+			published_at = publish_at, publish_at = NULL, version = version + 1, updated_at = $3
+		WHERE status = $4 AND publish_at <= $3
+		RETURNING id
+	`, productPublished, productOutOfStock, time.Now(), productDraft)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if _, err := p.recordRevision(ctx, tx, id, "", revisionPublished, 0); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return int64(len(ids)), nil
+}
+
+// archiveProduct retires a product that can no longer be deleted.
+func (p *Product) archiveProduct(ctx context.Context, id, actorID string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE products
+		SET status = $1, archived_at = $2, publish_at = NULL, version = version + 1, updated_at = $2
+		WHERE id = $3 AND status <> $1
+	`, productArchived, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to archive product: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+	if _, err := p.recordRevision(ctx, tx, id, actorID, revisionArchived, 0); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// updatedProductIDs runs an UPDATE ... RETURNING id and collects the ids.
+func updatedProductIDs(ctx context.Context, q queryer, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update products: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan product id: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over product rows: %v", err)
+	}
+	return ids, nil
+}
+
+// syncStockStatus flips live products between published and out_of_stock
+// to match their quantity. With no ids it checks every product. The flip
+// follows stock, so like stock changes it does not create a revision.
+func (p *Product) syncStockStatus(ctx context.Context, q queryer, ids ...string) error {
+	query := p.queryBuilder.Update("products").
+		Set("status", squirrel.Expr("CASE WHEN quantity > 0 THEN ? ELSE ? END", productPublished, productOutOfStock)).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"status": onSaleStatuses}).
+		Where("(status = ?) <> (quantity > 0)", productPublished)
+	if len(ids) > 0 {
+		query = query.Where(squirrel.Eq{"id": ids})
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if _, err := q.ExecContext(ctx, sqlQuery, args...); err != nil {
+		return fmt.Errorf("failed to update stock status: %v", err)
+	}
+	return nil
+}
+
+func (p *Product) productStatus(ctx context.Context, q queryer, id string) (*genprotos.ProductStatusResponse, error) {
+	var (
+		resp                               genprotos.ProductStatusResponse
+		publishAt, publishedAt, archivedAt sql.NullTime
+		updatedAt                          time.Time
+	)
+	err := q.QueryRowContext(ctx, "SELECT id, status, publish_at, published_at, archived_at, updated_at FROM products WHERE id = $1", id).
+		Scan(&resp.Id, &resp.Status, &publishAt, &publishedAt, &archivedAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product status: %v", err)
+	}
+	resp.PublishAt = formatNullTime(publishAt)
+	resp.PublishedAt = formatNullTime(publishedAt)
+	resp.ArchivedAt = formatNullTime(archivedAt)
+	resp.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return &resp, nil
+}
+
+// initialProductStatus works out the status and schedule of a new product.
+// Only draft and published are accepted; a future publish_at keeps it a draft.
+func initialProductStatus(requested, publishAt string) (string, *time.Time, error) {
+	productStatus := strings.ToLower(strings.TrimSpace(requested))
+	if productStatus == "" {
+		productStatus = productDraft
+	}
+	if productStatus != productDraft && productStatus != productPublished {
+		return "", nil, status.Errorf(codes.InvalidArgument, "new products must be draft or published, got %q", requested)
+	}
+
+	at, err := parsePublishAt(publishAt)
+	if err != nil {
+		return "", nil, err
+	}
+	if at != nil && at.After(time.Now()) {
+		return productDraft, at, nil
+	}
+	if at != nil {
+		productStatus = productPublished
+	}
+	return productStatus, nil, nil
+}
+
+// onSaleStatuses are the statuses buyers can see a product in. Out of stock
+// products stay visible, they are published products waiting for stock.
+var onSaleStatuses = []string{productPublished, productOutOfStock}
+
+// onSale reports whether buyers can see a product in this status.
+func onSale(productStatus string) bool {
+	return containsString(onSaleStatuses, productStatus)
+}
+
+// productStatusFilter validates a listing status filter, defaulting to published.
+func productStatusFilter(value string) (string, error) {
+	productStatus := strings.ToLower(strings.TrimSpace(value))
+	if productStatus == "" {
+		return productPublished, nil
+	}
+	if _, ok := productTransitions[productStatus]; !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unknown product status %q", value)
+	}
+	return productStatus, nil
+}
+
+func parsePublishAt(value string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	at, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "publish_at must be an RFC 3339 timestamp: %v", err)
+	}
+	return &at, nil
+}
+
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,442 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/media"
+)
+
+type storedThumbnail struct {
+	Size   string `json:"size"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Key    string `json:"key"`
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+const productImageColumns = "id, product_id, object_key, content_type, size_bytes, width, height, position, is_primary, thumbnails, created_at"
+
+func (p *Product) UploadProductImage(ctx context.Context, req *genprotos.UploadProductImageRequest) (*genprotos.ProductImage, error) {
+	if err := p.ensureProductExists(ctx, p.db, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	processed, err := media.ProcessImage(req.Data, req.ContentType, p.mediaConfig)
+	if err != nil {
+		if errors.Is(err, media.ErrInvalidImage) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, fmt.Errorf("failed to process image: %v", err)
+	}
+
+	imageID := uuid.NewString()
+	prefix := path.Join("products", req.ProductId, imageID)
+
+	// Upload every variant before touching the database so a row never
+	// points at a missing object. On failure whatever was stored is removed.
+	var uploaded []string
+	cleanup := func() {
+		for _, key := range uploaded {
+			if err := p.media.Delete(context.Background(), key); err != nil {
+				log.Printf("failed to remove orphaned object %s: %v", key, err)
+			}
+		}
+	}
+
+	originalKey := path.Join(prefix, "original."+processed.Original.Ext)
+	if err := p.media.Put(ctx, originalKey, processed.Original.ContentType, processed.Original.Data); err != nil {
+		return nil, err
+	}
+	uploaded = append(uploaded, originalKey)
+
+	thumbnails := make([]storedThumbnail, 0, len(processed.Thumbnails))
+	for _, thumb := range processed.Thumbnails {
+		key := path.Join(prefix, thumb.Name+"."+thumb.Ext)
+		if err := p.media.Put(ctx, key, thumb.ContentType, thumb.Data); err != nil {
+			cleanup()
+			return nil, err
+		}
+		uploaded = append(uploaded, key)
+		thumbnails = append(thumbnails, storedThumbnail{
+			Size:   thumb.Name,
+			Width:  thumb.Width,
+			Height: thumb.Height,
+			Key:    key,
+		})
+	}
+
+	thumbnailsJSON, err := json.Marshal(thumbnails)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to marshal thumbnails: %v", err)
+	}
+
+	image, err := p.insertProductImage(ctx, req, imageID, originalKey, processed, thumbnailsJSON)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (p *Product) insertProductImage(ctx context.Context, req *genprotos.UploadProductImageRequest, imageID, key string, processed *media.ProcessedImage, thumbnailsJSON []byte) (*genprotos.ProductImage, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the product row so concurrent uploads get distinct positions.
+	if _, err := tx.ExecContext(ctx, "SELECT id FROM products WHERE id = $1 FOR UPDATE", req.ProductId); err != nil {
+		return nil, fmt.Errorf("failed to lock product: %v", err)
+	}
+
+	var position, count int
+	err = tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(position) + 1, 0), COUNT(*) FROM product_images WHERE product_id = $1", req.ProductId).
+		Scan(&position, &count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image positions: %v", err)
+	}
+
+	isPrimary := req.MakePrimary || count == 0
+	if isPrimary {
+		if _, err := tx.ExecContext(ctx, "UPDATE product_images SET is_primary = FALSE WHERE product_id = $1", req.ProductId); err != nil {
+			return nil, fmt.Errorf("failed to reset primary image: %v", err)
+		}
+	}
+
+	data := map[string]interface{}{
+		"id":           imageID,
+		"product_id":   req.ProductId,
+		"object_key":   key,
+		"content_type": processed.Original.ContentType,
+		"size_bytes":   len(processed.Original.Data),
+		"width":        processed.Original.Width,
+		"height":       processed.Original.Height,
+		"position":     position,
+		"is_primary":   isPrimary,
+		"thumbnails":   thumbnailsJSON,
+		"created_at":   time.Now(),
+	}
+
+	query, args, err := p.queryBuilder.Insert("product_images").
+		SetMap(data).
+		Suffix("RETURNING " + productImageColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	image, err := p.scanProductImage(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert product image: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return image.ProductImage, nil
+}
+
+func (p *Product) ListProductImages(ctx context.Context, req *genprotos.ListProductImagesRequest) (*genprotos.ListProductImagesResponse, error) {
+	if err := p.ensureProductExists(ctx, p.db, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	images, err := p.productImages(ctx, p.db, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &genprotos.ListProductImagesResponse{Images: images}, nil
+}
+
+func (p *Product) ReorderProductImages(ctx context.Context, req *genprotos.ReorderProductImagesRequest) (*genprotos.ListProductImagesResponse, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	current, err := p.productImages(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order has to mention every image exactly once.
+	known := make(map[string]bool, len(current))
+	for _, image := range current {
+		known[image.Id] = true
+	}
+	if len(req.ImageIds) != len(current) {
+		return nil, status.Errorf(codes.InvalidArgument, "expected %d image ids, got %d", len(current), len(req.ImageIds))
+	}
+	for position, id := range req.ImageIds {
+		if !known[id] {
+			return nil, status.Errorf(codes.InvalidArgument, "image %s does not belong to product %s or is listed twice", id, req.ProductId)
+		}
+		delete(known, id)
+
+		if _, err := tx.ExecContext(ctx, "UPDATE product_images SET position = $1 WHERE id = $2", position, id); err != nil {
+			return nil, fmt.Errorf("failed to update image position: %v", err)
+		}
+	}
+
+	images, err := p.productImages(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &genprotos.ListProductImagesResponse{Images: images}, nil
+}
+
+func (p *Product) SetPrimaryProductImage(ctx context.Context, req *genprotos.SetPrimaryProductImageRequest) (*genprotos.ProductImage, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE product_images SET is_primary = FALSE WHERE product_id = $1", req.ProductId); err != nil {
+		return nil, fmt.Errorf("failed to reset primary image: %v", err)
+	}
+
+	image, err := p.scanProductImage(tx.QueryRowContext(ctx,
+		"UPDATE product_images SET is_primary = TRUE WHERE id = $1 AND product_id = $2 RETURNING "+productImageColumns,
+		req.ImageId, req.ProductId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "image %s not found for product %s", req.ImageId, req.ProductId)
+		}
+		return nil, fmt.Errorf("failed to set primary image: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return image.ProductImage, nil
+}
+
+func (p *Product) DeleteProductImage(ctx context.Context, req *genprotos.DeleteProductImageRequest) (*genprotos.Message, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	image, err := p.scanProductImage(tx.QueryRowContext(ctx,
+		"DELETE FROM product_images WHERE id = $1 AND product_id = $2 RETURNING "+productImageColumns,
+		req.ImageId, req.ProductId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "image %s not found for product %s", req.ImageId, req.ProductId)
+		}
+		return nil, fmt.Errorf("failed to delete image: %v", err)
+	}
+
+	// Keep positions dense and promote the next image if the primary went away.
+	_, err = tx.ExecContext(ctx, "UPDATE product_images SET position = position - 1 WHERE product_id = $1 AND position > $2", req.ProductId, image.Position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compact image positions: %v", err)
+	}
+	if image.IsPrimary {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE product_images SET is_primary = TRUE
+			WHERE id = (SELECT id FROM product_images WHERE product_id = $1 ORDER BY position LIMIT 1)
+		`, req.ProductId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to promote primary image: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	// Orders placed while this was the primary image still show it.
+	keys := image.objectKeys()
+	var ordered bool
+	if err := p.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM order_items WHERE image_key = $1)", image.key).Scan(&ordered); err != nil {
+		log.Printf("failed to check order history of image %s, keeping it: %v", image.key, err)
+		ordered = true
+	}
+	if ordered {
+		keys = keys[1:]
+	}
+	p.deleteObjects(keys)
+
+	return &genprotos.Message{Message: fmt.Sprintf("Image with ID %s deleted successfully", req.ImageId)}, nil
+}
+
+// productImages returns all images of a product ordered by position.
+func (p *Product) productImages(ctx context.Context, q queryer, productID string) ([]*genprotos.ProductImage, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+productImageColumns+" FROM product_images WHERE product_id = $1 ORDER BY position", productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product images: %v", err)
+	}
+	defer rows.Close()
+
+	images := make([]*genprotos.ProductImage, 0)
+	for rows.Next() {
+		image, err := p.scanProductImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product image row: %v", err)
+		}
+		images = append(images, image.ProductImage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over product image rows: %v", err)
+	}
+
+	return images, nil
+}
+
+// attachPrimaryImages fills Product.PrimaryImage for a page of listing results
+// with a single query.
+func (p *Product) attachPrimaryImages(ctx context.Context, products []*genprotos.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*genprotos.Product, len(products))
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		byID[product.Id] = product
+		ids = append(ids, product.Id)
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT "+productImageColumns+" FROM product_images WHERE is_primary AND product_id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to fetch primary images: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image, err := p.scanProductImage(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan product image row: %v", err)
+		}
+		byID[image.ProductId].PrimaryImage = image.ProductImage
+	}
+
+	return rows.Err()
+}
+
+// imageObjectKeys lists every stored object (original and thumbnails) for the
+// images matching the condition.
+func (p *Product) imageObjectKeys(ctx context.Context, q queryer, condition string, args ...interface{}) ([]string, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+productImageColumns+" FROM product_images WHERE "+condition, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product images: %v", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		image, err := p.scanProductImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product image row: %v", err)
+		}
+		keys = append(keys, image.objectKeys()...)
+	}
+
+	return keys, rows.Err()
+}
+
+// deleteObjects removes objects from the media store after the owning rows are
+// gone. Failures only leave orphans behind, so they are logged, not returned.
+func (p *Product) deleteObjects(keys []string) {
+	for _, key := range keys {
+		if err := p.media.Delete(context.Background(), key); err != nil {
+			log.Printf("failed to delete object %s: %v", key, err)
+		}
+	}
+}
+
+func (p *Product) ensureProductExists(ctx context.Context, q queryer, productID string) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to verify product ID: %v", err)
+	}
+	if !exists {
+		return status.Errorf(codes.NotFound, "product with ID %s not found", productID)
+	}
+	return nil
+}
+
+type scannedImage struct {
+	*genprotos.ProductImage
+	key        string
+	thumbnails []storedThumbnail
+}
+
+func (i *scannedImage) objectKeys() []string {
+	keys := []string{i.key}
+	for _, thumb := range i.thumbnails {
+		keys = append(keys, thumb.Key)
+	}
+	return keys
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (p *Product) scanProductImage(row rowScanner) (*scannedImage, error) {
+	var (
+		image          genprotos.ProductImage
+		key            string
+		thumbnailsJSON []byte
+		createdAt      time.Time
+	)
+	err := row.Scan(&image.Id, &image.ProductId, &key, &image.ContentType, &image.SizeBytes,
+		&image.Width, &image.Height, &image.Position, &image.IsPrimary, &thumbnailsJSON, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var thumbnails []storedThumbnail
+	if err := json.Unmarshal(thumbnailsJSON, &thumbnails); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal thumbnails: %v", err)
+	}
+
+	image.Url = p.media.URL(key)
+	image.CreatedAt = createdAt.Format(time.RFC3339)
+	for _, thumb := range thumbnails {
+		image.Thumbnails = append(image.Thumbnails, &genprotos.Thumbnail{
+			Size:   thumb.Size,
+			Width:  uint32(thumb.Width),
+			Height: uint32(thumb.Height),
+			Url:    p.media.URL(thumb.Key),
+		})
+	}
+
+	return &scannedImage{ProductImage: &image, key: key, thumbnails: thumbnails}, nil
+}
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+)
+
+const (
+	notificationPriceDrop    = "price_drop"
+	notificationBackInStock  = "back_in_stock"
+	notificationOrderStatus  = "order_status"
+	notificationReturnStatus = "return_status"
+)
+
+func (p *Product) ListNotifications(ctx context.Context, req *genprotos.ListNotificationsRequest) (*genprotos.ListNotificationsResponse, error) {
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", req.UserId)
+	}
+
+	query := p.queryBuilder.
+		Select("id", "user_id", "kind", "COALESCE(product_id::text, '')", "message", "created_at", "read_at").
+		From("notifications").
+		Where("user_id = ?", req.UserId).
+		OrderBy("created_at DESC", "id")
+	if req.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications: %v", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*genprotos.Notification, 0)
+	for rows.Next() {
+		var (
+			notification genprotos.Notification
+			createdAt    time.Time
+			readAt       sql.NullTime
+		)
+		if err := rows.Scan(&notification.Id, &notification.UserId, &notification.Kind, &notification.ProductId, &notification.Message, &createdAt, &readAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification row: %v", err)
+		}
+		notification.CreatedAt = createdAt.Format(time.RFC3339)
+		notification.ReadAt = formatNullTime(readAt)
+		notifications = append(notifications, &notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over notification rows: %v", err)
+	}
+
+	var unread uint64
+	if err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL", req.UserId).Scan(&unread); err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %v", err)
+	}
+
+	return &genprotos.ListNotificationsResponse{Notifications: notifications, Unread: unread}, nil
+}
+
+func (p *Product) MarkNotificationsRead(ctx context.Context, req *genprotos.MarkNotificationsReadRequest) (*genprotos.Message, error) {
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", req.UserId)
+	}
+	for _, id := range req.Ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "notification id %q is not a valid id", id)
+		}
+	}
+
+	query := "UPDATE notifications SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL"
+	args := []interface{}{time.Now(), req.UserId}
+	if len(req.Ids) > 0 {
+		query += " AND id = ANY($3)"
+		args = append(args, pq.Array(req.Ids))
+	}
+	if _, err := p.db.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to mark notifications read: %v", err)
+	}
+
+	return &genprotos.Message{Message: "Notifications marked as read"}, nil
+}
+
+// notifyPriceDrop tells everyone who saved a product that it got cheaper,
+// given the price it had before the change. Prices are only compared
+// within one currency.
+func (p *Product) notifyPriceDrop(ctx context.Context, q queryer, productID string, oldAmount int64, oldCurrency string) error {
+	var (
+		name     string
+		amount   int64
+		currency string
+	)
+	err := q.QueryRowContext(ctx, "SELECT name, price_amount, currency FROM products WHERE id = $1", productID).Scan(&name, &amount, &currency)
+	if err != nil {
+		return fmt.Errorf("failed to fetch product price: %v", err)
+	}
+	if currency != oldCurrency || amount >= oldAmount {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s dropped in price from %s to %s", name, money.Format(oldAmount, oldCurrency), money.Format(amount, currency))
+	return p.notifyWishers(ctx, q, productID, notificationPriceDrop, message)
+}
+
+// notifyBackInStock tells everyone who saved a product that it can be
+// bought again.
+func (p *Product) notifyBackInStock(ctx context.Context, q queryer, productID string) error {
+	var name string
+	if err := q.QueryRowContext(ctx, "SELECT name FROM products WHERE id = $1", productID).Scan(&name); err != nil {
+		return fmt.Errorf("failed to fetch product: %v", err)
+	}
+	return p.notifyWishers(ctx, q, productID, notificationBackInStock, fmt.Sprintf("%s is back in stock", name))
+}
+
+// notifyWishers sends one notification to each user with the product on
+// any of their lists, as long as the product is on sale.
+func (p *Product) notifyWishers(ctx context.Context, q queryer, productID, kind, message string) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT DISTINCT w.user_id
+		FROM wishlist_items wi
+		JOIN wishlists w ON w.id = wi.wishlist_id
+		JOIN products p ON p.id = wi.product_id
+		WHERE wi.product_id = $1 AND p.status IN ($2, $3)
+	`, productID, productPublished, productOutOfStock)
+	if err != nil {
+		return fmt.Errorf("failed to fetch users who saved the product: %v", err)
+	}
+	var users []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan user id: %v", err)
+		}
+		users = append(users, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over users who saved the product: %v", err)
+	}
+
+	now := time.Now()
+	for _, userID := range users {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO notifications (id, user_id, kind, product_id, message, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.NewString(), userID, kind, productID, message, now)
+		if err != nil {
+			return fmt.Errorf("failed to create notification: %v", err)
+		}
+	}
+	return nil
+}
+
+// notifyOrderStatus tells the buyer their order moved to a new status.
+func notifyOrderStatus(ctx context.Context, q queryer, orderID, userID, orderStatus string) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO notifications (id, user_id, kind, message, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, uuid.NewString(), userID, notificationOrderStatus, fmt.Sprintf("Your order %s is now %s", orderID, orderStatus), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %v", err)
+	}
+	return nil
+}
+
+// notifyFulfillmentStatus tells the buyer one artisan's part of their order
+// moved while the order as a whole did not.
+func notifyFulfillmentStatus(ctx context.Context, q queryer, orderID, userID, fulfillmentStatus string) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO notifications (id, user_id, kind, message, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, uuid.NewString(), userID, notificationOrderStatus, fmt.Sprintf("Part of your order %s is now %s", orderID, fulfillmentStatus), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %v", err)
+	}
+	return nil
+}
+
+// notifyReturnStatus tells the buyer or the artisan a return moved to a new
+// status.
+func notifyReturnStatus(ctx context.Context, q queryer, userID, returnID, orderID, returnStatus string) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO notifications (id, user_id, kind, message, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, uuid.NewString(), userID, notificationReturnStatus, fmt.Sprintf("Return %s for order %s is now %s", returnID, orderID, returnStatus), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/redact"
+)
+
+const (
+	eventPlaced   = "placed"
+	eventStatus   = "status"
+	eventPayment  = "payment"
+	eventShipping = "shipping"
+	eventReturn   = "return"
+	eventRefund   = "refund"
+)
+
+const orderEventColumns = "id, order_id, kind, COALESCE(from_status, ''), COALESCE(to_status, ''), COALESCE(actor_id::text, ''), COALESCE(actor_role, ''), COALESCE(note, ''), created_at, COALESCE(fulfillment_id::text, '')"
+
+// orderEvent is one thing that happened to an order, or to one of its
+// fulfillments when fulfillmentID is set.
+type orderEvent struct {
+	kind          string
+	fulfillmentID string
+	from          string
+	to            string
+	// actor is left empty for events nobody in particular caused.
+	actor orderActor
+	note  string
+}
+
+func (p *Product) GetOrderTimeline(ctx context.Context, req *genprotos.GetOrderTimelineRequest) (*genprotos.GetOrderTimelineResponse, error) {
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id %q is not a valid id", req.OrderId)
+	}
+
+	response := genprotos.GetOrderTimelineResponse{OrderId: req.OrderId}
+	err := p.db.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1", req.OrderId).Scan(&response.Status)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "order %s not found", req.OrderId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order: %v", err)
+	}
+
+	if response.Events, err = orderTimeline(ctx, p.db, req.OrderId); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// recordOrderEvent adds an event to an order's timeline.
+func recordOrderEvent(ctx context.Context, q queryer, orderID string, event orderEvent) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO order_events (id, order_id, fulfillment_id, kind, from_status, to_status, actor_id, actor_role, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, uuid.NewString(), orderID, sql.NullString{String: event.fulfillmentID, Valid: event.fulfillmentID != ""}, event.kind,
+		sql.NullString{String: event.from, Valid: event.from != ""},
+		sql.NullString{String: event.to, Valid: event.to != ""},
+		sql.NullString{String: event.actor.id, Valid: event.actor.id != ""},
+		sql.NullString{String: event.actor.role, Valid: event.actor.role != ""},
+		sql.NullString{String: redact.PAN(event.note), Valid: event.note != ""}, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record order event: %v", err)
+	}
+	return nil
+}
+
+// orderTimeline returns the events of an order, oldest first.
+func orderTimeline(ctx context.Context, q queryer, orderID string) ([]*genprotos.OrderEvent, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+orderEventColumns+" FROM order_events WHERE order_id = $1 ORDER BY created_at, id", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order events: %v", err)
+	}
+	defer rows.Close()
+
+	events := make([]*genprotos.OrderEvent, 0)
+	for rows.Next() {
+		var (
+			event     genprotos.OrderEvent
+			createdAt time.Time
+		)
+		if err := rows.Scan(&event.Id, &event.OrderId, &event.Kind, &event.FromStatus, &event.ToStatus, &event.ActorId, &event.ActorRole, &event.Note, &createdAt, &event.FulfillmentId); err != nil {
+			return nil, fmt.Errorf("failed to scan order event row: %v", err)
+		}
+		event.CreatedAt = createdAt.Format(time.RFC3339)
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over order event rows: %v", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,479 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	orderPending    = "pending"
+	orderPaid       = "paid"
+	orderProcessing = "processing"
+	orderShipped    = "shipped"
+	orderDelivered  = "delivered"
+	orderCompleted  = "completed"
+	orderCancelled  = "cancelled"
+	orderRefunded   = "refunded"
+	orderReturned   = "returned"
+)
+
+// Roles an order can be moved by. roleSystem is the service itself, for
+// payments and expired stock holds; requests cannot claim it.
+const (
+	roleBuyer   = "buyer"
+	roleArtisan = "artisan"
+	roleAdmin   = "admin"
+	roleSystem  = "system"
+)
+
+// orderTransitions lists the statuses an order may move to from each
+// status, and who may move it there.
+var orderTransitions = map[string]map[string][]string{
+	orderPending: {
+		orderPaid:      {roleSystem},
+		orderCancelled: {roleBuyer, roleAdmin, roleSystem},
+	},
+	orderPaid: {
+		orderProcessing: {roleArtisan, roleAdmin},
+		orderCancelled:  {roleBuyer, roleArtisan, roleAdmin},
+	},
+	orderProcessing: {
+		orderShipped:   {roleArtisan, roleAdmin},
+		orderCancelled: {roleArtisan, roleAdmin},
+	},
+	orderShipped: {
+		orderDelivered: {roleArtisan, roleAdmin},
+	},
+	orderDelivered: {
+		orderCompleted: {roleBuyer, roleAdmin},
+		orderReturned:  {roleArtisan, roleAdmin},
+	},
+	orderReturned: {
+		orderRefunded: {roleArtisan, roleAdmin},
+	},
+	orderCompleted: {},
+	orderCancelled: {},
+	orderRefunded:  {},
+}
+
+// orderActor is who asks for a status change.
+type orderActor struct {
+	id   string
+	role string
+}
+
+// requestActor validates the actor of a status change request. role
+// defaults to fallback when empty.
+func requestActor(actorID, role, fallback string) (orderActor, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role == "" {
+		role = fallback
+	}
+	if role != roleBuyer && role != roleArtisan && role != roleAdmin {
+		return orderActor{}, status.Errorf(codes.InvalidArgument, "unknown role %q; use buyer, artisan or admin", role)
+	}
+	if _, err := uuid.Parse(actorID); err != nil {
+		return orderActor{}, status.Errorf(codes.InvalidArgument, "actor_id %q is not a valid id", actorID)
+	}
+	return orderActor{id: actorID, role: role}, nil
+}
+
+// orderProgress ranks the statuses along the way from placing an order to
+// completing it. An order is as far along as its least advanced fulfillment.
+var orderProgress = map[string]int{
+	orderPending:    0,
+	orderPaid:       1,
+	orderProcessing: 2,
+	orderShipped:    3,
+	orderDelivered:  4,
+	orderCompleted:  5,
+}
+
+// fulfillment is one artisan's part of an order, locked for a status change.
+type fulfillment struct {
+	id        string
+	orderID   string
+	artisanID string
+	status    string
+}
+
+// orderMove is an order's status before and after a change.
+type orderMove struct {
+	previous string
+	status   string
+}
+
+// transitionOrder moves every fulfillment of an order to target inside the
+// caller's transaction; cancelled and refunded ones stay as they are. The
+// order then takes the status that follows from them. Asking for the status
+// the order already has changes nothing.
+func (p *Product) transitionOrder(ctx context.Context, tx *sql.Tx, orderID, target string, actor orderActor, note string) (orderMove, error) {
+	if _, ok := orderTransitions[target]; !ok {
+		return orderMove{}, status.Errorf(codes.InvalidArgument, "unknown order status %q", target)
+	}
+
+	current, buyerID, err := lockOrderBuyer(ctx, tx, orderID)
+	if err != nil {
+		return orderMove{}, err
+	}
+	if current == target {
+		return orderMove{previous: current, status: current}, nil
+	}
+
+	// Orders from before the state machine may hold any status; only an
+	// admin can bring them back into it.
+	allowed, known := orderTransitions[current]
+	roles, legal := allowed[target]
+	switch {
+	case !known && actor.role != roleAdmin:
+		return orderMove{}, status.Errorf(codes.FailedPrecondition, "order %s has unknown status %q; an admin must set its status", orderID, current)
+	case known && !legal:
+		return orderMove{}, status.Errorf(codes.FailedPrecondition, "cannot move order %s from %s to %s", orderID, current, target)
+	case known && !containsString(roles, actor.role):
+		return orderMove{}, status.Errorf(codes.PermissionDenied, "the %s role cannot move an order from %s to %s", actor.role, current, target)
+	}
+	if err := authorizeOrderActor(ctx, tx, orderID, buyerID, actor); err != nil {
+		return orderMove{}, err
+	}
+
+	if target == orderPaid {
+		if err := p.commitReservations(ctx, tx, orderID); err != nil {
+			return orderMove{}, err
+		}
+	}
+
+	groups, err := lockFulfillments(ctx, tx, "order_id = $1", orderID)
+	if err != nil {
+		return orderMove{}, err
+	}
+	for _, group := range groups {
+		if group.status == target || group.status == orderCancelled || group.status == orderRefunded {
+			continue
+		}
+		// When an admin resets an order with an unknown status its parts
+		// follow without their own moves being checked.
+		if known {
+			if err := checkFulfillmentMove(group, target, actor); err != nil {
+				return orderMove{}, err
+			}
+		}
+		if err := p.moveFulfillment(ctx, tx, group, target, actor, note); err != nil {
+			return orderMove{}, err
+		}
+	}
+
+	return p.syncOrderStatus(ctx, tx, orderID, buyerID, current, target, actor, note)
+}
+
+// transitionFulfillment moves one artisan's part of an order to target and
+// updates the order's status to match.
+func (p *Product) transitionFulfillment(ctx context.Context, tx *sql.Tx, orderID, fulfillmentID, target string, actor orderActor, note string) (orderMove, error) {
+	if _, ok := orderTransitions[target]; !ok {
+		return orderMove{}, status.Errorf(codes.InvalidArgument, "unknown order status %q", target)
+	}
+	if _, err := uuid.Parse(fulfillmentID); err != nil {
+		return orderMove{}, status.Errorf(codes.InvalidArgument, "fulfillment_id %q is not a valid id", fulfillmentID)
+	}
+
+	// The order is locked first, as everywhere else, so its status can be
+	// worked out from fulfillments nobody else is moving.
+	current, buyerID, err := lockOrderBuyer(ctx, tx, orderID)
+	if err != nil {
+		return orderMove{}, err
+	}
+	groups, err := lockFulfillments(ctx, tx, "id = $1 AND order_id = $2", fulfillmentID, orderID)
+	if err != nil {
+		return orderMove{}, err
+	}
+	if len(groups) == 0 {
+		return orderMove{}, status.Errorf(codes.NotFound, "fulfillment %s not found in order %s", fulfillmentID, orderID)
+	}
+	group := groups[0]
+	if group.status == target {
+		return orderMove{previous: current, status: current}, nil
+	}
+
+	if err := checkFulfillmentMove(group, target, actor); err != nil {
+		return orderMove{}, err
+	}
+	if actor.role != roleArtisan {
+		if err := authorizeOrderActor(ctx, tx, orderID, buyerID, actor); err != nil {
+			return orderMove{}, err
+		}
+	}
+	if err := p.moveFulfillment(ctx, tx, group, target, actor, note); err != nil {
+		return orderMove{}, err
+	}
+
+	move, err := p.syncOrderStatus(ctx, tx, orderID, buyerID, current, "", actor, note)
+	if err != nil {
+		return orderMove{}, err
+	}
+	if move.status == current {
+		// The order as a whole did not move, so tell the buyer about the part
+		// that did.
+		if err := notifyFulfillmentStatus(ctx, tx, orderID, buyerID, target); err != nil {
+			return orderMove{}, err
+		}
+	}
+	return move, nil
+}
+
+// checkFulfillmentMove checks a fulfillment may move to target and the
+// actor may move it. Artisans may only move their own part of an order.
+func checkFulfillmentMove(group fulfillment, target string, actor orderActor) error {
+	roles, legal := orderTransitions[group.status][target]
+	switch {
+	case !legal:
+		return status.Errorf(codes.FailedPrecondition, "fulfillment %s is %s and cannot move to %s", group.id, group.status, target)
+	case !containsString(roles, actor.role):
+		return status.Errorf(codes.PermissionDenied, "the %s role cannot move an order from %s to %s", actor.role, group.status, target)
+	case actor.role == roleArtisan && actor.id != group.artisanID:
+		return status.Errorf(codes.PermissionDenied, "fulfillment %s belongs to another artisan; artisans move their own part of an order with fulfillment_id", group.id)
+	}
+	return nil
+}
+
+// moveFulfillment moves a locked fulfillment to target and runs what the
+// move implies: stock goes back on cancellation and return, and what the
+// buyer paid for the part, less what returns already gave back, is
+// refunded.
+func (p *Product) moveFulfillment(ctx context.Context, tx *sql.Tx, group fulfillment, target string, actor orderActor, note string) error {
+	var products []string
+	if target == orderCancelled || target == orderReturned {
+		rows, err := tx.QueryContext(ctx, "SELECT DISTINCT product_id FROM order_items WHERE fulfillment_id = $1 AND product_id IS NOT NULL", group.id)
+		if err != nil {
+			return fmt.Errorf("failed to fetch fulfillment items: %v", err)
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan fulfillment item: %v", err)
+			}
+			products = append(products, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating over fulfillment items: %v", err)
+		}
+	}
+
+	giveBack := false
+	switch target {
+	case orderCancelled:
+		if err := p.releaseReservations(ctx, tx, group.orderID, movementCancellation, products); err != nil {
+			return err
+		}
+		giveBack = group.status != orderPending
+	case orderReturned:
+		if err := p.releaseReservations(ctx, tx, group.orderID, movementReturn, products); err != nil {
+			return err
+		}
+	case orderRefunded:
+		giveBack = true
+	}
+
+	query := "UPDATE order_fulfillments SET status = $1, updated_at = $2 WHERE id = $3"
+	if giveBack {
+		// Whatever returns already gave back is not refunded again.
+		var left int64
+		if err := tx.QueryRowContext(ctx, "SELECT payout_amount - refunded_amount FROM order_fulfillments WHERE id = $1", group.id).Scan(&left); err != nil {
+			return fmt.Errorf("failed to fetch fulfillment payout: %v", err)
+		}
+		if _, err := p.refundPayment(ctx, tx, group.orderID, left, refund{fulfillmentID: group.id, actor: actor, note: note}); err != nil {
+			return err
+		}
+		query = "UPDATE order_fulfillments SET status = $1, updated_at = $2, refunded_amount = payout_amount WHERE id = $3"
+	}
+	if _, err := tx.ExecContext(ctx, query, target, time.Now(), group.id); err != nil {
+		return fmt.Errorf("failed to update fulfillment status: %v", err)
+	}
+	return recordOrderEvent(ctx, tx, group.orderID, orderEvent{
+		kind:          eventStatus,
+		fulfillmentID: group.id,
+		from:          group.status,
+		to:            target,
+		actor:         actor,
+		note:          note,
+	})
+}
+
+// syncOrderStatus sets an order's status to the one that follows from its
+// fulfillments, or to fallback when it has none. Once the whole order is
+// cancelled its promotion uses are given back, and once it is cancelled or
+// refunded so are its payments. The change goes on the timeline and the
+// buyer is told.
+func (p *Product) syncOrderStatus(ctx context.Context, tx *sql.Tx, orderID, buyerID, current, fallback string, actor orderActor, note string) (orderMove, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT status FROM order_fulfillments WHERE order_id = $1", orderID)
+	if err != nil {
+		return orderMove{}, fmt.Errorf("failed to fetch fulfillments: %v", err)
+	}
+	var statuses []string
+	for rows.Next() {
+		var groupStatus string
+		if err := rows.Scan(&groupStatus); err != nil {
+			rows.Close()
+			return orderMove{}, fmt.Errorf("failed to scan fulfillment row: %v", err)
+		}
+		statuses = append(statuses, groupStatus)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return orderMove{}, fmt.Errorf("error iterating over fulfillment rows: %v", err)
+	}
+
+	target := deriveOrderStatus(statuses)
+	if target == "" {
+		target = fallback
+	}
+	move := orderMove{previous: current, status: current}
+	if target == "" || target == current {
+		return move, nil
+	}
+	move.status = target
+
+	switch target {
+	case orderCancelled:
+		if err := releaseRedemptions(ctx, tx, orderID); err != nil {
+			return orderMove{}, err
+		}
+		if err := voidPayments(ctx, tx, orderID); err != nil {
+			return orderMove{}, err
+		}
+		if err := p.refundPayments(ctx, tx, orderID, refund{actor: actor, note: note}); err != nil {
+			return orderMove{}, err
+		}
+	case orderRefunded:
+		if err := p.refundPayments(ctx, tx, orderID, refund{actor: actor, note: note}); err != nil {
+			return orderMove{}, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3", target, time.Now(), orderID); err != nil {
+		return orderMove{}, fmt.Errorf("failed to update order status: %v", err)
+	}
+	if err := recordOrderEvent(ctx, tx, orderID, orderEvent{kind: eventStatus, from: current, to: target, actor: actor, note: note}); err != nil {
+		return orderMove{}, err
+	}
+	if err := notifyOrderStatus(ctx, tx, orderID, buyerID, target); err != nil {
+		return orderMove{}, err
+	}
+	return move, nil
+}
+
+// deriveOrderStatus works out an order's status from its fulfillments.
+// Cancelled parts are left out unless every part is cancelled; parts that
+// were returned or refunded only count once nothing else is left. Otherwise
+// the order is as far along as its least advanced part. It returns "" for an
+// order without fulfillments.
+func deriveOrderStatus(statuses []string) string {
+	var active, settled []string
+	for _, groupStatus := range statuses {
+		switch groupStatus {
+		case orderCancelled:
+		case orderReturned, orderRefunded:
+			settled = append(settled, groupStatus)
+		default:
+			active = append(active, groupStatus)
+		}
+	}
+
+	switch {
+	case len(statuses) == 0:
+		return ""
+	case len(active) == 0 && len(settled) == 0:
+		return orderCancelled
+	case len(active) == 0:
+		for _, groupStatus := range settled {
+			if groupStatus == orderReturned {
+				return orderReturned
+			}
+		}
+		return orderRefunded
+	}
+
+	least := active[0]
+	for _, groupStatus := range active[1:] {
+		if orderProgress[groupStatus] < orderProgress[least] {
+			least = groupStatus
+		}
+	}
+	return least
+}
+
+// lockOrderBuyer locks an order row for the rest of the transaction and
+// returns its status and buyer.
+func lockOrderBuyer(ctx context.Context, tx *sql.Tx, orderID string) (string, string, error) {
+	var current, buyerID sql.NullString
+	err := tx.QueryRowContext(ctx, "SELECT status, user_id FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&current, &buyerID)
+	if err == sql.ErrNoRows {
+		return "", "", status.Errorf(codes.NotFound, "order %s not found", orderID)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch order: %v", err)
+	}
+	return current.String, buyerID.String, nil
+}
+
+// lockFulfillments locks the fulfillments matching where, in id order.
+func lockFulfillments(ctx context.Context, tx *sql.Tx, where string, args ...interface{}) ([]fulfillment, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, order_id, COALESCE(artisan_id::text, ''), status
+		FROM order_fulfillments WHERE `+where+` ORDER BY id FOR UPDATE`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock fulfillments: %v", err)
+	}
+	defer rows.Close()
+
+	var groups []fulfillment
+	for rows.Next() {
+		var group fulfillment
+		if err := rows.Scan(&group.id, &group.orderID, &group.artisanID, &group.status); err != nil {
+			return nil, fmt.Errorf("failed to scan fulfillment row: %v", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over fulfillment rows: %v", err)
+	}
+	return groups, nil
+}
+
+// authorizeOrderActor checks the actor is who their role says: the buyer
+// of the order, an artisan selling something in it, or an admin.
+func authorizeOrderActor(ctx context.Context, q queryer, orderID, buyerID string, actor orderActor) error {
+	var (
+		allowed bool
+		err     error
+	)
+	switch actor.role {
+	case roleSystem:
+		return nil
+	case roleBuyer:
+		allowed = actor.id == buyerID
+	case roleArtisan:
+		err = q.QueryRowContext(ctx, `
+			SELECT EXISTS(
+				SELECT 1 FROM order_items oi JOIN products p ON p.id = oi.product_id
+				WHERE oi.order_id = $1 AND p.artisan_id = $2
+			)
+		`, orderID, actor.id).Scan(&allowed)
+	case roleAdmin:
+		err = q.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND lower(user_type) = $2)", actor.id, roleAdmin).Scan(&allowed)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check order permissions: %v", err)
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "%s cannot act as %s on order %s", actor.id, actor.role, orderID)
+	}
+	return nil
+}
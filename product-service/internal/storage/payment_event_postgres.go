@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/payment"
+)
+
+// HandlePaymentEvent applies an event the payment provider sent by webhook.
+// The event carries its transaction as it stood, and the payment is brought
+// up to that state instead of acting on the event's type, so events that
+// arrive late or out of order never undo anything. An event delivered again
+// is recognised by its id and changes nothing.
+func (p *Product) HandlePaymentEvent(ctx context.Context, req *genprotos.PaymentEvent) (*genprotos.PaymentEventResponse, error) {
+	if req.Id == "" || req.Type == "" || req.GetTransaction().GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id, type and transaction.id are required")
+	}
+	createdAt := time.Now()
+	if req.CreatedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.CreatedAt)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "created_at %q is not an RFC 3339 time", req.CreatedAt)
+		}
+		createdAt = parsed
+	}
+	txn := providerTransaction(req.Transaction)
+	txn.Currency = strings.ToUpper(txn.Currency)
+	if _, err := money.Exponent(txn.Currency); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction.currency: %v", err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var orderID string
+	err = tx.QueryRowContext(ctx, "SELECT order_id FROM payments WHERE transaction_id = $1 AND provider IS NOT NULL", txn.ID).Scan(&orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "no payment has transaction %s", txn.ID)
+		}
+		return nil, fmt.Errorf("failed to fetch payment: %v", err)
+	}
+	orderStatus, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	pay, err := scanPayment(tx.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE transaction_id = $1 AND provider IS NOT NULL FOR UPDATE", txn.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock payment: %v", err)
+	}
+
+	response := &genprotos.PaymentEventResponse{EventId: req.Id, PaymentId: pay.id, OrderId: orderID}
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO payment_events (id, type, transaction_id, payment_id, created_at, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING
+	`, req.Id, req.Type, txn.ID, pay.id, createdAt, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to record payment event: %v", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to record payment event: %v", err)
+	} else if n == 0 {
+		response.Duplicate = true
+		response.PaymentStatus = pay.status
+		response.OrderStatus = orderStatus
+		return response, nil
+	}
+
+	captured, err := p.reconcilePayment(ctx, tx, pay, orderStatus, txn)
+	if err != nil {
+		return nil, err
+	}
+	pay, err = scanPayment(tx.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE id = $1", pay.id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment: %v", err)
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1", orderID).Scan(&orderStatus); err != nil {
+		return nil, fmt.Errorf("failed to fetch order status: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		if captured {
+			p.undoCapture(pay)
+		}
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	p.queuePayments()
+	response.PaymentStatus = pay.status
+	response.OrderStatus = orderStatus
+	return response, nil
+}
+
+// reconcilePayment brings a payment up to what the provider says about its
+// transaction, and reports whether it captured the payment itself.
+//
+// An open payment follows the provider: it is declined, voided or
+// authorized, and once authorized it is captured. When the provider
+// captured it already, the order is paid, or given its money back if it can
+// no longer be paid. A capture of another amount or currency than the
+// payment's never pays the order: the payment fails and what was captured
+// goes back. Money the provider gave back without us asking, from its
+// dashboard or through a chargeback, is recorded as refunds.
+func (p *Product) reconcilePayment(ctx context.Context, tx *sql.Tx, pay storedPayment, orderStatus string, txn *payment.Transaction) (bool, error) {
+	system := orderActor{role: roleSystem}
+	open := pay.status == paymentRequiresAction || pay.status == paymentAuthorized
+	payable := orderStatus == orderPending
+	if open && payable {
+		if err := checkReservationsHeld(ctx, tx, pay.orderID); err != nil {
+			if status.Code(err) != codes.FailedPrecondition {
+				return false, err
+			}
+			payable = false
+		}
+	}
+
+	switch {
+	case open && txn.Captured > 0 && (txn.Captured != pay.amount || txn.Currency != pay.currency):
+		pay.status = paymentFailed
+		pay.providerStatus = txn.Status
+		pay.failureCode = "capture_mismatch"
+		pay.failureMessage = fmt.Sprintf("the provider captured %s %s instead of %s %s",
+			money.Format(txn.Captured, txn.Currency), txn.Currency, money.Format(pay.amount, pay.currency), pay.currency)
+		pay.challengeURL = ""
+		log.Printf("payment %s (transaction %s): %s", pay.id, txn.ID, pay.failureMessage)
+		if err := updatePayment(ctx, tx, pay); err != nil {
+			return false, err
+		}
+		note := fmt.Sprintf("payment %s: %s (%s); it is given back", pay.id, pay.status, pay.failureReason())
+		if err := recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: system, note: note}); err != nil {
+			return false, err
+		}
+		return false, queueProviderRefund(ctx, tx, pay, txn.Captured-txn.Refunded, txn.Currency, "the provider captured the wrong amount")
+	case open && txn.Captured > 0:
+		pay.status = paymentPaid
+		pay.providerStatus = txn.Status
+		pay.failureCode, pay.failureMessage, pay.challengeURL = "", "", ""
+		if payable {
+			if err := p.recordCapture(ctx, tx, pay); err != nil {
+				return false, err
+			}
+			break
+		}
+		if err := updatePayment(ctx, tx, pay); err != nil {
+			return false, err
+		}
+		note := fmt.Sprintf("payment %s captured by the provider after the order was %s; it is given back", pay.id, orderStatus)
+		if err := recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: system, note: note}); err != nil {
+			return false, err
+		}
+		// Whatever the provider gave back already is not refunded again.
+		if _, err := p.refundPayment(ctx, tx, pay.orderID, txn.Refunded, refund{paymentID: pay.id, atProvider: true, actor: system, note: "refunded at the payment provider"}); err != nil {
+			return false, err
+		}
+		_, err := p.refundPayment(ctx, tx, pay.orderID, pay.amount-txn.Refunded, refund{paymentID: pay.id, actor: system, note: "the order can no longer be paid"})
+		return false, err
+	case open && txn.Status == payment.StatusAuthorized && pay.status == paymentRequiresAction:
+		pay.apply(txn)
+		if err := updatePayment(ctx, tx, pay); err != nil {
+			return false, err
+		}
+		if err := recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: system, note: fmt.Sprintf("payment %s: %s", pay.id, pay.status)}); err != nil {
+			return false, err
+		}
+		// An order that can no longer be paid is cancelled by the
+		// reservation worker, which voids the payment.
+		if !payable {
+			return false, nil
+		}
+		if err := p.capturePayment(ctx, tx, &pay); err != nil {
+			return false, err
+		}
+		return pay.status == paymentPaid, nil
+	case open && (txn.Status == payment.StatusDeclined || txn.Status == payment.StatusVoided):
+		pay.apply(txn)
+		if err := updatePayment(ctx, tx, pay); err != nil {
+			return false, err
+		}
+		note := fmt.Sprintf("payment %s: %s", pay.id, pay.status)
+		if reason := pay.failureReason(); reason != "" {
+			note += " (" + reason + ")"
+		}
+		return false, recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: system, note: note})
+	case open:
+		return false, nil
+	}
+
+	if pay.status != paymentPaid && pay.status != paymentPartiallyRefunded {
+		return false, nil
+	}
+	if txn.Refunded <= pay.refunded && txn.Status != payment.StatusChargedBack {
+		return false, nil
+	}
+	if txn.Refunded > pay.refunded {
+		refunded, err := p.refundPayment(ctx, tx, pay.orderID, txn.Refunded-pay.refunded, refund{paymentID: pay.id, atProvider: true, actor: system, note: "refunded at the payment provider"})
+		if err != nil {
+			return false, err
+		}
+		pay.refunded += refunded
+	}
+	if txn.Status == payment.StatusChargedBack {
+		_, err := p.refundPayment(ctx, tx, pay.orderID, pay.amount-pay.refunded, refund{paymentID: pay.id, atProvider: true, actor: system, note: "chargeback"})
+		if err != nil {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE payments SET status = $1, updated_at = $2 WHERE id = $3", paymentChargedBack, time.Now(), pay.id); err != nil {
+			return false, fmt.Errorf("failed to update payment: %v", err)
+		}
+		err = recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: system, note: fmt.Sprintf("payment %s was charged back by the card's bank", pay.id)})
+		if err != nil {
+			return false, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE payments SET provider_status = $1 WHERE id = $2", txn.Status, pay.id); err != nil {
+		return false, fmt.Errorf("failed to update payment: %v", err)
+	}
+	return false, nil
+}
+
+func providerTransaction(txn *genprotos.PaymentTransaction) *payment.Transaction {
+	return &payment.Transaction{
+		ID:             txn.Id,
+		Reference:      txn.Reference,
+		Status:         txn.Status,
+		Amount:         txn.Amount,
+		Currency:       txn.Currency,
+		Captured:       txn.Captured,
+		Refunded:       txn.Refunded,
+		FailureCode:    txn.FailureCode,
+		FailureMessage: txn.FailureMessage,
+	}
+}
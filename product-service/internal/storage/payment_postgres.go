@@ -0,0 +1,594 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	genprotos "armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/payment"
+	"armiya/equipment-service/internal/redact"
+)
+
+// Payment statuses. A payment is pending from before the provider is asked
+// to authorize it until its answer is known, waits in requires_action while
+// the buyer completes 3-D Secure and in authorized until it is captured;
+// failed means the provider refused the request, or captured something
+// other than the payment.
+const (
+	paymentPending           = "pending"
+	paymentRequiresAction    = "requires_action"
+	paymentAuthorized        = "authorized"
+	paymentDeclined          = "declined"
+	paymentFailed            = "failed"
+	paymentVoided            = "voided"
+	paymentPaid              = "paid"
+	paymentPartiallyRefunded = "partially_refunded"
+	paymentRefunded          = "refunded"
+	paymentChargedBack       = "charged_back"
+)
+
+// paymentStatuses maps a provider's transaction status to the payment's.
+var paymentStatuses = map[string]string{
+	payment.StatusRequiresAction:    paymentRequiresAction,
+	payment.StatusAuthorized:        paymentAuthorized,
+	payment.StatusDeclined:          paymentDeclined,
+	payment.StatusCaptured:          paymentPaid,
+	payment.StatusVoided:            paymentVoided,
+	payment.StatusPartiallyRefunded: paymentPartiallyRefunded,
+	payment.StatusRefunded:          paymentRefunded,
+	payment.StatusChargedBack:       paymentChargedBack,
+}
+
+const paymentColumns = `id, order_id, amount, refunded_amount, currency, status, COALESCE(transaction_id, ''),
+	COALESCE(provider, ''), COALESCE(provider_status, ''), COALESCE(failure_code, ''), COALESCE(failure_message, ''),
+	COALESCE(challenge_url, ''), created_at, COALESCE(payment_method, ''), COALESCE(payment_token, '')`
+
+type storedPayment struct {
+	id             string
+	orderID        string
+	amount         int64
+	refunded       int64
+	currency       string
+	status         string
+	transactionID  string
+	provider       string
+	providerStatus string
+	failureCode    string
+	failureMessage string
+	challengeURL   string
+	createdAt      time.Time
+	method         string
+	// token is the card's token, kept while the payment is pending.
+	token string
+}
+
+func scanPayment(row rowScanner) (storedPayment, error) {
+	var pay storedPayment
+	err := row.Scan(&pay.id, &pay.orderID, &pay.amount, &pay.refunded, &pay.currency, &pay.status, &pay.transactionID,
+		&pay.provider, &pay.providerStatus, &pay.failureCode, &pay.failureMessage, &pay.challengeURL, &pay.createdAt,
+		&pay.method, &pay.token)
+	return pay, err
+}
+
+// apply takes what the provider says about the payment's transaction.
+func (pay *storedPayment) apply(txn *payment.Transaction) {
+	pay.transactionID = txn.ID
+	pay.providerStatus = txn.Status
+	pay.failureCode = txn.FailureCode
+	pay.failureMessage = txn.FailureMessage
+	pay.challengeURL = txn.ChallengeURL
+	if paymentStatus, ok := paymentStatuses[txn.Status]; ok {
+		pay.status = paymentStatus
+	}
+}
+
+// failureReason says why a payment did not go through, for the buyer.
+func (pay storedPayment) failureReason() string {
+	switch {
+	case pay.failureMessage != "" && pay.failureCode != "":
+		return fmt.Sprintf("%s: %s", pay.failureCode, pay.failureMessage)
+	case pay.failureMessage != "":
+		return pay.failureMessage
+	default:
+		return pay.failureCode
+	}
+}
+
+func (pay storedPayment) response() *genprotos.PayResponse {
+	return &genprotos.PayResponse{
+		OrderId:       pay.orderID,
+		PaymentId:     pay.id,
+		Amount:        money.New(pay.amount, pay.currency),
+		LegacyAmount:  money.ToFloat(pay.amount, pay.currency),
+		Status:        pay.status,
+		TransactionId: pay.transactionID,
+		CreatedAt:     pay.createdAt.Format(time.RFC3339),
+		FailureReason: pay.failureReason(),
+		ChallengeUrl:  pay.challengeURL,
+	}
+}
+
+func (p *Product) insertPayment(ctx context.Context, tx *sql.Tx, pay *storedPayment, method string) error {
+	pay.provider = p.paymentBackend
+	method = redact.PAN(method)
+	pay.method = method
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO payments (id, order_id, amount, currency, status, transaction_id, payment_method, payment_token, provider, provider_status,
+			failure_code, failure_message, challenge_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $14)
+	`, pay.id, pay.orderID, pay.amount, pay.currency, pay.status,
+		sql.NullString{String: pay.transactionID, Valid: pay.transactionID != ""}, method,
+		sql.NullString{String: pay.token, Valid: pay.token != ""}, pay.provider,
+		sql.NullString{String: pay.providerStatus, Valid: pay.providerStatus != ""},
+		sql.NullString{String: pay.failureCode, Valid: pay.failureCode != ""},
+		sql.NullString{String: pay.failureMessage, Valid: pay.failureMessage != ""},
+		sql.NullString{String: pay.challengeURL, Valid: pay.challengeURL != ""}, pay.createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert payment into database: %v", err)
+	}
+
+	if method == "" {
+		method = "unspecified method"
+	}
+	note := fmt.Sprintf("payment %s of %s %s by %s: %s", pay.id, money.Format(pay.amount, pay.currency), pay.currency, method, pay.status)
+	if reason := pay.failureReason(); reason != "" {
+		note += " (" + reason + ")"
+	}
+	return recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: orderActor{role: roleSystem}, note: note})
+}
+
+func updatePayment(ctx context.Context, tx *sql.Tx, pay storedPayment) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE payments SET status = $1, provider_status = $2, failure_code = $3, failure_message = $4, challenge_url = $5, updated_at = $6
+		WHERE id = $7
+	`, pay.status, pay.providerStatus,
+		sql.NullString{String: pay.failureCode, Valid: pay.failureCode != ""},
+		sql.NullString{String: pay.failureMessage, Valid: pay.failureMessage != ""},
+		sql.NullString{String: pay.challengeURL, Valid: pay.challengeURL != ""}, time.Now(), pay.id)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %v", err)
+	}
+	return nil
+}
+
+// capturePayment takes the money of an authorized payment and marks its
+// order paid. When the provider does not answer in time the payment stays
+// authorized and ConfirmPayment tries again. Money taken for an order that
+// then cannot be paid goes straight back.
+func (p *Product) capturePayment(ctx context.Context, tx *sql.Tx, pay *storedPayment) error {
+	if err := checkReservationsHeld(ctx, tx, pay.orderID); err != nil {
+		return err
+	}
+	txn, err := p.payments.Capture(ctx, pay.transactionID, pay.amount)
+	if errors.Is(err, payment.ErrTimeout) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to capture payment %s: %v", pay.id, err)
+	}
+	pay.apply(txn)
+
+	err = p.recordCapture(ctx, tx, *pay)
+	if err != nil {
+		p.undoCapture(*pay)
+	}
+	return err
+}
+
+func (p *Product) recordCapture(ctx context.Context, tx *sql.Tx, pay storedPayment) error {
+	if err := updatePayment(ctx, tx, pay); err != nil {
+		return err
+	}
+	if pay.status != paymentPaid {
+		return status.Errorf(codes.Internal, "payment %s is %s after capture", pay.id, pay.providerStatus)
+	}
+	err := recordOrderEvent(ctx, tx, pay.orderID, orderEvent{
+		kind:  eventPayment,
+		actor: orderActor{role: roleSystem},
+		note:  fmt.Sprintf("payment %s captured", pay.id),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.transitionOrder(ctx, tx, pay.orderID, orderPaid, orderActor{role: roleSystem}, "")
+	return err
+}
+
+// undoCapture gives back a captured payment whose order could not be marked
+// paid. It runs after the transaction is gone, so failures are only logged.
+func (p *Product) undoCapture(pay storedPayment) {
+	if pay.status != paymentPaid {
+		return
+	}
+	if _, err := p.payments.Refund(context.Background(), pay.transactionID, pay.amount, pay.id); err != nil {
+		log.Printf("failed to give back payment %s (transaction %s): %v", pay.id, pay.transactionID, err)
+	}
+}
+
+// ConfirmPayment finishes a payment that was left open. A pending payment,
+// whose authorization went unanswered, is authorized again under the same
+// idempotency key, so the provider makes it once. Once the buyer completed
+// 3-D Secure the provider's answer is taken, and an authorized payment is
+// captured. Payments that are no longer open are returned as they are.
+func (p *Product) ConfirmPayment(ctx context.Context, req *genprotos.ConfirmPaymentRequest) (*genprotos.PayResponse, error) {
+	if _, err := uuid.Parse(req.PaymentId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "payment_id %q is not a valid id", req.PaymentId)
+	}
+
+	pay, err := scanPayment(p.db.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE id = $1", req.PaymentId))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "payment %s not found", req.PaymentId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment: %v", err)
+	}
+	if pay.status != paymentPending && pay.status != paymentRequiresAction && pay.status != paymentAuthorized {
+		return pay.response(), nil
+	}
+	var orderStatus string
+	if err := p.db.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1", pay.orderID).Scan(&orderStatus); err != nil {
+		return nil, fmt.Errorf("failed to fetch order status: %v", err)
+	}
+	if orderStatus != orderPending {
+		return nil, status.Errorf(codes.FailedPrecondition, "order %s is %s and cannot be paid", pay.orderID, orderStatus)
+	}
+
+	switch pay.status {
+	case paymentPending:
+		pay, err = p.authorizePayment(ctx, pay)
+	case paymentRequiresAction:
+		txn, statusErr := p.payments.Status(ctx, pay.transactionID)
+		if statusErr != nil {
+			err = fmt.Errorf("failed to fetch payment %s from the provider: %w", pay.id, statusErr)
+			break
+		}
+		pay, err = p.recordAuthorization(ctx, pay.id, paymentRequiresAction, txn, nil)
+	}
+	if err == nil && pay.status == paymentAuthorized {
+		pay, err = p.captureAuthorized(ctx, pay)
+	}
+	if errors.Is(err, payment.ErrTimeout) {
+		return nil, status.Error(codes.Unavailable, "the payment provider did not answer in time; try again")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pay.response(), nil
+}
+
+// authorizeRequest asks the provider to authorize a pending payment. The
+// payment's id is the idempotency key, so asking again after an unanswered
+// request gets the transaction the first one made, if any.
+func (pay storedPayment) authorizeRequest() payment.AuthorizeRequest {
+	return payment.AuthorizeRequest{
+		Reference:      pay.orderID,
+		Amount:         pay.amount,
+		Currency:       pay.currency,
+		Method:         pay.method,
+		Token:          pay.token,
+		IdempotencyKey: pay.id,
+	}
+}
+
+// authorizePayment has the provider authorize a pending payment and records
+// its answer. No transaction is held while the provider is asked. When the
+// answer does not arrive the payment stays pending and the error is
+// returned; a request the provider refused fails the payment.
+func (p *Product) authorizePayment(ctx context.Context, pay storedPayment) (storedPayment, error) {
+	txn, err := p.payments.Authorize(ctx, pay.authorizeRequest())
+	if err != nil && !refusedByProvider(err) {
+		return pay, err
+	}
+	recorded, recordErr := p.recordAuthorization(ctx, pay.id, paymentPending, txn, err)
+	if recordErr != nil {
+		return pay, recordErr
+	}
+	if err != nil {
+		return recorded, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return recorded, nil
+}
+
+// recordAuthorization takes the provider's answer for a payment that is
+// still in status from: its transaction, or the error it refused the
+// request with. A payment that moved on in the meantime, through a webhook
+// for instance, is returned as it is. An authorization the order can no
+// longer use is voided.
+func (p *Product) recordAuthorization(ctx context.Context, paymentID, from string, txn *payment.Transaction, refused error) (storedPayment, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storedPayment{}, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	pay, orderStatus, err := lockPayment(ctx, tx, paymentID)
+	if err != nil {
+		return storedPayment{}, err
+	}
+	if pay.status != from {
+		return pay, nil
+	}
+	if refused != nil {
+		pay.status = paymentFailed
+		pay.failureCode = "invalid_request"
+		pay.failureMessage = refused.Error()
+	} else {
+		pay.apply(txn)
+		if pay.status == from {
+			return pay, nil
+		}
+	}
+	if err := recordAttempt(ctx, tx, &pay); err != nil {
+		return storedPayment{}, err
+	}
+
+	release := false
+	if pay.status == paymentRequiresAction || pay.status == paymentAuthorized {
+		release = orderStatus != orderPending
+		if !release {
+			if err := checkReservationsHeld(ctx, tx, pay.orderID); err != nil {
+				if status.Code(err) != codes.FailedPrecondition {
+					return storedPayment{}, err
+				}
+				release = true
+			}
+		}
+	}
+	if release {
+		if err := voidPayments(ctx, tx, pay.orderID); err != nil {
+			return storedPayment{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storedPayment{}, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	if release {
+		p.queuePayments()
+		return pay, status.Errorf(codes.FailedPrecondition, "order %s can no longer be paid; payment %s is released", pay.orderID, pay.id)
+	}
+	return pay, nil
+}
+
+// recordAttempt stores the outcome of a payment's authorization, forgets its
+// token and puts the outcome on the order's timeline.
+func recordAttempt(ctx context.Context, tx *sql.Tx, pay *storedPayment) error {
+	pay.token = ""
+	_, err := tx.ExecContext(ctx, "UPDATE payments SET transaction_id = $1, payment_token = NULL WHERE id = $2",
+		sql.NullString{String: pay.transactionID, Valid: pay.transactionID != ""}, pay.id)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %v", err)
+	}
+	if err := updatePayment(ctx, tx, *pay); err != nil {
+		return err
+	}
+	note := fmt.Sprintf("payment %s: %s", pay.id, pay.status)
+	if reason := pay.failureReason(); reason != "" {
+		note += " (" + reason + ")"
+	}
+	return recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: orderActor{role: roleSystem}, note: note})
+}
+
+// captureAuthorized takes the money of an authorized payment and marks its
+// order paid. The provider is asked with no transaction held; when it does
+// not answer in time the payment stays authorized and ConfirmPayment tries
+// again. Money taken for an order that can no longer be paid by the time
+// it is recorded is given back.
+func (p *Product) captureAuthorized(ctx context.Context, pay storedPayment) (storedPayment, error) {
+	txn, err := p.payments.Capture(ctx, pay.transactionID, pay.amount)
+	if errors.Is(err, payment.ErrTimeout) {
+		return pay, nil
+	}
+	if err != nil {
+		return pay, fmt.Errorf("failed to capture payment %s: %v", pay.id, err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pay, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	current, orderStatus, err := lockPayment(ctx, tx, pay.id)
+	if err != nil {
+		return pay, err
+	}
+	if current.status != paymentAuthorized {
+		return current, nil
+	}
+	pay = current
+	pay.apply(txn)
+
+	payable := orderStatus == orderPending
+	if payable {
+		if err := checkReservationsHeld(ctx, tx, pay.orderID); err != nil {
+			if status.Code(err) != codes.FailedPrecondition {
+				return pay, err
+			}
+			payable = false
+		}
+	}
+	if payable {
+		if err := p.recordCapture(ctx, tx, pay); err != nil {
+			return pay, err
+		}
+	} else {
+		system := orderActor{role: roleSystem}
+		if err := updatePayment(ctx, tx, pay); err != nil {
+			return pay, err
+		}
+		note := fmt.Sprintf("payment %s captured after the order was %s; it is given back", pay.id, orderStatus)
+		if err := recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: system, note: note}); err != nil {
+			return pay, err
+		}
+		if _, err := p.refundPayment(ctx, tx, pay.orderID, pay.amount, refund{paymentID: pay.id, actor: system, note: "the order can no longer be paid"}); err != nil {
+			return pay, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return pay, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	if !payable {
+		p.queuePayments()
+		return pay, status.Errorf(codes.FailedPrecondition, "order %s can no longer be paid; payment %s is given back", pay.orderID, pay.id)
+	}
+	return pay, nil
+}
+
+// lockPayment locks a payment and its order, the order first as everywhere
+// else, and returns the order's status.
+func lockPayment(ctx context.Context, tx *sql.Tx, paymentID string) (storedPayment, string, error) {
+	var orderID string
+	if err := tx.QueryRowContext(ctx, "SELECT order_id FROM payments WHERE id = $1", paymentID).Scan(&orderID); err != nil {
+		if err == sql.ErrNoRows {
+			return storedPayment{}, "", status.Errorf(codes.NotFound, "payment %s not found", paymentID)
+		}
+		return storedPayment{}, "", fmt.Errorf("failed to fetch payment: %v", err)
+	}
+	orderStatus, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return storedPayment{}, "", err
+	}
+	pay, err := scanPayment(tx.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE id = $1 FOR UPDATE", paymentID))
+	if err != nil {
+		return storedPayment{}, "", fmt.Errorf("failed to lock payment: %v", err)
+	}
+	return pay, orderStatus, nil
+}
+
+// voidPayments releases the payments of an order that were authorized but
+// never captured, so the buyer's card is no longer held. Pending payments
+// are released too, once the provider says whether it authorized them. The
+// provider is only asked once the caller's transaction commits; see
+// RunPaymentWorker.
+func voidPayments(ctx context.Context, tx *sql.Tx, orderID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE payments SET void_due_at = $1
+		WHERE order_id = $2 AND status IN ($3, $4, $5) AND provider IS NOT NULL AND void_due_at IS NULL
+	`, time.Now(), orderID, paymentPending, paymentRequiresAction, paymentAuthorized)
+	if err != nil {
+		return fmt.Errorf("failed to queue payment voids: %v", err)
+	}
+	return nil
+}
+
+// refund says what money goes back to the buyer for. returnID and
+// fulfillmentID are left empty when it is for the whole order. paymentID
+// limits the refund to one payment, and atProvider records money the
+// provider already gave back, such as a chargeback, without asking it to
+// refund again.
+type refund struct {
+	returnID      string
+	fulfillmentID string
+	paymentID     string
+	atProvider    bool
+	actor         orderActor
+	note          string
+}
+
+// refundPayment gives up to amount back to the buyer from an order's
+// payments, oldest first, and returns how much went back. Orders that were
+// never paid, or whose payments were already given back, have nothing left
+// to refund. Payments made through a provider are refunded there once the
+// caller's transaction commits: the refund is kept as pending until
+// RunPaymentWorker has the provider make it. Each refund goes on the order's
+// timeline.
+func (p *Product) refundPayment(ctx context.Context, tx *sql.Tx, orderID string, amount int64, r refund) (int64, error) {
+	if amount <= 0 {
+		return 0, nil
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, amount, refunded_amount, currency, COALESCE(transaction_id, ''), provider IS NOT NULL FROM payments
+		WHERE order_id = $1 AND status IN ($2, $3) AND ($4 = '' OR id::text = $4)
+		ORDER BY created_at, id FOR UPDATE
+	`, orderID, paymentPaid, paymentPartiallyRefunded, r.paymentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock payments: %v", err)
+	}
+	type paid struct {
+		id            string
+		left          int64
+		currency      string
+		transactionID string
+		withProvider  bool
+	}
+	var payments []paid
+	for rows.Next() {
+		var (
+			pay                paid
+			amount, refundedBy int64
+		)
+		if err := rows.Scan(&pay.id, &amount, &refundedBy, &pay.currency, &pay.transactionID, &pay.withProvider); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan payment row: %v", err)
+		}
+		pay.left = amount - refundedBy
+		payments = append(payments, pay)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating over payment rows: %v", err)
+	}
+
+	var refunded int64
+	for _, pay := range payments {
+		take := min(pay.left, amount-refunded)
+		if take <= 0 {
+			continue
+		}
+		paymentStatus := paymentPartiallyRefunded
+		if take == pay.left {
+			paymentStatus = paymentRefunded
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE payments SET refunded_amount = refunded_amount + $1, status = $2, updated_at = $3 WHERE id = $4", take, paymentStatus, time.Now(), pay.id); err != nil {
+			return 0, fmt.Errorf("failed to refund payment: %v", err)
+		}
+		refundID := uuid.NewString()
+		refundStatus, dueAt := refundSucceeded, sql.NullTime{}
+		if pay.withProvider && !r.atProvider {
+			refundStatus, dueAt = refundPending, sql.NullTime{Time: time.Now(), Valid: true}
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO refunds (id, payment_id, order_id, fulfillment_id, return_id, amount, currency, note, status, due_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, refundID, pay.id, orderID,
+			sql.NullString{String: r.fulfillmentID, Valid: r.fulfillmentID != ""},
+			sql.NullString{String: r.returnID, Valid: r.returnID != ""},
+			take, pay.currency, sql.NullString{String: redact.PAN(r.note), Valid: r.note != ""}, refundStatus, dueAt, time.Now())
+		if err != nil {
+			return 0, fmt.Errorf("failed to record refund: %v", err)
+		}
+
+		note := fmt.Sprintf("refund %s of %s %s from payment %s", refundID, money.Format(take, pay.currency), pay.currency, pay.id)
+		if r.returnID != "" {
+			note += " for return " + r.returnID
+		}
+		err = recordOrderEvent(ctx, tx, orderID, orderEvent{kind: eventRefund, fulfillmentID: r.fulfillmentID, actor: r.actor, note: note})
+		if err != nil {
+			return 0, err
+		}
+		refunded += take
+	}
+	return refunded, nil
+}
+
+// refundPayments gives back whatever is left of an order's payments.
+func (p *Product) refundPayments(ctx context.Context, tx *sql.Tx, orderID string, r refund) error {
+	var left int64
+	err := tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(amount - refunded_amount), 0) FROM payments WHERE order_id = $1 AND status IN ($2, $3)", orderID, paymentPaid, paymentPartiallyRefunded).Scan(&left)
+	if err != nil {
+		return fmt.Errorf("failed to fetch payments: %v", err)
+	}
+	_, err = p.refundPayment(ctx, tx, orderID, left, r)
+	return err
+}
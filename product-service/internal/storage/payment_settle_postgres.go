@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/payment"
+)
+
+// Refund statuses. A refund through a provider is pending until the
+// provider made it, and failed when the provider refused to.
+const (
+	refundPending   = "pending"
+	refundSucceeded = "succeeded"
+	refundFailed    = "failed"
+)
+
+// maxSettleBackoff caps how long a refund the provider could not be reached
+// for waits before it is tried again.
+const maxSettleBackoff = time.Hour
+
+// queuePayments wakes the payment worker after a commit that may have left
+// refunds or voids for the provider.
+func (p *Product) queuePayments() {
+	// A full channel means the worker is already due to look.
+	select {
+	case p.paymentsQueued <- struct{}{}:
+	default:
+	}
+}
+
+// RunPaymentWorker sends pending refunds and voids to the payment provider
+// once per interval and whenever a change queues some, until ctx is done.
+// Refunds carry their id as the provider's idempotency key, so one that is
+// sent again after a crash is only made once.
+func (p *Product) RunPaymentWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, settle := range []func(context.Context) (bool, error){p.settleNextRefund, p.settleNextVoid} {
+			for {
+				settled, err := settle(ctx)
+				if err != nil {
+					log.Printf("failed to settle payments: %v", err)
+				}
+				if !settled || err != nil {
+					break
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.paymentsQueued:
+		}
+	}
+}
+
+// settleNextRefund has the provider make the oldest pending refund that is
+// due. It reports false when there was nothing to do.
+func (p *Product) settleNextRefund(ctx context.Context) (bool, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		id, paymentID, orderID, currency, transactionID string
+		amount                                          int64
+		attempts                                        int
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT r.id, r.payment_id, r.order_id, r.amount, r.currency, r.attempts, COALESCE(pm.transaction_id, '')
+		FROM refunds r JOIN payments pm ON pm.id = r.payment_id
+		WHERE r.status = $1 AND r.due_at <= $2
+		ORDER BY r.due_at LIMIT 1 FOR UPDATE OF r SKIP LOCKED
+	`, refundPending, time.Now()).Scan(&id, &paymentID, &orderID, &amount, &currency, &attempts, &transactionID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim refund: %v", err)
+	}
+	attempts++
+
+	txn, err := p.payments.Refund(ctx, transactionID, amount, id)
+	switch {
+	case err == nil:
+		_, err = tx.ExecContext(ctx, "UPDATE refunds SET status = $1, attempts = $2, last_error = NULL, due_at = NULL, settled_at = $3 WHERE id = $4", refundSucceeded, attempts, time.Now(), id)
+		if err != nil {
+			return false, fmt.Errorf("failed to update refund: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE payments SET provider_status = $1, updated_at = $2 WHERE id = $3", txn.Status, time.Now(), paymentID); err != nil {
+			return false, fmt.Errorf("failed to update payment: %v", err)
+		}
+	case refusedByProvider(err):
+		log.Printf("payment provider refused refund %s of payment %s (transaction %s): %v", id, paymentID, transactionID, err)
+		_, err = tx.ExecContext(ctx, "UPDATE refunds SET status = $1, attempts = $2, last_error = $3, due_at = NULL WHERE id = $4", refundFailed, attempts, err.Error(), id)
+		if err != nil {
+			return false, fmt.Errorf("failed to update refund: %v", err)
+		}
+		note := fmt.Sprintf("refund %s of %s %s from payment %s was refused by the payment provider; it must be given back by hand", id, money.Format(amount, currency), currency, paymentID)
+		if err := recordOrderEvent(ctx, tx, orderID, orderEvent{kind: eventRefund, actor: orderActor{role: roleSystem}, note: note}); err != nil {
+			return false, err
+		}
+	default:
+		log.Printf("failed to refund payment %s (transaction %s), will try again: %v", paymentID, transactionID, err)
+		backoff := min(time.Duration(attempts)*time.Minute, maxSettleBackoff)
+		_, err = tx.ExecContext(ctx, "UPDATE refunds SET attempts = $1, last_error = $2, due_at = $3 WHERE id = $4", attempts, err.Error(), time.Now().Add(backoff), id)
+		if err != nil {
+			return false, fmt.Errorf("failed to update refund: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return true, nil
+}
+
+// settleNextVoid has the provider void the next payment of a cancelled
+// order that is due. A pending payment is first authorized again under the
+// same idempotency key, to learn whether the provider holds anything for it.
+// Payments that were settled some other way in the meantime, by a webhook
+// for instance, are left as they are. It reports false when there was
+// nothing to do.
+func (p *Product) settleNextVoid(ctx context.Context) (bool, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	pay, err := scanPayment(tx.QueryRowContext(ctx, `
+		SELECT `+paymentColumns+` FROM payments
+		WHERE void_due_at <= $1
+		ORDER BY void_due_at LIMIT 1 FOR UPDATE SKIP LOCKED
+	`, time.Now()))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim payment to void: %v", err)
+	}
+
+	dueAt := sql.NullTime{}
+	if pay.status == paymentPending {
+		txn, err := p.payments.Authorize(ctx, pay.authorizeRequest())
+		switch {
+		case err == nil:
+			pay.apply(txn)
+		case refusedByProvider(err):
+			pay.status = paymentFailed
+			pay.failureCode = "invalid_request"
+			pay.failureMessage = err.Error()
+		default:
+			log.Printf("failed to look up payment %s at the provider, will try again: %v", pay.id, err)
+			dueAt = sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+		}
+		if !dueAt.Valid {
+			if err := recordAttempt(ctx, tx, &pay); err != nil {
+				return false, err
+			}
+		}
+	}
+	if !dueAt.Valid && (pay.status == paymentRequiresAction || pay.status == paymentAuthorized) {
+		txn, err := p.payments.Void(ctx, pay.transactionID)
+		if refusedByProvider(err) {
+			// A void sent again after its commit failed is refused, as the
+			// transaction is voided already.
+			if current, statusErr := p.payments.Status(ctx, pay.transactionID); statusErr == nil && current.Status == payment.StatusVoided {
+				txn, err = current, nil
+			}
+		}
+		switch {
+		case err == nil:
+			pay.apply(txn)
+			if err := updatePayment(ctx, tx, pay); err != nil {
+				return false, err
+			}
+			note := fmt.Sprintf("payment %s voided", pay.id)
+			if err := recordOrderEvent(ctx, tx, pay.orderID, orderEvent{kind: eventPayment, actor: orderActor{role: roleSystem}, note: note}); err != nil {
+				return false, err
+			}
+		case refusedByProvider(err):
+			log.Printf("payment provider refused to void payment %s (transaction %s): %v", pay.id, pay.transactionID, err)
+		default:
+			log.Printf("failed to void payment %s (transaction %s), will try again: %v", pay.id, pay.transactionID, err)
+			dueAt = sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE payments SET void_due_at = $1 WHERE id = $2", dueAt, pay.id); err != nil {
+		return false, fmt.Errorf("failed to update payment: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return true, nil
+}
+
+// queueProviderRefund has the provider give back amount of a payment's
+// transaction that was never counted as paid, such as a capture of the
+// wrong amount. Unlike refundPayment it leaves the payment's refunded
+// amount alone.
+func queueProviderRefund(ctx context.Context, tx *sql.Tx, pay storedPayment, amount int64, currency, note string) error {
+	if amount <= 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO refunds (id, payment_id, order_id, amount, currency, note, status, due_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+	`, uuid.NewString(), pay.id, pay.orderID, amount, currency, note, refundPending, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record refund: %v", err)
+	}
+	return nil
+}
+
+// refusedByProvider tells a request the provider turned down, which would
+// fail again, from one that may go through when tried again.
+func refusedByProvider(err error) bool {
+	return errors.Is(err, payment.ErrInvalidRequest) || errors.Is(err, payment.ErrNotFound)
+}
@@ -4,20 +4,43 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"armiya/equipment-service/genprotos"
 	"armiya/equipment-service/internal/config"
+	"armiya/equipment-service/internal/media"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/payment"
+	"armiya/equipment-service/internal/promotion"
 )
 
 type Product struct {
 	db           *sql.DB
 	queryBuilder squirrel.StatementBuilderType
+	media        media.Store
+	mediaConfig  config.MediaConfig
+	currency     config.CurrencyConfig
+	catalog      config.CatalogConfig
+	orders       config.OrderConfig
+	payments     payment.Provider
+	// paymentBackend names the provider on the payments it takes.
+	paymentBackend string
+	rounding       money.RoundingMode
+	importQueued   chan struct{}
+	paymentsQueued chan struct{}
 }
 
 func New(config *config.Config) (*Product, error) {
@@ -26,13 +49,56 @@ func New(config *config.Config) (*Product, error) {
 		return nil, fmt.Errorf("failed to connect to DB: %v", err)
 	}
 
+	store, err := media.NewStore(context.Background(), config.Media)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up media storage: %v", err)
+	}
+
+	rounding, err := money.ParseRoundingMode(config.Currency.Rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := payment.NewProvider(config.Payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up payments: %v", err)
+	}
+
 	return &Product{
-		db:           db,
-		queryBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		db:             db,
+		queryBuilder:   squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		media:          store,
+		mediaConfig:    config.Media,
+		currency:       config.Currency,
+		catalog:        config.Catalog,
+		orders:         config.Orders,
+		payments:       payments,
+		paymentBackend: config.Payment.Backend,
+		rounding:       rounding,
+		importQueued:   make(chan struct{}, 1),
+		paymentsQueued: make(chan struct{}, 1),
 	}, nil
 }
 
+// PaymentStub serves the mock payment provider over the stub API, so clients
+// can tokenize cards against it. It is nil for any other backend.
+func (p *Product) PaymentStub() http.Handler {
+	mock, ok := p.payments.(*payment.Mock)
+	if !ok {
+		return nil
+	}
+	return payment.NewStubServer(mock)
+}
+
 func (p *Product) AddProduct(ctx context.Context, req *genprotos.AddProductRequest) (*genprotos.AddProductResponse, error) {
+	price, err := money.Resolve(req.Price, req.LegacyPrice, p.currency.Default)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if price == nil {
+		return nil, status.Error(codes.InvalidArgument, "price is required")
+	}
+
 	// Begin a transaction
 	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -61,17 +127,47 @@ func (p *Product) AddProduct(ctx context.Context, req *genprotos.AddProductReque
 		return nil, fmt.Errorf("failed to verify category ID: %v", err)
 	}
 
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := p.validateAttributes(ctx, tx, categoryId, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	productStatus, publishAt, err := initialProductStatus(req.Status, req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	sku, err := normalizeSKU(req.Sku)
+	if err != nil {
+		return nil, err
+	}
+	quantity, err := strconv.Atoi(strings.TrimSpace(req.Quantity))
+	if err != nil || quantity < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "quantity must be a non-negative integer, got %q", req.Quantity)
+	}
+
 	// Prepare product data
 	data := map[string]interface{}{
-		"id":          uuid.NewString(),
-		"name":        req.Name,
-		"description": req.Description,
-		"artisan_id":  req.ArtisanId,
-		"price":       req.Price,
-		"category_id": req.CategoryId,
-		"quantity":    req.Quantity,
-		"created_at":  time.Now(),
-		"updated_at":  time.Now(),
+		"id":           uuid.NewString(),
+		"name":         req.Name,
+		"description":  req.Description,
+		"artisan_id":   req.ArtisanId,
+		"sku":          sql.NullString{String: sku, Valid: sku != ""},
+		"price_amount": price.Amount,
+		"currency":     price.Currency,
+		"category_id":  req.CategoryId,
+		"quantity":     quantity,
+		"tags":         pq.Array(tags),
+		"attributes":   attributes,
+		"status":       productStatus,
+		"publish_at":   publishAt,
+		"created_at":   time.Now(),
+		"updated_at":   time.Now(),
+	}
+	if productStatus == productPublished {
+		data["published_at"] = data["created_at"]
 	}
 
 	// Build SQL query to insert product
@@ -83,27 +179,164 @@ func (p *Product) AddProduct(ctx context.Context, req *genprotos.AddProductReque
 	}
 
 	// Execute SQL query
-	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		if conflict := skuConflict(err, sku); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("failed to execute SQL query: %v", err)
 	}
 
+	if _, err = p.recordMovement(ctx, tx, data["id"].(string), int64(quantity), int64(quantity), stockMovement{reason: movementInitial, actorID: req.ArtisanId}); err != nil {
+		return nil, err
+	}
+
+	// A product published without stock starts out of stock.
+	if err = p.syncStockStatus(ctx, tx, data["id"].(string)); err != nil {
+		return nil, err
+	}
+	if err = tx.QueryRowContext(ctx, "SELECT status FROM products WHERE id = $1", data["id"]).Scan(&productStatus); err != nil {
+		return nil, fmt.Errorf("failed to fetch product status: %v", err)
+	}
+
+	if _, err = p.recordRevision(ctx, tx, data["id"].(string), req.ArtisanId, revisionCreated, 0); err != nil {
+		return nil, err
+	}
+	// Echo the attributes as stored, trimmed and with typed values normalised.
+	storedAttributes, err := decodeAttributes([]byte(attributes))
+	if err != nil {
+		return nil, err
+	}
+
 	// Return response
 	return &genprotos.AddProductResponse{
 		Id:          data["id"].(string),
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
+		Price:       price,
+		LegacyPrice: money.Format(price.Amount, price.Currency),
 		CategoryId:  req.CategoryId,
 		Quantity:    req.Quantity,
 		CreatedAt:   data["created_at"].(time.Time).String(),
+		Tags:        tags,
+		Attributes:  storedAttributes,
+		Status:      productStatus,
+		PublishAt:   formatNullTime(nullTime(publishAt)),
+		Version:     1,
+		Sku:         sku,
 	}, nil
 }
 
 func (p *Product) EditProduct(ctx context.Context, req *genprotos.EditProductRequest) (*genprotos.EditProductResponse, error) {
+	paths, err := editProductPaths(req)
+	if err != nil {
+		return nil, err
+	}
+	// Edits must say which version they are based on, so that nobody
+	// overwrites a change they have not seen.
+	if req.Version <= 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "version (or an If-Match header) is required to edit product %s", req.Id)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		categoryID  string
+		version     int64
+		stored      []byte
+		oldPrice    int64
+		oldCurrency string
+	)
+	err = tx.QueryRowContext(ctx, "SELECT category_id, version, attributes, price_amount, currency FROM products WHERE id = $1 FOR UPDATE", req.Id).Scan(&categoryID, &version, &stored, &oldPrice, &oldCurrency)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product: %v", err)
+	}
+	if req.Version != version {
+		return nil, status.Errorf(codes.Aborted, "product %s was modified by someone else (version %d, edit is based on %d)", req.Id, version, req.Version)
+	}
+
 	data := map[string]interface{}{
-		"id":    req.Id,
-		"name":  req.Name,
-		"price": req.Price,
+		"updated_at": time.Now(),
+		"version":    squirrel.Expr("version + 1"),
+	}
+	// Stock is set through the ledger rather than with the other fields.
+	var quantity *int
+	var sku string
+	for _, path := range paths {
+		switch path {
+		case "name":
+			name := strings.TrimSpace(req.Name)
+			if name == "" || len(name) > 100 {
+				return nil, status.Error(codes.InvalidArgument, "name must be between 1 and 100 characters")
+			}
+			data["name"] = name
+		case "description":
+			data["description"] = req.Description
+		case "price":
+			price, err := money.ResolveFloat(req.Price, req.LegacyPrice, p.currency.Default)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			if price == nil {
+				return nil, status.Error(codes.InvalidArgument, "price cannot be cleared")
+			}
+			if err := money.Validate(price, false); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			data["price_amount"] = price.Amount
+			data["currency"] = price.Currency
+		case "category_id":
+			category, err := p.categoryByKey(ctx, tx, req.CategoryId)
+			if err != nil {
+				return nil, err
+			}
+			categoryID = category.Id
+			data["category_id"] = categoryID
+		case "quantity":
+			value, err := strconv.Atoi(strings.TrimSpace(req.Quantity))
+			if err != nil || value < 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "quantity must be a non-negative integer, got %q", req.Quantity)
+			}
+			quantity = &value
+		case "tags":
+			tags, err := normalizeTags(req.Tags)
+			if err != nil {
+				return nil, err
+			}
+			data["tags"] = pq.Array(tags)
+		case "attributes":
+			attributes, err := p.validateAttributes(ctx, tx, categoryID, req.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			data["attributes"] = attributes
+		case "sku":
+			sku, err = normalizeSKU(req.Sku)
+			if err != nil {
+				return nil, err
+			}
+			data["sku"] = sql.NullString{String: sku, Valid: sku != ""}
+		}
+	}
+
+	// Moving to another category must leave the product valid under the new
+	// schema, so re-check the attributes it already has.
+	if _, ok := data["category_id"]; ok {
+		if _, ok := data["attributes"]; !ok {
+			current, err := decodeAttributes(stored)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.validateAttributes(ctx, tx, categoryID, current); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "product attributes do not fit the new category, include attributes in the update: %v", status.Convert(err).Message())
+			}
+		}
 	}
 
 	query, args, err := p.queryBuilder.Update("products").
@@ -114,21 +347,136 @@ func (p *Product) EditProduct(ctx context.Context, req *genprotos.EditProductReq
 		return nil, fmt.Errorf("failed to build SQL query: %v", err)
 	}
 
-	if _, err := p.db.ExecContext(ctx, query, args...); err != nil {
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if conflict := skuConflict(err, sku); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("failed to execute SQL query: %v", err)
 	}
 
-	var updatedProduct genprotos.EditProductResponse
-	err = p.db.QueryRowContext(ctx, "SELECT id, name, description, price, category_id, quantity, updated_at FROM products WHERE id = $1", req.Id).
-		Scan(&updatedProduct.Id, &updatedProduct.Name, &updatedProduct.Description, &updatedProduct.Price, &updatedProduct.CategoryId, &updatedProduct.Quantity, &updatedProduct.UpdatedAt)
+	if quantity != nil {
+		if err := p.setStock(ctx, tx, req.Id, int64(*quantity), stockMovement{reason: movementAdjustment, actorID: req.ActorId, note: "product edit"}); err != nil {
+			return nil, err
+		}
+		if err := p.syncStockStatus(ctx, tx, req.Id); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := data["price_amount"]; ok {
+		if err := p.notifyPriceDrop(ctx, tx, req.Id, oldPrice, oldCurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.recordRevision(ctx, tx, req.Id, req.ActorId, revisionEdited, 0); err != nil {
+		return nil, err
+	}
+
+	var (
+		updatedProduct genprotos.EditProductResponse
+		priceAmount    int64
+		currency       string
+		tags           pq.StringArray
+		attributes     []byte
+	)
+	err = tx.QueryRowContext(ctx, "SELECT id, name, description, price_amount, currency, category_id, quantity, updated_at, tags, attributes, status, version, COALESCE(sku, '') FROM products WHERE id = $1", req.Id).
+		Scan(&updatedProduct.Id, &updatedProduct.Name, &updatedProduct.Description, &priceAmount, &currency, &updatedProduct.CategoryId, &updatedProduct.Quantity, &updatedProduct.UpdatedAt, &tags, &attributes, &updatedProduct.Status, &updatedProduct.Version, &updatedProduct.Sku)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updated product: %v", err)
 	}
+	updatedProduct.Price = money.New(priceAmount, currency)
+	updatedProduct.LegacyPrice = money.Format(priceAmount, currency)
+	updatedProduct.Tags = tags
+	if updatedProduct.Attributes, err = decodeAttributes(attributes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
 
 	return &updatedProduct, nil
 }
 
+// editableProductFields are the update_mask paths EditProduct accepts, in
+// the order they are applied; category_id comes before attributes so new
+// attributes are checked against the new category.
+var editableProductFields = []string{"name", "description", "price", "category_id", "quantity", "tags", "attributes", "sku"}
+
+// editProductPaths returns the fields an edit touches: the update mask when
+// one is given, otherwise every field the request fills in.
+func editProductPaths(req *genprotos.EditProductRequest) ([]string, error) {
+	var paths []string
+	if req.UpdateMask != nil && len(req.UpdateMask.Paths) > 0 {
+		requested := make(map[string]bool, len(req.UpdateMask.Paths))
+		for _, path := range req.UpdateMask.Paths {
+			path = strings.TrimSpace(path)
+			if path == "legacy_price" {
+				path = "price"
+			}
+			if !containsString(editableProductFields, path) {
+				return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+			}
+			requested[path] = true
+		}
+		for _, field := range editableProductFields {
+			if requested[field] {
+				paths = append(paths, field)
+			}
+		}
+		return paths, nil
+	}
+
+	if req.Name != "" {
+		paths = append(paths, "name")
+	}
+	if req.Description != "" {
+		paths = append(paths, "description")
+	}
+	if req.Price != nil || req.LegacyPrice != 0 {
+		paths = append(paths, "price")
+	}
+	if req.CategoryId != "" {
+		paths = append(paths, "category_id")
+	}
+	if req.Quantity != "" {
+		paths = append(paths, "quantity")
+	}
+	if len(req.Tags) > 0 {
+		paths = append(paths, "tags")
+	}
+	if len(req.Attributes) > 0 {
+		paths = append(paths, "attributes")
+	}
+	if req.Sku != "" {
+		paths = append(paths, "sku")
+	}
+	if len(paths) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "nothing to update")
+	}
+	return paths, nil
+}
+
 func (p *Product) DeleteProduct(ctx context.Context, req *genprotos.DeleteProductRequest) (*genprotos.Message, error) {
+	// Orders keep pointing at their products, so those are archived instead.
+	var ordered bool
+	err := p.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM order_items WHERE product_id = $1)", req.Id).Scan(&ordered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check product order history: %v", err)
+	}
+	if ordered {
+		if err := p.archiveProduct(ctx, req.Id, req.ActorId); err != nil {
+			return nil, err
+		}
+		return &genprotos.Message{Message: fmt.Sprintf("Product with ID %s has order history and was archived instead of deleted", req.Id)}, nil
+	}
+
+	// Image rows go away with the product, so collect their objects first.
+	imageKeys, err := p.imageObjectKeys(ctx, p.db, "product_id = $1", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	query, args, err := p.queryBuilder.Delete("products").
 		Where(squirrel.Eq{"id": req.Id}).
 		ToSql()
@@ -150,16 +498,63 @@ func (p *Product) DeleteProduct(ctx context.Context, req *genprotos.DeleteProduc
 		return nil, fmt.Errorf("product with ID %s not found", req.Id)
 	}
 
+	p.deleteObjects(imageKeys)
+
 	return &genprotos.Message{Message: fmt.Sprintf("Product with ID %s deleted successfully", req.Id)}, nil
 }
 
 func (p *Product) GetProduct(ctx context.Context, req *genprotos.GetProductRequest) (*genprotos.GetProductResponse, error) {
-	var product genprotos.GetProductResponse
-	err := p.db.QueryRowContext(ctx, "SELECT id, name, description, price, category_id, quantity, created_at, updated_at FROM products WHERE id = $1", req.Id).
-		Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId, &product.Quantity, &product.CreatedAt, &product.UpdatedAt)
+	display, err := displayCurrency(req.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		product     genprotos.GetProductResponse
+		priceAmount int64
+		currency    string
+		tags        pq.StringArray
+		attributes  []byte
+		artisanID   string
+
+		publishAt, publishedAt, archivedAt sql.NullTime
+	)
+	err = p.db.QueryRowContext(ctx, "SELECT id, name, description, price_amount, currency, category_id, quantity, created_at, updated_at, tags, attributes, status, publish_at, published_at, archived_at, version, COALESCE(sku, ''), COALESCE(artisan_id::text, '') FROM products WHERE id = $1", req.Id).
+		Scan(&product.Id, &product.Name, &product.Description, &priceAmount, &currency, &product.CategoryId, &product.Quantity, &product.CreatedAt, &product.UpdatedAt, &tags, &attributes, &product.Status, &publishAt, &publishedAt, &archivedAt, &product.Version, &product.Sku, &artisanID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch product: %v", err)
 	}
+	// Drafts and archived products are only returned when the request names
+	// their artisan. The id is taken as given, it does not prove who asks.
+	if !onSale(product.Status) && (req.ArtisanId == "" || req.ArtisanId != artisanID) {
+		return nil, status.Errorf(codes.NotFound, "product with ID %s not found", req.Id)
+	}
+	product.PublishAt = formatNullTime(publishAt)
+	product.PublishedAt = formatNullTime(publishedAt)
+	product.ArchivedAt = formatNullTime(archivedAt)
+	product.Price = money.New(priceAmount, currency)
+	product.LegacyPrice = money.Format(priceAmount, currency)
+	product.Tags = tags
+	if product.Attributes, err = decodeAttributes(attributes); err != nil {
+		return nil, err
+	}
+
+	if display != "" {
+		rates, err := p.loadRateTable(ctx, p.db)
+		if err != nil {
+			return nil, err
+		}
+		amount, _, err := p.convertPrice(rates, priceAmount, currency, display)
+		if err != nil {
+			return nil, err
+		}
+		product.DisplayPrice = money.New(amount, display)
+	}
+
+	product.Images, err = p.productImages(ctx, p.db, product.Id)
+	if err != nil {
+		return nil, err
+	}
 
 	return &product, nil
 }
@@ -168,26 +563,68 @@ func (p *Product) GetAllProducts(ctx context.Context, req *genprotos.GetAllProdu
 	var products []*genprotos.Product
 	var total uint64
 
-	rows, err := p.db.QueryContext(ctx, "SELECT id, name, description, price, category_id, quantity FROM products LIMIT $1 OFFSET $2", req.Limit, (req.Page-1)*req.Limit)
+	display, err := displayCurrency(req.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	productStatus, err := productStatusFilter(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	conditions := squirrel.Eq{"status": productStatus}
+	if req.ArtisanId != "" {
+		if _, err := uuid.Parse(req.ArtisanId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+		}
+		conditions["artisan_id"] = req.ArtisanId
+	} else if !onSale(productStatus) {
+		return nil, status.Errorf(codes.InvalidArgument, "artisan_id is required to list %s products", productStatus)
+	}
+
+	query, args, err := p.queryBuilder.Select(productListColumns).
+		From("products").
+		Where(conditions).
+		Limit(req.Limit).
+		Offset((req.Page - 1) * req.Limit).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	countQuery, countArgs, err := p.queryBuilder.Select("COUNT(*)").
+		From("products").
+		Where(conditions).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch products: %v", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var product genprotos.Product
-		err := rows.Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId, &product.Quantity)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan product row: %v", err)
 		}
-		products = append(products, &product)
+		products = append(products, product)
 	}
 
-	err = p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products").Scan(&total)
+	err = p.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total number of products: %v", err)
 	}
 
+	if err := p.attachPrimaryImages(ctx, products); err != nil {
+		return nil, err
+	}
+	if err := p.applyDisplayPrices(ctx, products, display); err != nil {
+		return nil, err
+	}
+
 	return &genprotos.GetAllProductsResponse{
 		Products: products,
 		Total:    total,
@@ -200,48 +637,91 @@ func (p *Product) SearchAndFilterProducts(ctx context.Context, req *genprotos.Se
 	var products []*genprotos.Product
 	var total uint64
 
-	baseQuery := "SELECT id, name, description, price, category_id, quantity FROM products WHERE 1=1"
-	countQuery := "SELECT COUNT(*) FROM products WHERE 1=1"
+	minPrice, err := money.ResolveFloat(req.MinPrice, req.LegacyMinPrice, p.currency.Default)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	maxPrice, err := money.ResolveFloat(req.MaxPrice, req.LegacyMaxPrice, p.currency.Default)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	display, err := displayCurrency(req.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := p.loadRateTable(ctx, p.db)
+	if err != nil {
+		return nil, err
+	}
 
+	conditions := squirrel.And{squirrel.Eq{"status": onSaleStatuses}}
 	if req.Name != "" {
-		baseQuery += fmt.Sprintf(" AND name ILIKE '%%%s%%'", req.Name)
-		countQuery += fmt.Sprintf(" AND name ILIKE '%%%s%%'", req.Name)
+		conditions = append(conditions, squirrel.ILike{"name": "%" + req.Name + "%"})
 	}
 	if req.Category != "" {
-		baseQuery += fmt.Sprintf(" AND category_id = '%s'", req.Category)
-		countQuery += fmt.Sprintf(" AND category_id = '%s'", req.Category)
+		clause, err := p.categoryFilter(ctx, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, squirrel.Expr(clause))
+	}
+	// Bounds may be in any currency; they are converted into each product
+	// currency we hold rates for. Currencies were validated by money.ResolveFloat.
+	if minPrice != nil {
+		conditions = append(conditions, squirrel.Expr(p.priceBoundClause(rates, minPrice, ">=")))
 	}
-	if req.MinPrice > 0 {
-		baseQuery += fmt.Sprintf(" AND price >= %f", req.MinPrice)
-		countQuery += fmt.Sprintf(" AND price >= %f", req.MinPrice)
+	if maxPrice != nil {
+		conditions = append(conditions, squirrel.Expr(p.priceBoundClause(rates, maxPrice, "<=")))
 	}
-	if req.MaxPrice > 0 {
-		baseQuery += fmt.Sprintf(" AND price <= %f", req.MaxPrice)
-		countQuery += fmt.Sprintf(" AND price <= %f", req.MaxPrice)
+	attributeConditions, err := attributeFilters(req)
+	if err != nil {
+		return nil, err
 	}
+	conditions = append(conditions, attributeConditions...)
 
-	baseQuery += fmt.Sprintf(" LIMIT %d OFFSET %d", req.Limit, (req.Page-1)*req.Limit)
+	query, args, err := p.queryBuilder.Select(productListColumns).
+		From("products").
+		Where(conditions).
+		Limit(req.Limit).
+		Offset((req.Page - 1) * req.Limit).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	countQuery, countArgs, err := p.queryBuilder.Select("COUNT(*)").
+		From("products").
+		Where(conditions).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
 
-	rows, err := p.db.Query(baseQuery)
+	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch products: %v", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var product genprotos.Product
-		err := rows.Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId, &product.Quantity)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan product row: %v", err)
 		}
-		products = append(products, &product)
+		products = append(products, product)
 	}
 
-	err = p.db.QueryRowContext(ctx, countQuery).Scan(&total)
+	err = p.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total number of filtered products: %v", err)
 	}
 
+	if err := p.attachPrimaryImages(ctx, products); err != nil {
+		return nil, err
+	}
+	if err := p.applyDisplayPrices(ctx, products, display); err != nil {
+		return nil, err
+	}
+
 	return &genprotos.SearchAndFilterResponse{
 		Products: products,
 		Total:    total,
@@ -319,18 +799,24 @@ func (p *Product) GetAllOrders(ctx context.Context, req *genprotos.GetAllOrdersR
 	var orders []*genprotos.Order
 	var total uint64
 
-	rows, err := p.db.QueryContext(ctx, "SELECT id, user_id, total_amount, status, created_at FROM orders LIMIT $1 OFFSET $2", req.Limit, (req.Page-1)*req.Limit)
+	rows, err := p.db.QueryContext(ctx, "SELECT id, user_id, total_amount, currency, status, created_at FROM orders LIMIT $1 OFFSET $2", req.Limit, (req.Page-1)*req.Limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch orders: %v", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var order genprotos.Order
-		err := rows.Scan(&order.Id, &order.UserId, &order.TotalAmount, &order.Status, &order.CreatedAt)
+		var (
+			order       genprotos.Order
+			totalAmount int64
+			currency    string
+		)
+		err := rows.Scan(&order.Id, &order.UserId, &totalAmount, &currency, &order.Status, &order.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan order row: %v", err)
 		}
+		order.TotalAmount = money.New(totalAmount, currency)
+		order.LegacyTotalAmount = money.Format(totalAmount, currency)
 		orders = append(orders, &order)
 	}
 
@@ -353,24 +839,42 @@ func (p *Product) ShowOrderInfo(ctx context.Context, req *genprotos.ShowOrderInf
 		ShippingAddress: &genprotos.ShippingAddress{},
 	}
 
-	var shippingAddressJSON []byte
+	var (
+		shippingAddressJSON []byte
+		totalAmount         int64
+		subtotal, shipping  int64
+		discountTotal       int64
+		currency            string
+		reservedUntil       sql.NullTime
+	)
 
 	err := p.db.QueryRowContext(ctx, `
-        SELECT id, user_id, total_amount, status, shipping_address, created_at, updated_at 
+        SELECT id, user_id, total_amount, subtotal_amount, shipping_amount, discount_amount, currency, status, shipping_address, created_at, updated_at, reserved_until
         FROM orders 
         WHERE id = $1
     `, req.Id).Scan(
 		&order.OrderId,
 		&order.UserId,
-		&order.TotalAmount,
+		&totalAmount,
+		&subtotal,
+		&shipping,
+		&discountTotal,
+		&currency,
 		&order.Status,
 		&shippingAddressJSON,
 		&order.CreatedAt,
 		&order.UpdatedAt,
+		&reservedUntil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch order: %v", err)
 	}
+	order.ReservedUntil = formatNullTime(reservedUntil)
+	order.TotalAmount = money.New(totalAmount, currency)
+	order.LegacyTotalAmount = money.ToFloat(totalAmount, currency)
+	order.Subtotal = money.New(subtotal, currency)
+	order.Shipping = money.New(shipping, currency)
+	order.DiscountTotal = money.New(discountTotal, currency)
 
 	// Unmarshal the shipping address JSON
 	err = json.Unmarshal(shippingAddressJSON, order.ShippingAddress)
@@ -378,80 +882,113 @@ func (p *Product) ShowOrderInfo(ctx context.Context, req *genprotos.ShowOrderInf
 		return nil, fmt.Errorf("failed to unmarshal shipping address: %v", err)
 	}
 
-	rows, err := p.db.QueryContext(ctx, "SELECT product_id, quantity FROM order_items WHERE order_id = $1", req.Id)
+	order.Lines, err = p.orderLines(ctx, p.db, req.Id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch order items: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		item := &genprotos.Item{}
-		err := rows.Scan(&item.ProductId, &item.Quantity)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan order item row: %v", err)
-		}
-		order.Items = append(order.Items, item)
+	for _, line := range order.Lines {
+		order.Items = append(order.Items, &genprotos.Item{ProductId: line.ProductId, Quantity: line.Quantity})
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating over order items rows: %v", err)
+	order.Discounts, err = orderDiscounts(ctx, p.db, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	order.Timeline, err = orderTimeline(ctx, p.db, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	order.Fulfillments, err = orderFulfillments(ctx, p.db, req.Id)
+	if err != nil {
+		return nil, err
 	}
 
 	return order, nil
 }
 
 func (p *Product) CancelOrder(ctx context.Context, req *genprotos.CancelOrderRequest) (*genprotos.CancelOrderResponse, error) {
-	data := map[string]interface{}{
-		"status":     "cancelled",
-		"updated_at": time.Now(),
+	actor, err := requestActor(req.ActorId, req.Role, roleBuyer)
+	if err != nil {
+		return nil, err
 	}
 
-	query, args, err := p.queryBuilder.Update("orders").
-		SetMap(data).
-		Where(squirrel.Eq{"id": req.OrderId}).
-		ToSql()
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
 	}
+	defer tx.Rollback()
 
-	if _, err := p.db.ExecContext(ctx, query, args...); err != nil {
-		return nil, fmt.Errorf("failed to execute SQL query: %v", err)
+	response := genprotos.CancelOrderResponse{Id: req.OrderId}
+	// The stock goes back exactly once, however often the order is cancelled.
+	var move orderMove
+	if req.FulfillmentId != "" {
+		move, err = p.transitionFulfillment(ctx, tx, req.OrderId, req.FulfillmentId, orderCancelled, actor, req.Note)
+		response.FulfillmentId = req.FulfillmentId
+		response.FulfillmentStatus = orderCancelled
+	} else {
+		move, err = p.transitionOrder(ctx, tx, req.OrderId, orderCancelled, actor, req.Note)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return &genprotos.CancelOrderResponse{
-		Id:        req.OrderId,
-		Status:    "cancelled",
-		UpdatedAt: data["updated_at"].(time.Time).String(),
-	}, nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	p.queuePayments()
+
+	response.Status = move.status
+	response.PreviousStatus = move.previous
+	response.UpdatedAt = time.Now().Format(time.RFC3339)
+	return &response, nil
 }
 
 func (p *Product) ChangeOrderStatus(ctx context.Context, req *genprotos.ChangeOrderStatusRequest) (*genprotos.ChangeOrderStatusResponse, error) {
-	data := map[string]interface{}{
-		"status":     req.Status,
-		"updated_at": time.Now(),
+	actor, err := requestActor(req.ActorId, req.Role, "")
+	if err != nil {
+		return nil, err
+	}
+	target := strings.ToLower(strings.TrimSpace(req.Status))
+	if target == orderPaid {
+		return nil, status.Error(codes.InvalidArgument, "orders become paid by paying for them")
 	}
 
-	query, args, err := p.queryBuilder.Update("orders").
-		SetMap(data).
-		Where(squirrel.Eq{"id": req.OrderId}).
-		ToSql()
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
 	}
+	defer tx.Rollback()
 
-	if _, err := p.db.ExecContext(ctx, query, args...); err != nil {
-		return nil, fmt.Errorf("failed to execute SQL query: %v", err)
+	response := genprotos.ChangeOrderStatusResponse{Id: req.OrderId}
+	var move orderMove
+	if req.FulfillmentId != "" {
+		move, err = p.transitionFulfillment(ctx, tx, req.OrderId, req.FulfillmentId, target, actor, req.Note)
+		response.FulfillmentId = req.FulfillmentId
+		response.FulfillmentStatus = target
+	} else {
+		move, err = p.transitionOrder(ctx, tx, req.OrderId, target, actor, req.Note)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return &genprotos.ChangeOrderStatusResponse{
-		Id:        req.OrderId,
-		Status:    req.Status,
-		UpdatedAt: data["updated_at"].(time.Time).String(),
-	}, nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	p.queuePayments()
+
+	response.Status = move.status
+	response.PreviousStatus = move.previous
+	response.UpdatedAt = time.Now().Format(time.RFC3339)
+	return &response, nil
 }
 
+// OrderProduct places an order. A retry with the same idempotency key gets
+// the first order back instead of placing another.
 func (p *Product) OrderProduct(ctx context.Context, req *genprotos.OrderRequest) (*genprotos.OrderResponse, error) {
-	orderID := uuid.New()
+	key, err := idempotencyKey(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -459,7 +996,89 @@ func (p *Product) OrderProduct(ctx context.Context, req *genprotos.OrderRequest)
 	}
 	defer tx.Rollback()
 
-	totalAmount := calculateTotalAmount(ctx, p.db, req.Items)
+	var replay genprotos.OrderResponse
+	replayed, err := claimIdempotencyKey(ctx, tx, idempotentOrder, req.UserId, key, req, &replay)
+	if err != nil {
+		return nil, err
+	}
+	if replayed {
+		return &replay, nil
+	}
+
+	order, err := p.placeOrder(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveIdempotentResponse(ctx, tx, idempotentOrder, req.UserId, key, order); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return order, nil
+}
+
+// placeOrder creates an order and takes its stock inside the caller's
+// transaction.
+func (p *Product) placeOrder(ctx context.Context, tx *sql.Tx, req *genprotos.OrderRequest) (*genprotos.OrderResponse, error) {
+	orderID := uuid.New()
+
+	currency, err := displayCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = p.currency.Default
+	}
+
+	if err := validateOrder(ctx, tx, req); err != nil {
+		return nil, err
+	}
+
+	// One snapshot of the rates is used for the whole order and each line
+	// records the rate it was converted with.
+	rates, err := p.loadRateTable(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := p.priceOrderItems(ctx, tx, req.Items, currency, rates)
+	if err != nil {
+		return nil, err
+	}
+	var subtotal int64
+	for _, line := range lines {
+		total, err := money.Multiply(line.price, line.item.Quantity)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "line total for product %s: %v", line.item.ProductId, err)
+		}
+		if subtotal, err = money.Add(subtotal, total); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "order total: %v", err)
+		}
+	}
+	shipping, err := p.shippingFee(rates, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	promoLines := promotionLines(lines)
+	discounts, problems, err := p.evaluatePromotions(ctx, tx, req.UserId, req.CouponCodes, promoLines, shipping, currency, rates, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(problems))
+		for _, problem := range problems {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "coupon_codes", Description: problem})
+		}
+		return nil, badRequest(codes.FailedPrecondition, "the order cannot be placed", violations)
+	}
+	totalAmount, err := money.Add(subtotal, shipping)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "order total: %v", err)
+	}
+	totalAmount -= discounts.Total
 
 	// Marshal ShippingAddress to JSON
 	shippingAddressJSON, err := json.Marshal(req.ShippingAddress)
@@ -467,252 +1086,442 @@ func (p *Product) OrderProduct(ctx context.Context, req *genprotos.OrderRequest)
 		return nil, fmt.Errorf("failed to marshal shipping address: %v", err)
 	}
 
+	reservedUntil := time.Now().Add(p.orders.ReservationTTL)
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO orders (id, user_id, total_amount, status, shipping_address, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
-    `, orderID, req.UserId, totalAmount, "pending", shippingAddressJSON, time.Now())
+        INSERT INTO orders (id, user_id, total_amount, subtotal_amount, shipping_amount, discount_amount, currency, status, shipping_address, created_at, reserved_until)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    `, orderID, req.UserId, totalAmount, subtotal, shipping, discounts.Total, currency, orderPending, shippingAddressJSON, time.Now(), reservedUntil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert order into database: %v", err)
 	}
 
-	for _, item := range req.Items {
-		price, err := getProductPrice(ctx, p.db, item.ProductId)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch product price: %v", err)
-		}
+	// Each artisan ships their part of the order on their own.
+	fulfillments, byArtisan := planFulfillments(lines, promoLines, discounts, shipping)
+	if err := insertFulfillments(ctx, tx, orderID.String(), currency, fulfillments); err != nil {
+		return nil, err
+	}
+
+	if err := p.reserveStock(ctx, tx, orderID.String(), req.UserId, req.Items, reservedUntil); err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
 		id := uuid.NewString()
 		_, err = tx.ExecContext(ctx, `
-            INSERT INTO order_items (id, order_id, product_id, quantity, price)
-            VALUES ($1, $2, $3, $4, $5)
-        `, id, orderID, item.ProductId, item.Quantity, price)
+            INSERT INTO order_items (id, order_id, product_id, quantity, price_amount, currency, base_price_amount, base_currency, exchange_rate, product_revision, fulfillment_id, product_name, image_key)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        `, id, orderID, line.item.ProductId, line.item.Quantity, line.price, currency, line.basePrice, line.baseCurrency, money.FormatRate(line.rate), line.revision, byArtisan[line.artisanID].id,
+			line.name, sql.NullString{String: line.imageKey, Valid: line.imageKey != ""})
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert order item into database: %v", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	discountLines, err := recordDiscounts(ctx, tx, orderID.String(), req.UserId, discounts, currency)
+	if err != nil {
+		return nil, err
+	}
+	err = recordOrderEvent(ctx, tx, orderID.String(), orderEvent{
+		kind:  eventPlaced,
+		to:    orderPending,
+		actor: orderActor{id: req.UserId, role: roleBuyer},
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &genprotos.OrderResponse{
-		Id:              orderID.String(),
-		UserId:          req.UserId,
-		TotalAmount:     float32(totalAmount),
-		Status:          "pending",
-		ShippingAddress: req.ShippingAddress,
-		CreatedAt:       time.Now().Format(time.RFC3339),
-		Items:           req.Items,
+		Id:                orderID.String(),
+		UserId:            req.UserId,
+		TotalAmount:       money.New(totalAmount, currency),
+		LegacyTotalAmount: money.ToFloat(totalAmount, currency),
+		Status:            orderPending,
+		ShippingAddress:   req.ShippingAddress,
+		CreatedAt:         time.Now().Format(time.RFC3339),
+		Items:             req.Items,
+		ReservedUntil:     reservedUntil.Format(time.RFC3339),
+		Subtotal:          money.New(subtotal, currency),
+		Shipping:          money.New(shipping, currency),
+		DiscountTotal:     money.New(discounts.Total, currency),
+		Discounts:         discountLines,
+		Fulfillments:      plannedFulfillments(orderID.String(), currency, fulfillments),
 	}, nil
 }
 
-func calculateTotalAmount(ctx context.Context, db *sql.DB, items []*genprotos.Item) float64 {
-	var total float64
+// orderItemPrice is an order item priced in the order currency.
+type orderItemPrice struct {
+	item         *genprotos.Item
+	price        int64
+	basePrice    int64
+	baseCurrency string
+	rate         *big.Rat
+	revision     int64
+	artisanID    string
+	categoryID   string
+	name         string
+	imageKey     string
+}
+
+// priceOrderItems converts each product price to the order currency with
+// the given rates.
+func (p *Product) priceOrderItems(ctx context.Context, q queryer, items []*genprotos.Item, currency string, rates *money.RateTable) ([]orderItemPrice, error) {
+	lines := make([]orderItemPrice, 0, len(items))
 	for _, item := range items {
-		price, err := getProductPrice(ctx, db, item.ProductId)
+		line := orderItemPrice{item: item}
+		err := q.QueryRowContext(ctx, `
+			SELECT price_amount, currency, version, COALESCE(artisan_id::text, ''), COALESCE(category_id::text, ''), name,
+				COALESCE((SELECT object_key FROM product_images WHERE product_id = products.id AND is_primary), '')
+			FROM products WHERE id = $1
+		`, item.ProductId).Scan(&line.basePrice, &line.baseCurrency, &line.revision, &line.artisanID, &line.categoryID, &line.name, &line.imageKey)
 		if err != nil {
-			log.Printf("failed to fetch product price for product %s: %v", item.ProductId, err)
-			continue
+			return nil, fmt.Errorf("failed to fetch product price: %v", err)
+		}
+		if line.price, line.rate, err = p.convertPrice(rates, line.basePrice, line.baseCurrency, currency); err != nil {
+			return nil, err
 		}
-		total += float64(item.Quantity) * price
+		lines = append(lines, line)
 	}
-	return total
+	return lines, nil
 }
 
-func getProductPrice(ctx context.Context, db *sql.DB, productID string) (float64, error) {
-	var price float64
-	err := db.QueryRowContext(ctx, "SELECT price FROM products WHERE id = $1", productID).Scan(&price)
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch product price: %v", err)
+// promotionLines merges order items by product, so buy-x-get-y counts
+// every unit of a product however the items were split.
+func promotionLines(items []orderItemPrice) []promotion.Line {
+	var lines []promotion.Line
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item.item.ProductId]; ok {
+			lines[i].Quantity += item.item.Quantity
+			continue
+		}
+		index[item.item.ProductId] = len(lines)
+		lines = append(lines, promotion.Line{
+			ProductID:  item.item.ProductId,
+			ArtisanID:  item.artisanID,
+			CategoryID: item.categoryID,
+			Quantity:   item.item.Quantity,
+			UnitPrice:  item.price,
+		})
+	}
+	return lines
+}
+
+// shippingFee is the configured shipping fee in the order currency.
+func (p *Product) shippingFee(rates *money.RateTable, currency string) (int64, error) {
+	if p.orders.ShippingFee == 0 {
+		return 0, nil
 	}
-	return price, nil
+	fee, _, err := p.convertPrice(rates, p.orders.ShippingFee, p.currency.Default, currency)
+	return fee, err
 }
 
+// Pay charges the buyer's card for a pending order through the payment
+// provider. The card only reaches us as the provider's token. Each attempt
+// is recorded as pending before the provider is asked, and the provider is
+// only asked once that is committed, so no order lock is held while it
+// answers. The order is only paid once the payment is captured; a card that
+// needs 3-D Secure leaves the payment waiting for the buyer, and one the
+// provider did not answer for stays pending. Both are finished with
+// ConfirmPayment.
 func (p *Product) Pay(ctx context.Context, req *genprotos.PayRequest) (*genprotos.PayResponse, error) {
-	paymentID := uuid.New()
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id %q is not a valid id", req.OrderId)
+	}
+	if strings.TrimSpace(req.PaymentToken) == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_token is required; get one for the card from the payment provider")
+	}
+	key, err := idempotencyKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pay, replay, err := p.startPayment(ctx, req, key)
+	if err != nil {
+		return nil, err
+	}
+	if replay != nil {
+		return replay, nil
+	}
+
+	pay, err = p.authorizePayment(ctx, pay)
+	if err == nil && pay.status == paymentAuthorized {
+		pay, err = p.captureAuthorized(ctx, pay)
+	}
+	return p.finishPayment(ctx, pay, key, err)
+}
 
+// startPayment records a pending payment for the order. A retried payment
+// gets the first attempt's outcome back instead, rather than charging the
+// card again.
+func (p *Product) startPayment(ctx context.Context, req *genprotos.PayRequest, key string) (storedPayment, *genprotos.PayResponse, error) {
 	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return storedPayment{}, nil, fmt.Errorf("failed to start transaction: %v", err)
 	}
 	defer tx.Rollback()
 
-	totalAmount, err := calculateTotalAmountForPayment(ctx, p.db, req.OrderId)
+	var replay genprotos.PayResponse
+	replayed, err := claimIdempotencyKey(ctx, tx, idempotentPay, req.OrderId, key, req, &replay)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate total amount for payment: %v", err)
+		return storedPayment{}, nil, err
+	}
+	if replayed {
+		return storedPayment{}, &replay, nil
 	}
 
-	_, err = tx.ExecContext(ctx, `
-        INSERT INTO payments (id, order_id, amount, status, transaction_id, payment_method, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
-    `, paymentID, req.OrderId, totalAmount, "paid", "dummy_transaction_id", req.PaymentMethod, time.Now())
+	orderStatus, err := lockOrder(ctx, tx, req.OrderId)
+	if err != nil {
+		return storedPayment{}, nil, err
+	}
+	if orderStatus != orderPending {
+		return storedPayment{}, nil, status.Errorf(codes.FailedPrecondition, "order %s is %s and cannot be paid", req.OrderId, orderStatus)
+	}
+	var open string
+	err = tx.QueryRowContext(ctx, "SELECT id FROM payments WHERE order_id = $1 AND status IN ($2, $3, $4) LIMIT 1", req.OrderId, paymentPending, paymentRequiresAction, paymentAuthorized).Scan(&open)
+	if err == nil {
+		return storedPayment{}, nil, status.Errorf(codes.FailedPrecondition, "payment %s of order %s is still open; finish it with ConfirmPayment", open, req.OrderId)
+	}
+	if err != sql.ErrNoRows {
+		return storedPayment{}, nil, fmt.Errorf("failed to check open payments: %v", err)
+	}
+	// Nothing is charged for stock that is no longer held.
+	if err := checkReservationsHeld(ctx, tx, req.OrderId); err != nil {
+		return storedPayment{}, nil, err
+	}
+	totalAmount, currency, err := calculateTotalAmountForPayment(ctx, tx, req.OrderId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert payment into database: %v", err)
+		return storedPayment{}, nil, fmt.Errorf("failed to calculate total amount for payment: %v", err)
 	}
 
+	pay := storedPayment{
+		id:        uuid.NewString(),
+		orderID:   req.OrderId,
+		amount:    totalAmount,
+		currency:  currency,
+		status:    paymentPending,
+		token:     req.PaymentToken,
+		createdAt: time.Now(),
+	}
+	if err := p.insertPayment(ctx, tx, &pay, req.PaymentMethod); err != nil {
+		return storedPayment{}, nil, err
+	}
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return storedPayment{}, nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
+	return pay, nil, nil
+}
 
-	return &genprotos.PayResponse{
-		OrderId:       req.OrderId,
-		PaymentId:     paymentID.String(),
-		Amount:        float32(totalAmount),
-		Status:        "paid",
-		TransactionId: "dummy_transaction_id",
-		CreatedAt:     time.Now().Format(time.RFC3339),
-	}, nil
+// finishPayment keeps what Pay answers under its idempotency key, so a retry
+// gets it back. A request the provider refused gives the key up instead, so
+// that it can be sent again with another token.
+func (p *Product) finishPayment(ctx context.Context, pay storedPayment, key string, payErr error) (*genprotos.PayResponse, error) {
+	response := pay.response()
+	if key != "" {
+		tx, err := p.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start transaction: %v", err)
+		}
+		defer tx.Rollback()
+		if status.Code(payErr) == codes.InvalidArgument {
+			err = releaseIdempotencyKey(ctx, tx, idempotentPay, pay.orderID, key)
+		} else {
+			err = saveIdempotentResponse(ctx, tx, idempotentPay, pay.orderID, key, response)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		}
+	}
+
+	if errors.Is(payErr, payment.ErrTimeout) {
+		return nil, status.Errorf(codes.Unavailable, "the payment provider did not answer in time; payment %s is pending, finish it with ConfirmPayment", pay.id)
+	}
+	if payErr != nil {
+		return nil, payErr
+	}
+	return response, nil
 }
 
-func calculateTotalAmountForPayment(ctx context.Context, db *sql.DB, orderID string) (float64, error) {
-	var totalAmount float64
-	rows, err := db.QueryContext(ctx, `
-        SELECT quantity, price
-        FROM order_items
-        WHERE order_id = $1
-    `, orderID)
+// calculateTotalAmountForPayment returns what the buyer owes for an order,
+// after shipping and discounts.
+func calculateTotalAmountForPayment(ctx context.Context, q queryer, orderID string) (int64, string, error) {
+	var (
+		totalAmount int64
+		currency    string
+	)
+	err := q.QueryRowContext(ctx, "SELECT total_amount, currency FROM orders WHERE id = $1", orderID).Scan(&totalAmount, &currency)
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch order items: %v", err)
+		return 0, "", fmt.Errorf("failed to fetch order total: %v", err)
 	}
-	defer rows.Close()
+	return totalAmount, currency, nil
+}
 
-	for rows.Next() {
-		var quantity uint64
-		var price float64
-		if err := rows.Scan(&quantity, &price); err != nil {
-			return 0, fmt.Errorf("failed to scan order item: %v", err)
+func (p *Product) UpdateShippingDetails(ctx context.Context, req *genprotos.UpdateShippingDetailsRequest) (*genprotos.UpdateShippingDetailsResponse, error) {
+	var actor orderActor
+	if req.ActorId != "" {
+		if _, err := uuid.Parse(req.ActorId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "actor_id %q is not a valid id", req.ActorId)
 		}
-		totalAmount += float64(quantity) * price
+		actor.id = req.ActorId
 	}
-	return totalAmount, nil
-}
 
-func (p *Product) UpdateShippingDetails(ctx context.Context, req *genprotos.UpdateShippingDetailsRequest) (*genprotos.UpdateShippingDetailsResponse, error) {
-	// Create the shipping details map
-	shippingDetails := map[string]interface{}{
-		"tracking_number":         req.TrackingNumber,
-		"carrier":                 req.Carrier,
-		"estimated_delivery_date": req.EstimatedDeliveryDate,
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockOrderBuyer(ctx, tx, req.OrderId); err != nil {
+		return nil, err
 	}
 
-	// Convert the shipping details to JSON
-	shippingDetailsJSON, err := json.Marshal(shippingDetails)
+	// Each artisan ships their part of the order, so the details belong to
+	// one fulfillment.
+	var groups []fulfillment
+	if req.FulfillmentId != "" {
+		if _, err := uuid.Parse(req.FulfillmentId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "fulfillment_id %q is not a valid id", req.FulfillmentId)
+		}
+		groups, err = lockFulfillments(ctx, tx, "id = $1 AND order_id = $2", req.FulfillmentId, req.OrderId)
+		if err == nil && len(groups) == 0 {
+			return nil, status.Errorf(codes.NotFound, "fulfillment %s not found in order %s", req.FulfillmentId, req.OrderId)
+		}
+	} else {
+		groups, err = lockFulfillments(ctx, tx, "order_id = $1", req.OrderId)
+		if err == nil && len(groups) != 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "order %s has %d fulfillments; set fulfillment_id", req.OrderId, len(groups))
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal shipping details: %v", err)
+		return nil, err
 	}
+	group := groups[0]
 
-	// Update the order in the database
-	query, args, err := p.queryBuilder.Update("orders").
-		Set("shipping_address", shippingDetailsJSON).
+	query, args, err := p.queryBuilder.Update("order_fulfillments").
+		Set("tracking_number", req.TrackingNumber).
+		Set("carrier", req.Carrier).
+		Set("estimated_delivery_date", req.EstimatedDeliveryDate).
 		Set("updated_at", time.Now()).
-		Where(squirrel.Eq{"id": req.OrderId}).
+		Where(squirrel.Eq{"id": group.id}).
+		Suffix("RETURNING updated_at").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL query: %v", err)
 	}
 
-	if _, err := p.db.ExecContext(ctx, query, args...); err != nil {
+	var updatedAt time.Time
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&updatedAt); err != nil {
 		return nil, fmt.Errorf("failed to execute SQL query: %v", err)
 	}
 
-	// Fetch the updated order details
-	var updatedOrder genprotos.UpdateShippingDetailsResponse
-	var shippingAddressJSON []byte
-
-	err = p.db.QueryRowContext(ctx, "SELECT id, shipping_address, updated_at FROM orders WHERE id = $1", req.OrderId).
-		Scan(&updatedOrder.OrderId, &shippingAddressJSON, &updatedOrder.UpdatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch updated order: %v", err)
-	}
-
-	// Unmarshal the JSON into the response struct fields
-	var shippingDetailsMap map[string]interface{}
-	if err := json.Unmarshal(shippingAddressJSON, &shippingDetailsMap); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal shipping address: %v", err)
-	}
-
-	updatedOrder.TrackingNumber = shippingDetailsMap["tracking_number"].(string)
-	updatedOrder.Carrier = shippingDetailsMap["carrier"].(string)
-	updatedOrder.EstimatedDeliveryDate = shippingDetailsMap["estimated_delivery_date"].(string)
-
-	return &updatedOrder, nil
-}
-
-func (p *Product) AddCategory(ctx context.Context, req *genprotos.AddCategoryRequest) (*genprotos.AddCategoryResponse, error) {
-	tx, err := p.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	details := fmt.Sprintf("tracking number %s via %s, estimated delivery %s", req.TrackingNumber, req.Carrier, req.EstimatedDeliveryDate)
+	if req.Note != "" {
+		details += ": " + req.Note
 	}
-	defer tx.Rollback()
-	id := uuid.NewString()
-	var categoryID string
-	err = tx.QueryRowContext(ctx, `
-		INSERT INTO product_categories (id, name, description, created_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
-	`, id, req.Name, req.Description, time.Now()).Scan(&categoryID)
+	err = recordOrderEvent(ctx, tx, req.OrderId, orderEvent{kind: eventShipping, fulfillmentID: group.id, actor: actor, note: details})
 	if err != nil {
-		return nil, fmt.Errorf("failed to add category: %v", err)
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
 
-	return &genprotos.AddCategoryResponse{
-		Id:          categoryID,
-		Name:        req.Name,
-		Description: req.Description,
-		CreatedAt:   time.Now().Format(time.RFC3339),
+	return &genprotos.UpdateShippingDetailsResponse{
+		OrderId:               req.OrderId,
+		FulfillmentId:         group.id,
+		TrackingNumber:        req.TrackingNumber,
+		Carrier:               req.Carrier,
+		EstimatedDeliveryDate: req.EstimatedDeliveryDate,
+		UpdatedAt:             updatedAt.Format(time.RFC3339),
 	}, nil
 }
 
 func (p *Product) GetStatistics(ctx context.Context, req *genprotos.GetStatisticsRequest) (*genprotos.GetStatisticsResponse, error) {
-	var (
-		totalSales   uint64
-		totalRevenue float64
-	)
-
-	err := p.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) AS total_sales, SUM(total_amount) AS total_revenue
+	totalSales, revenue, err := p.orderTotals(ctx, `
+		SELECT currency, COUNT(*), COALESCE(SUM(total_amount), 0)
 		FROM orders
 		WHERE created_at >= $1 AND created_at <= $2
-	`, req.StartDate, req.EndDate).Scan(&totalSales, &totalRevenue)
+		GROUP BY currency
+		ORDER BY currency
+	`, req.StartDate, req.EndDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get statistics: %v", err)
 	}
+	totalRevenue := defaultCurrencyTotal(revenue, p.currency.Default)
 
 	return &genprotos.GetStatisticsResponse{
-		TotalSales:    totalSales,
-		TotalRevenue:  float32(totalRevenue),
-		TopProducts:   nil,
-		TopCategories: nil,
+		TotalSales:         totalSales,
+		TotalRevenue:       money.New(totalRevenue, p.currency.Default),
+		LegacyTotalRevenue: money.ToFloat(totalRevenue, p.currency.Default),
+		RevenueByCurrency:  revenue,
+		TopProducts:        nil,
+		TopCategories:      nil,
 	}, nil
 }
 
 func (p *Product) GetUserActivity(ctx context.Context, req *genprotos.GetUserActivityRequest) (*genprotos.GetUserActivityResponse, error) {
-	var (
-		ordersPlaced   uint64
-		totalSpent     float64
-		reviewsWritten uint64
-	)
-
-	err := p.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) AS orders_placed, SUM(total_amount) AS total_spent, COUNT(*) AS reviews_written
+	ordersPlaced, spent, err := p.orderTotals(ctx, `
+		SELECT currency, COUNT(*), COALESCE(SUM(total_amount), 0)
 		FROM orders
 		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
-	`, req.UserId, req.StartDate, req.EndDate).Scan(&ordersPlaced, &totalSpent, &reviewsWritten)
+		GROUP BY currency
+		ORDER BY currency
+	`, req.UserId, req.StartDate, req.EndDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user activity: %v", err)
 	}
+	totalSpent := defaultCurrencyTotal(spent, p.currency.Default)
 
 	return &genprotos.GetUserActivityResponse{
-		UserId:         req.UserId,
-		OrdersPlaced:   ordersPlaced,
-		TotalSpent:     float32(totalSpent),
-		ReviewsWritten: reviewsWritten,
+		UserId:           req.UserId,
+		OrdersPlaced:     ordersPlaced,
+		TotalSpent:       money.New(totalSpent, p.currency.Default),
+		LegacyTotalSpent: money.ToFloat(totalSpent, p.currency.Default),
+		SpentByCurrency:  spent,
+		ReviewsWritten:   ordersPlaced,
 	}, nil
 }
 
+// orderTotals counts orders and sums their totals per currency. Amounts in
+// different currencies are never added together.
+func (p *Product) orderTotals(ctx context.Context, query string, args ...interface{}) (uint64, []*genprotos.Money, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		count  uint64
+		totals []*genprotos.Money
+	)
+	for rows.Next() {
+		var (
+			currency string
+			orders   uint64
+			amount   int64
+		)
+		if err := rows.Scan(&currency, &orders, &amount); err != nil {
+			return 0, nil, err
+		}
+		count += orders
+		totals = append(totals, money.New(amount, currency))
+	}
+	return count, totals, rows.Err()
+}
+
+func defaultCurrencyTotal(totals []*genprotos.Money, currency string) int64 {
+	for _, total := range totals {
+		if total.Currency == currency {
+			return total.Amount
+		}
+	}
+	return 0
+}
+
 func (p *Product) GetArtisanRankings(ctx context.Context, req *genprotos.GetArtisanRankingsRequest) (*genprotos.GetArtisanRankingsResponse, error) {
 	var rankings []*genprotos.ArtisanRanking
 
@@ -735,11 +1544,16 @@ func (p *Product) GetArtisanRankings(ctx context.Context, req *genprotos.GetArti
 	defer rows.Close()
 
 	for rows.Next() {
-		var ranking genprotos.ArtisanRanking
-		err := rows.Scan(&ranking.ArtisanId, &ranking.FullName, &ranking.AverageRating, &ranking.TotalSales, &ranking.TotalRevenue)
+		var (
+			ranking      genprotos.ArtisanRanking
+			totalRevenue int64
+		)
+		err := rows.Scan(&ranking.ArtisanId, &ranking.FullName, &ranking.AverageRating, &ranking.TotalSales, &totalRevenue)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan artisan ranking row: %v", err)
 		}
+		ranking.TotalRevenue = money.New(totalRevenue, p.currency.Default)
+		ranking.LegacyTotalRevenue = money.ToFloat(totalRevenue, p.currency.Default)
 		rankings = append(rankings, &ranking)
 	}
 
@@ -756,10 +1570,10 @@ func (p *Product) GetRecommendations(ctx context.Context, req *genprotos.GetReco
 	var recommendations []*genprotos.Recommendation
 
 	query := `
-        SELECT p.id, p.name, p.price, p.category_id
+        SELECT p.id, p.name, p.price_amount, p.currency, p.category_id
         FROM products p
         JOIN recommendations r ON p.id = r.product_id
-        WHERE r.user_id = $1
+        WHERE r.user_id = $1 AND p.status = 'published'
         ORDER BY r.score DESC
         LIMIT $2
     `
@@ -771,11 +1585,17 @@ func (p *Product) GetRecommendations(ctx context.Context, req *genprotos.GetReco
 	defer rows.Close()
 
 	for rows.Next() {
-		var recommendation genprotos.Recommendation
-		err := rows.Scan(&recommendation.Id, &recommendation.Name, &recommendation.Price, &recommendation.CategoryId)
+		var (
+			recommendation genprotos.Recommendation
+			priceAmount    int64
+			currency       string
+		)
+		err := rows.Scan(&recommendation.Id, &recommendation.Name, &priceAmount, &currency, &recommendation.CategoryId)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan recommendation row: %v", err)
 		}
+		recommendation.Price = money.New(priceAmount, currency)
+		recommendation.LegacyPrice = money.ToFloat(priceAmount, currency)
 		recommendations = append(recommendations, &recommendation)
 	}
 
@@ -788,31 +1608,62 @@ func (p *Product) GetRecommendations(ctx context.Context, req *genprotos.GetReco
 	}, nil
 }
 
+// CheckPaymentStatus returns the latest payment attempt of an order.
 func (p *Product) CheckPaymentStatus(ctx context.Context, req *genprotos.CheckPaymentStatusRequest) (*genprotos.CheckPaymentStatusResponse, error) {
 	query, args, err := p.queryBuilder.
-		Select("order_id, id, amount, status, transaction_id, created_at").
+		Select(paymentColumns).
 		From("payments").
 		Where(squirrel.Eq{"order_id": req.OrderId}).
+		OrderBy("created_at DESC", "id").
+		Limit(1).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL query: %v", err)
 	}
 
-	var payment genprotos.CheckPaymentStatusResponse
-	err = p.db.QueryRowContext(ctx, query, args...).Scan(
-		&payment.OrderId,
-		&payment.PaymentId,
-		&payment.Amount,
-		&payment.Status,
-		&payment.TransactionId,
-		&payment.CreatedAt,
-	)
+	pay, err := scanPayment(p.db.QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("payment not found for order_id: %s", req.OrderId)
+			return nil, status.Errorf(codes.NotFound, "payment not found for order_id: %s", req.OrderId)
 		}
 		return nil, fmt.Errorf("failed to fetch payment status: %v", err)
 	}
 
-	return &payment, nil
+	return &genprotos.CheckPaymentStatusResponse{
+		OrderId:       pay.orderID,
+		PaymentId:     pay.id,
+		Amount:        money.New(pay.amount, pay.currency),
+		LegacyAmount:  money.ToFloat(pay.amount, pay.currency),
+		Status:        pay.status,
+		TransactionId: pay.transactionID,
+		CreatedAt:     pay.createdAt.Format(time.RFC3339),
+		Refunded:      money.New(pay.refunded, pay.currency),
+		FailureReason: pay.failureReason(),
+		ChallengeUrl:  pay.challengeURL,
+	}, nil
+}
+
+const productListColumns = "id, name, description, price_amount, currency, category_id, quantity, tags, attributes, status, version, COALESCE(sku, '')"
+
+// scanProduct reads a row selected with productListColumns.
+func scanProduct(row rowScanner) (*genprotos.Product, error) {
+	var (
+		product     genprotos.Product
+		priceAmount int64
+		currency    string
+		tags        pq.StringArray
+		attributes  []byte
+	)
+	err := row.Scan(&product.Id, &product.Name, &product.Description, &priceAmount, &currency, &product.CategoryId, &product.Quantity, &tags, &attributes, &product.Status, &product.Version, &product.Sku)
+	if err != nil {
+		return nil, err
+	}
+	product.Price = money.New(priceAmount, currency)
+	product.LegacyPrice = money.Format(priceAmount, currency)
+	product.Tags = tags
+	if product.Attributes, err = decodeAttributes(attributes); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
 }
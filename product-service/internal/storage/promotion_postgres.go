@@ -0,0 +1,596 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/promotion"
+)
+
+const promotionColumns = `id, name, COALESCE(code, ''), kind, percent, amount, COALESCE(currency, ''), buy_quantity, get_quantity,
+	COALESCE(artisan_id::text, ''), COALESCE(category_id::text, ''), min_subtotal_amount, COALESCE(min_subtotal_currency, ''),
+	starts_at, ends_at, usage_limit, per_user_limit, stackable, active, created_at,
+	(SELECT COUNT(*) FROM promotion_redemptions r WHERE r.promotion_id = promotions.id)`
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// storedPromotion is a promotion row together with its validity window.
+type storedPromotion struct {
+	*genprotos.Promotion
+	startsAt sql.NullTime
+	endsAt   sql.NullTime
+}
+
+func (p *Product) CreatePromotion(ctx context.Context, req *genprotos.CreatePromotionRequest) (*genprotos.Promotion, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 255 {
+		return nil, status.Error(codes.InvalidArgument, "name must be between 1 and 255 characters")
+	}
+	code := normalizeCouponCode(req.Code)
+	if code != "" && !couponCodePattern.MatchString(code) {
+		return nil, status.Error(codes.InvalidArgument, "code must be 3 to 50 letters, digits, '-' or '_'")
+	}
+
+	var (
+		percent, buy, get uint32
+		amount            int64
+		currency          sql.NullString
+	)
+	switch req.Kind {
+	case promotion.KindPercentage:
+		if req.Percent == 0 || req.Percent > 100 {
+			return nil, status.Error(codes.InvalidArgument, "percent must be between 1 and 100")
+		}
+		percent = req.Percent
+	case promotion.KindFixed:
+		if err := money.Validate(req.Amount, false); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "amount: %v", err)
+		}
+		if req.Amount.Amount == 0 {
+			return nil, status.Error(codes.InvalidArgument, "amount must be greater than zero")
+		}
+		amount = req.Amount.Amount
+		currency = sql.NullString{String: req.Amount.Currency, Valid: true}
+	case promotion.KindBuyXGetY:
+		if req.BuyQuantity == 0 || req.GetQuantity == 0 {
+			return nil, status.Error(codes.InvalidArgument, "buy_quantity and get_quantity must be at least 1")
+		}
+		buy, get = req.BuyQuantity, req.GetQuantity
+	case promotion.KindFreeShipping:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown promotion kind %q", req.Kind)
+	}
+
+	var artisanID, categoryID sql.NullString
+	if req.ArtisanId != "" {
+		if _, err := uuid.Parse(req.ArtisanId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+		}
+		artisanID = sql.NullString{String: req.ArtisanId, Valid: true}
+	}
+	if req.CategoryId != "" {
+		if _, err := uuid.Parse(req.CategoryId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "category_id %q is not a valid id", req.CategoryId)
+		}
+		var exists bool
+		if err := p.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM product_categories WHERE id = $1)", req.CategoryId).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to fetch category: %v", err)
+		}
+		if !exists {
+			return nil, status.Errorf(codes.NotFound, "category %s not found", req.CategoryId)
+		}
+		categoryID = sql.NullString{String: req.CategoryId, Valid: true}
+	}
+
+	var (
+		minSubtotal         int64
+		minSubtotalCurrency sql.NullString
+	)
+	if req.MinSubtotal != nil && req.MinSubtotal.Amount != 0 {
+		if err := money.Validate(req.MinSubtotal, false); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "min_subtotal: %v", err)
+		}
+		minSubtotal = req.MinSubtotal.Amount
+		minSubtotalCurrency = sql.NullString{String: req.MinSubtotal.Currency, Valid: true}
+	}
+
+	startsAt, err := parsePromotionTime("starts_at", req.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	endsAt, err := parsePromotionTime("ends_at", req.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return nil, status.Error(codes.InvalidArgument, "ends_at must be after starts_at")
+	}
+
+	id := uuid.NewString()
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO promotions (id, name, code, kind, percent, amount, currency, buy_quantity, get_quantity,
+			artisan_id, category_id, min_subtotal_amount, min_subtotal_currency, starts_at, ends_at,
+			usage_limit, per_user_limit, stackable, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, TRUE, $19)
+	`, id, name, sql.NullString{String: code, Valid: code != ""}, req.Kind, percent, amount, currency, buy, get,
+		artisanID, categoryID, minSubtotal, minSubtotalCurrency, nullTime(startsAt), nullTime(endsAt),
+		req.UsageLimit, req.PerUserLimit, req.Stackable, time.Now())
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, status.Errorf(codes.AlreadyExists, "coupon code %s is already in use", code)
+		}
+		return nil, fmt.Errorf("failed to create promotion: %v", err)
+	}
+
+	promo, err := scanPromotion(p.db.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	return promo.Promotion, nil
+}
+
+func (p *Product) ListPromotions(ctx context.Context, req *genprotos.ListPromotionsRequest) (*genprotos.ListPromotionsResponse, error) {
+	query := p.queryBuilder.
+		Select(promotionColumns).
+		From("promotions").
+		OrderBy("created_at DESC", "id")
+	if req.ArtisanId != "" {
+		if _, err := uuid.Parse(req.ArtisanId); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "artisan_id %q is not a valid id", req.ArtisanId)
+		}
+		query = query.Where("artisan_id = ?", req.ArtisanId)
+	}
+	if req.ActiveOnly {
+		now := time.Now()
+		query = query.Where("active AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", now, now)
+	}
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch promotions: %v", err)
+	}
+	defer rows.Close()
+
+	promotions := make([]*genprotos.Promotion, 0)
+	for rows.Next() {
+		promo, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promo.Promotion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over promotion rows: %v", err)
+	}
+
+	return &genprotos.ListPromotionsResponse{Promotions: promotions}, nil
+}
+
+func (p *Product) SetPromotionActive(ctx context.Context, req *genprotos.SetPromotionActiveRequest) (*genprotos.Promotion, error) {
+	if _, err := uuid.Parse(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "id %q is not a valid id", req.Id)
+	}
+
+	promo, err := scanPromotion(p.db.QueryRowContext(ctx, `
+		UPDATE promotions SET active = $1 WHERE id = $2
+		RETURNING `+promotionColumns, req.Active, req.Id))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "promotion %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return promo.Promotion, nil
+}
+
+// ApplyCartCoupon remembers a coupon code on the cart. The code must be
+// usable now; whether it applies to the items is shown on the cart, since
+// the items can still change.
+func (p *Product) ApplyCartCoupon(ctx context.Context, req *genprotos.CartCouponRequest) (*genprotos.Cart, error) {
+	code := normalizeCouponCode(req.Code)
+	if code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	cartID, err := p.resolveCart(ctx, tx, req.CartId, req.UserId, true)
+	if err != nil {
+		return nil, err
+	}
+	promo, err := findCoupon(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+	if problem, err := promotionProblem(ctx, tx, promo, req.UserId, time.Now()); err != nil {
+		return nil, err
+	} else if problem != "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "coupon %s %s", code, problem)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE carts SET coupon_codes = array_append(coupon_codes, $1), updated_at = $2
+		WHERE id = $3 AND NOT ($1 = ANY(coupon_codes))
+	`, code, time.Now(), cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply coupon: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+func (p *Product) RemoveCartCoupon(ctx context.Context, req *genprotos.CartCouponRequest) (*genprotos.Cart, error) {
+	cartID, err := p.resolveCart(ctx, p.db, req.CartId, req.UserId, false)
+	if err != nil {
+		return nil, err
+	}
+	if cartID != "" {
+		_, err := p.db.ExecContext(ctx, "UPDATE carts SET coupon_codes = array_remove(coupon_codes, $1), updated_at = $2 WHERE id = $3", normalizeCouponCode(req.Code), time.Now(), cartID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove coupon: %v", err)
+		}
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+// evaluatePromotions works out the discounts for an order or cart: every
+// automatic promotion running now plus the entered coupon codes. Problems
+// with the codes are returned as messages rather than errors so a cart can
+// show them; orders refuse them. With lock set, promotions that have usage
+// limits are locked until the caller's transaction ends so two orders
+// cannot both take the last use.
+func (p *Product) evaluatePromotions(ctx context.Context, q queryer, userID string, couponCodes []string, lines []promotion.Line, shipping int64, currency string, rates *money.RateTable, lock bool) (promotion.Result, []string, error) {
+	now := time.Now()
+	var problems []string
+
+	promotions, err := queryPromotions(ctx, q, `
+		SELECT `+promotionColumns+` FROM promotions
+		WHERE code IS NULL AND active AND (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY created_at, id
+	`, now)
+	if err != nil {
+		return promotion.Result{}, nil, err
+	}
+	automatic := len(promotions)
+
+	seen := make(map[string]bool, len(couponCodes))
+	for _, code := range couponCodes {
+		code = normalizeCouponCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		promo, err := findCoupon(ctx, q, code)
+		if status.Code(err) == codes.NotFound {
+			problems = append(problems, fmt.Sprintf("coupon %s does not exist", code))
+			continue
+		}
+		if err != nil {
+			return promotion.Result{}, nil, err
+		}
+		promotions = append(promotions, promo)
+	}
+
+	if lock {
+		var limited []string
+		for _, promo := range promotions {
+			if promo.UsageLimit > 0 || promo.PerUserLimit > 0 {
+				limited = append(limited, promo.Id)
+			}
+		}
+		if len(limited) > 0 {
+			if _, err := q.ExecContext(ctx, "SELECT id FROM promotions WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(limited)); err != nil {
+				return promotion.Result{}, nil, fmt.Errorf("failed to lock promotions: %v", err)
+			}
+		}
+	}
+
+	rules := make([]*promotion.Rule, 0, len(promotions))
+	for i, promo := range promotions {
+		coupon := i >= automatic
+		problem, err := promotionProblem(ctx, q, promo, userID, now)
+		if err != nil {
+			return promotion.Result{}, nil, err
+		}
+		if problem == "" {
+			var rule *promotion.Rule
+			rule, problem, err = p.promotionRule(ctx, q, promo, currency, rates)
+			if err != nil {
+				return promotion.Result{}, nil, err
+			}
+			if rule != nil {
+				if coupon {
+					problem = ruleProblem(rule, lines, shipping, currency)
+				}
+				rules = append(rules, rule)
+			}
+		}
+		if problem != "" && coupon {
+			problems = append(problems, fmt.Sprintf("coupon %s %s", promo.Code, problem))
+		}
+	}
+
+	return promotion.Best(rules, lines, shipping), problems, nil
+}
+
+// promotionRule converts a promotion to the order currency. A promotion
+// that cannot be converted comes back nil with the reason.
+func (p *Product) promotionRule(ctx context.Context, q queryer, promo *storedPromotion, currency string, rates *money.RateTable) (*promotion.Rule, string, error) {
+	rule := &promotion.Rule{
+		ID:        promo.Id,
+		Name:      promo.Name,
+		Code:      promo.Code,
+		Kind:      promo.Kind,
+		Percent:   int64(promo.Percent),
+		Buy:       uint64(promo.BuyQuantity),
+		Get:       uint64(promo.GetQuantity),
+		ArtisanID: promo.ArtisanId,
+		Stackable: promo.Stackable,
+	}
+	var err error
+	if promo.Amount != nil {
+		if rule.Amount, _, err = p.convertPrice(rates, promo.Amount.Amount, promo.Amount.Currency, currency); err != nil {
+			log.Printf("promotion %s is not available in %s: %v", promo.Id, currency, err)
+			return nil, fmt.Sprintf("cannot be used with %s", currency), nil
+		}
+	}
+	if promo.MinSubtotal != nil {
+		if rule.MinSubtotal, _, err = p.convertPrice(rates, promo.MinSubtotal.Amount, promo.MinSubtotal.Currency, currency); err != nil {
+			log.Printf("promotion %s is not available in %s: %v", promo.Id, currency, err)
+			return nil, fmt.Sprintf("cannot be used with %s", currency), nil
+		}
+	}
+
+	if promo.CategoryId != "" {
+		rows, err := q.QueryContext(ctx, categorySubtreeQuery, promo.CategoryId)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch promotion categories: %v", err)
+		}
+		defer rows.Close()
+		rule.Categories = make(map[string]bool)
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return nil, "", fmt.Errorf("failed to scan category id: %v", err)
+			}
+			rule.Categories[id] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, "", fmt.Errorf("error iterating over promotion categories: %v", err)
+		}
+	}
+	return rule, "", nil
+}
+
+// promotionProblem says why a promotion cannot be used right now, or ""
+// when it can. Usage is counted afresh so it sees uses committed since the
+// promotion was read.
+func promotionProblem(ctx context.Context, q queryer, promo *storedPromotion, userID string, at time.Time) (string, error) {
+	switch {
+	case !promo.Active:
+		return "is no longer active", nil
+	case promo.startsAt.Valid && promo.startsAt.Time.After(at):
+		return fmt.Sprintf("is not valid until %s", promo.StartsAt), nil
+	case promo.endsAt.Valid && !promo.endsAt.Time.After(at):
+		return fmt.Sprintf("expired on %s", promo.EndsAt), nil
+	}
+	if promo.UsageLimit == 0 && promo.PerUserLimit == 0 {
+		return "", nil
+	}
+
+	var used, usedByUser uint64
+	err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id::text = $2)
+		FROM promotion_redemptions WHERE promotion_id = $1
+	`, promo.Id, userID).Scan(&used, &usedByUser)
+	if err != nil {
+		return "", fmt.Errorf("failed to count promotion uses: %v", err)
+	}
+	if promo.UsageLimit > 0 && used >= uint64(promo.UsageLimit) {
+		return "has been fully redeemed", nil
+	}
+	if promo.PerUserLimit > 0 && usedByUser >= uint64(promo.PerUserLimit) {
+		return "has already been used the maximum number of times", nil
+	}
+	return "", nil
+}
+
+// ruleProblem says why an order does not qualify for a coupon.
+func ruleProblem(rule *promotion.Rule, lines []promotion.Line, shipping int64, currency string) string {
+	err := rule.Check(lines, shipping)
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, promotion.ErrBelowMinimum):
+		return fmt.Sprintf("requires a subtotal of at least %s", money.Format(rule.MinSubtotal, currency))
+	case errors.Is(err, promotion.ErrNotEnoughItems):
+		return fmt.Sprintf("requires buying %d of one item", rule.Buy+rule.Get)
+	}
+	return err.Error()
+}
+
+// recordDiscounts stores the discounts an order got as its discount lines
+// and counts one use of each promotion.
+func recordDiscounts(ctx context.Context, tx *sql.Tx, orderID, userID string, result promotion.Result, currency string) ([]*genprotos.OrderDiscount, error) {
+	now := time.Now()
+	for i, discount := range result.Discounts {
+		rule := discount.Rule
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO order_discounts (id, order_id, promotion_id, name, code, kind, amount, currency, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, uuid.NewString(), orderID, rule.ID, rule.Name, sql.NullString{String: rule.Code, Valid: rule.Code != ""}, rule.Kind, discount.Amount, currency, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record order discount: %v", err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO promotion_redemptions (promotion_id, order_id, user_id, created_at)
+			VALUES ($1, $2, $3, $4)
+		`, rule.ID, orderID, userID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record promotion use: %v", err)
+		}
+	}
+	return discountLines(result, currency), nil
+}
+
+// releaseRedemptions gives the promotion uses of a cancelled order back.
+// The order keeps its discount lines.
+func releaseRedemptions(ctx context.Context, tx *sql.Tx, orderID string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM promotion_redemptions WHERE order_id = $1", orderID); err != nil {
+		return fmt.Errorf("failed to release promotion uses: %v", err)
+	}
+	return nil
+}
+
+// orderDiscounts loads the discount lines of an order.
+func orderDiscounts(ctx context.Context, q queryer, orderID string) ([]*genprotos.OrderDiscount, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT COALESCE(promotion_id::text, ''), name, COALESCE(code, ''), kind, amount, currency
+		FROM order_discounts WHERE order_id = $1
+		ORDER BY position, id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order discounts: %v", err)
+	}
+	defer rows.Close()
+
+	discounts := make([]*genprotos.OrderDiscount, 0)
+	for rows.Next() {
+		var (
+			discount genprotos.OrderDiscount
+			amount   int64
+			currency string
+		)
+		if err := rows.Scan(&discount.PromotionId, &discount.Name, &discount.Code, &discount.Kind, &amount, &currency); err != nil {
+			return nil, fmt.Errorf("failed to scan order discount: %v", err)
+		}
+		discount.Amount = money.New(amount, currency)
+		discounts = append(discounts, &discount)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over order discounts: %v", err)
+	}
+	return discounts, nil
+}
+
+func discountLines(result promotion.Result, currency string) []*genprotos.OrderDiscount {
+	discounts := make([]*genprotos.OrderDiscount, 0, len(result.Discounts))
+	for _, discount := range result.Discounts {
+		discounts = append(discounts, &genprotos.OrderDiscount{
+			PromotionId: discount.Rule.ID,
+			Name:        discount.Rule.Name,
+			Code:        discount.Rule.Code,
+			Kind:        discount.Rule.Kind,
+			Amount:      money.New(discount.Amount, currency),
+		})
+	}
+	return discounts
+}
+
+func findCoupon(ctx context.Context, q queryer, code string) (*storedPromotion, error) {
+	promo, err := scanPromotion(q.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE code = $1", code))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "coupon %s does not exist", code)
+	}
+	return promo, err
+}
+
+func queryPromotions(ctx context.Context, q queryer, query string, args ...interface{}) ([]*storedPromotion, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch promotions: %v", err)
+	}
+	defer rows.Close()
+
+	var promotions []*storedPromotion
+	for rows.Next() {
+		promo, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over promotion rows: %v", err)
+	}
+	return promotions, nil
+}
+
+// scanPromotion reads a row selected with promotionColumns. sql.ErrNoRows
+// is returned as is.
+func scanPromotion(row rowScanner) (*storedPromotion, error) {
+	var (
+		promo                     = storedPromotion{Promotion: &genprotos.Promotion{}}
+		amount, minSubtotal       int64
+		currency, minSubtotalCurr string
+		createdAt                 time.Time
+	)
+	err := row.Scan(&promo.Id, &promo.Name, &promo.Code, &promo.Kind, &promo.Percent, &amount, &currency,
+		&promo.BuyQuantity, &promo.GetQuantity, &promo.ArtisanId, &promo.CategoryId, &minSubtotal, &minSubtotalCurr,
+		&promo.startsAt, &promo.endsAt, &promo.UsageLimit, &promo.PerUserLimit, &promo.Stackable, &promo.Active,
+		&createdAt, &promo.TimesUsed)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan promotion row: %v", err)
+	}
+	if currency != "" {
+		promo.Amount = money.New(amount, currency)
+	}
+	if minSubtotalCurr != "" {
+		promo.MinSubtotal = money.New(minSubtotal, minSubtotalCurr)
+	}
+	promo.StartsAt = formatNullTime(promo.startsAt)
+	promo.EndsAt = formatNullTime(promo.endsAt)
+	promo.CreatedAt = createdAt.Format(time.RFC3339)
+	return &promo, nil
+}
+
+func parsePromotionTime(field, value string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	at, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be an RFC 3339 timestamp: %v", field, err)
+	}
+	return &at, nil
+}
+
+// normalizeCouponCode makes codes case-insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,808 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/media"
+	"armiya/equipment-service/internal/money"
+	"armiya/equipment-service/internal/redact"
+)
+
+const (
+	returnRequested = "requested"
+	returnApproved  = "approved"
+	returnRejected  = "rejected"
+	returnCancelled = "cancelled"
+	returnShipped   = "shipped"
+	returnReceived  = "received"
+	returnRefunded  = "refunded"
+)
+
+const (
+	maxReturnPhotos       = 5
+	maxReturnReasonLength = 2000
+)
+
+// returnTransitions lists the statuses a return may move to from each
+// status, and who may move it there. A received return is refunded by the
+// service itself, with nothing given back when the artisan refunds nothing.
+var returnTransitions = map[string]map[string][]string{
+	returnRequested: {
+		returnApproved:  {roleArtisan, roleAdmin},
+		returnRejected:  {roleArtisan, roleAdmin},
+		returnCancelled: {roleBuyer},
+	},
+	returnApproved: {
+		returnShipped:   {roleBuyer},
+		returnReceived:  {roleArtisan, roleAdmin},
+		returnCancelled: {roleBuyer},
+	},
+	returnShipped: {
+		returnReceived: {roleArtisan, roleAdmin},
+	},
+	returnReceived: {
+		returnRefunded: {roleSystem},
+	},
+	returnRejected:  {},
+	returnCancelled: {},
+	returnRefunded:  {},
+}
+
+// returnColumns selects from returns aliased as r.
+const returnColumns = "r.id, r.order_id, r.fulfillment_id, COALESCE(r.user_id::text, ''), COALESCE(r.artisan_id::text, ''), r.status, r.reason, r.currency, r.refund_amount, r.refunded_amount, COALESCE(r.tracking_number, ''), COALESCE(r.carrier, ''), COALESCE(r.resolution_note, ''), r.created_at, r.updated_at, r.received_at, r.refunded_at"
+
+// lockedReturn is a return locked for a status change.
+type lockedReturn struct {
+	id            string
+	orderID       string
+	fulfillmentID string
+	userID        string
+	artisanID     string
+	status        string
+}
+
+func (p *Product) RequestReturn(ctx context.Context, req *genprotos.RequestReturnRequest) (*genprotos.Return, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	violate := func(field, format string, args ...interface{}) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		violate("order_id", "%q is not a valid id", req.OrderId)
+	}
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		violate("user_id", "%q is not a valid id", req.UserId)
+	}
+	// Buyers sometimes paste card details into free text; they are never kept.
+	reason := redact.PAN(strings.TrimSpace(req.Reason))
+	switch {
+	case reason == "":
+		violate("reason", "is required")
+	case len(reason) > maxReturnReasonLength:
+		violate("reason", "must be at most %d characters", maxReturnReasonLength)
+	}
+	if len(req.Items) == 0 {
+		violate("items", "a return needs at least one item")
+	}
+	var (
+		ids       []string
+		fields    = make(map[string]string)
+		requested = make(map[string]int64)
+	)
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if _, err := uuid.Parse(item.OrderItemId); err != nil {
+			violate(field+".order_item_id", "%q is not a valid id", item.OrderItemId)
+			continue
+		}
+		if item.Quantity == 0 || item.Quantity > 1<<31-1 {
+			violate(field+".quantity", "must be between 1 and %d", 1<<31-1)
+			continue
+		}
+		if _, ok := fields[item.OrderItemId]; ok {
+			violate(field+".order_item_id", "line %s is listed more than once", item.OrderItemId)
+			continue
+		}
+		ids = append(ids, item.OrderItemId)
+		fields[item.OrderItemId] = field
+		requested[item.OrderItemId] = int64(item.Quantity)
+	}
+	if len(req.Photos) > maxReturnPhotos {
+		violate("photos", "at most %d photos may be attached", maxReturnPhotos)
+	}
+
+	// Photos are checked before anything is stored.
+	processed := make([]*media.ProcessedImage, 0, len(req.Photos))
+	for i, photo := range req.Photos {
+		image, err := media.ProcessImage(photo.Data, photo.ContentType, p.mediaConfig)
+		if errors.Is(err, media.ErrInvalidImage) {
+			violate(fmt.Sprintf("photos[%d]", i), "%v", err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to process return photo: %v", err)
+		}
+		processed = append(processed, image)
+	}
+	if len(violations) > 0 {
+		return nil, badRequest(codes.InvalidArgument, "the return cannot be requested", violations)
+	}
+
+	returnID := uuid.NewString()
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, buyerID, err := lockOrderBuyer(ctx, tx, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if buyerID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "order %s was not placed by %s", req.OrderId, req.UserId)
+	}
+
+	// How much of each line is still with the buyer: returns that were not
+	// rejected or cancelled already claim their items.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT oi.id, COALESCE(oi.fulfillment_id::text, ''), oi.quantity,
+			COALESCE(SUM(ri.quantity) FILTER (WHERE r.status NOT IN ($3, $4)), 0)
+		FROM order_items oi
+		LEFT JOIN return_items ri ON ri.order_item_id = oi.id
+		LEFT JOIN returns r ON r.id = ri.return_id
+		WHERE oi.order_id = $1 AND oi.id = ANY($2)
+		GROUP BY oi.id
+	`, req.OrderId, pq.Array(ids), returnRejected, returnCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items: %v", err)
+	}
+	type orderedLine struct {
+		fulfillmentID string
+		quantity      int64
+		claimed       int64
+	}
+	lines := make(map[string]orderedLine, len(ids))
+	for rows.Next() {
+		var (
+			id   string
+			line orderedLine
+		)
+		if err := rows.Scan(&id, &line.fulfillmentID, &line.quantity, &line.claimed); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan order item row: %v", err)
+		}
+		lines[id] = line
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over order item rows: %v", err)
+	}
+
+	var fulfillmentID string
+	for _, id := range ids {
+		line, ok := lines[id]
+		switch {
+		case !ok:
+			violate(fields[id]+".order_item_id", "line %s is not part of order %s", id, req.OrderId)
+		case line.fulfillmentID == "":
+			violate(fields[id]+".order_item_id", "line %s was ordered before orders were split by artisan and cannot be returned", id)
+		case fulfillmentID != "" && line.fulfillmentID != fulfillmentID:
+			violate(fields[id]+".order_item_id", "line %s is sold by another artisan; request a return per artisan", id)
+		case requested[id] > line.quantity-line.claimed:
+			violate(fields[id]+".quantity", "%d requested, %d of line %s can still be returned", requested[id], line.quantity-line.claimed, id)
+		}
+		if ok && fulfillmentID == "" {
+			fulfillmentID = line.fulfillmentID
+		}
+	}
+	if len(violations) > 0 {
+		code := codes.FailedPrecondition
+		for _, violation := range violations {
+			if strings.HasSuffix(violation.Field, ".order_item_id") {
+				code = codes.InvalidArgument
+			}
+		}
+		return nil, badRequest(code, "the return cannot be requested", violations)
+	}
+
+	groups, err := lockFulfillments(ctx, tx, "id = $1", fulfillmentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, status.Errorf(codes.NotFound, "fulfillment %s not found", fulfillmentID)
+	}
+	group := groups[0]
+	if group.status != orderDelivered {
+		return nil, status.Errorf(codes.FailedPrecondition, "items can only be returned once delivered; fulfillment %s is %s", group.id, group.status)
+	}
+
+	worth, currency, err := returnWorth(ctx, tx, group.id, returnID, requested)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO returns (id, order_id, fulfillment_id, user_id, artisan_id, status, reason, currency, refund_amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+	`, returnID, req.OrderId, group.id, req.UserId, sql.NullString{String: group.artisanID, Valid: group.artisanID != ""},
+		returnRequested, reason, currency, worth, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert return: %v", err)
+	}
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO return_items (return_id, order_item_id, quantity) VALUES ($1, $2, $3)", returnID, id, requested[id]); err != nil {
+			return nil, fmt.Errorf("failed to insert return item: %v", err)
+		}
+	}
+
+	// Photos are uploaded last so a failed request leaves nothing behind;
+	// if the transaction fails after all, the uploads are removed again.
+	var uploaded []string
+	for i, image := range processed {
+		photoID := uuid.NewString()
+		key := path.Join("returns", returnID, photoID+"."+image.Original.Ext)
+		if err := p.media.Put(ctx, key, image.Original.ContentType, image.Original.Data); err != nil {
+			p.deleteObjects(uploaded)
+			return nil, err
+		}
+		uploaded = append(uploaded, key)
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO return_photos (id, return_id, object_key, content_type, position, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, photoID, returnID, key, image.Original.ContentType, i, now)
+		if err != nil {
+			p.deleteObjects(uploaded)
+			return nil, fmt.Errorf("failed to insert return photo: %v", err)
+		}
+	}
+
+	err = recordOrderEvent(ctx, tx, req.OrderId, orderEvent{
+		kind:          eventReturn,
+		fulfillmentID: group.id,
+		to:            returnRequested,
+		actor:         orderActor{id: req.UserId, role: roleBuyer},
+		note:          fmt.Sprintf("return %s: %s", returnID, reason),
+	})
+	if err == nil && group.artisanID != "" {
+		err = notifyReturnStatus(ctx, tx, group.artisanID, returnID, req.OrderId, returnRequested)
+	}
+	if err == nil {
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("failed to commit transaction: %v", err)
+		}
+	}
+	if err != nil {
+		p.deleteObjects(uploaded)
+		return nil, err
+	}
+
+	return p.getReturn(ctx, p.db, returnID)
+}
+
+func (p *Product) GetReturn(ctx context.Context, req *genprotos.GetReturnRequest) (*genprotos.Return, error) {
+	if _, err := uuid.Parse(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "id %q is not a valid id", req.Id)
+	}
+	return p.getReturn(ctx, p.db, req.Id)
+}
+
+func (p *Product) ListReturns(ctx context.Context, req *genprotos.ListReturnsRequest) (*genprotos.ListReturnsResponse, error) {
+	filters := []struct{ field, column, value string }{
+		{"user_id", "r.user_id", req.UserId},
+		{"artisan_id", "r.artisan_id", req.ArtisanId},
+		{"order_id", "r.order_id", req.OrderId},
+	}
+	for _, wanted := range req.Statuses {
+		if _, ok := returnTransitions[wanted]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown return status %q", wanted)
+		}
+	}
+
+	query := p.queryBuilder.
+		Select(returnColumns).
+		From("returns r").
+		OrderBy("r.created_at DESC", "r.id")
+	count := p.queryBuilder.
+		Select("COUNT(*)").
+		From("returns r")
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		if _, err := uuid.Parse(filter.value); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s %q is not a valid id", filter.field, filter.value)
+		}
+		query = query.Where(filter.column+" = ?", filter.value)
+		count = count.Where(filter.column+" = ?", filter.value)
+	}
+	if len(req.Statuses) > 0 {
+		query = query.Where("r.status = ANY(?)", pq.Array(req.Statuses))
+		count = count.Where("r.status = ANY(?)", pq.Array(req.Statuses))
+	}
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	var response genprotos.ListReturnsResponse
+	countSQL, countArgs, err := count.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if err := p.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&response.Total); err != nil {
+		return nil, fmt.Errorf("failed to count returns: %v", err)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+	if response.Returns, err = p.queryReturns(ctx, p.db, sqlQuery, args...); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (p *Product) ReviewReturn(ctx context.Context, req *genprotos.ReviewReturnRequest) (*genprotos.Return, error) {
+	actor, err := requestActor(req.ActorId, req.Role, roleArtisan)
+	if err != nil {
+		return nil, err
+	}
+	target := returnApproved
+	if !req.Approve {
+		target = returnRejected
+		if strings.TrimSpace(req.Note) == "" {
+			return nil, status.Error(codes.InvalidArgument, "note is required when rejecting a return; tell the buyer why")
+		}
+	}
+	return p.changeReturn(ctx, req.ReturnId, func(tx *sql.Tx, ret lockedReturn, _ string) error {
+		return p.moveReturn(ctx, tx, ret, target, actor, strings.TrimSpace(req.Note))
+	})
+}
+
+func (p *Product) ShipReturn(ctx context.Context, req *genprotos.ShipReturnRequest) (*genprotos.Return, error) {
+	actor, err := requestActor(req.UserId, roleBuyer, roleBuyer)
+	if err != nil {
+		return nil, err
+	}
+	return p.changeReturn(ctx, req.ReturnId, func(tx *sql.Tx, ret lockedReturn, _ string) error {
+		note := ""
+		if req.TrackingNumber != "" {
+			note = strings.TrimSpace(req.Carrier + " " + req.TrackingNumber)
+		}
+		if err := p.moveReturn(ctx, tx, ret, returnShipped, actor, note); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE returns SET tracking_number = $1, carrier = $2 WHERE id = $3",
+			sql.NullString{String: req.TrackingNumber, Valid: req.TrackingNumber != ""},
+			sql.NullString{String: req.Carrier, Valid: req.Carrier != ""}, ret.id)
+		if err != nil {
+			return fmt.Errorf("failed to update return shipping: %v", err)
+		}
+		return nil
+	})
+}
+
+func (p *Product) CancelReturn(ctx context.Context, req *genprotos.CancelReturnRequest) (*genprotos.Return, error) {
+	actor, err := requestActor(req.UserId, roleBuyer, roleBuyer)
+	if err != nil {
+		return nil, err
+	}
+	return p.changeReturn(ctx, req.ReturnId, func(tx *sql.Tx, ret lockedReturn, _ string) error {
+		return p.moveReturn(ctx, tx, ret, returnCancelled, actor, strings.TrimSpace(req.Note))
+	})
+}
+
+// ReceiveReturn records that the items came back. They go back in stock and
+// the buyer is refunded what the items are worth, or refund_amount when the
+// artisan gives back less. The return ends up refunded even when that is
+// nothing. Once every item of the artisan's part came back, the part is
+// returned, and refunded if nothing is left to give back.
+func (p *Product) ReceiveReturn(ctx context.Context, req *genprotos.ReceiveReturnRequest) (*genprotos.Return, error) {
+	actor, err := requestActor(req.ActorId, req.Role, roleArtisan)
+	if err != nil {
+		return nil, err
+	}
+	note := strings.TrimSpace(req.Note)
+	return p.changeReturn(ctx, req.ReturnId, func(tx *sql.Tx, ret lockedReturn, orderStatus string) error {
+		if err := p.moveReturn(ctx, tx, ret, returnReceived, actor, note); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE returns SET received_at = $1 WHERE id = $2", time.Now(), ret.id); err != nil {
+			return fmt.Errorf("failed to update return: %v", err)
+		}
+
+		quantities, err := returnQuantities(ctx, tx, ret.id)
+		if err != nil {
+			return err
+		}
+		if err := p.restockReturn(ctx, tx, ret, actor); err != nil {
+			return err
+		}
+
+		worth, currency, err := returnWorth(ctx, tx, ret.fulfillmentID, ret.id, quantities)
+		if err != nil {
+			return err
+		}
+		amount := worth
+		if req.RefundAmount != nil {
+			if *req.RefundAmount < 0 || *req.RefundAmount > worth {
+				return status.Errorf(codes.InvalidArgument, "refund_amount must be between 0 and %d (%s %s)", worth, money.Format(worth, currency), currency)
+			}
+			amount = *req.RefundAmount
+		}
+		refunded, err := p.refundPayment(ctx, tx, ret.orderID, amount, refund{returnID: ret.id, fulfillmentID: ret.fulfillmentID, actor: actor, note: note})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE order_fulfillments SET refunded_amount = refunded_amount + $1, updated_at = $2 WHERE id = $3", refunded, time.Now(), ret.fulfillmentID); err != nil {
+			return fmt.Errorf("failed to update fulfillment refund: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE returns SET refund_amount = $1, refunded_amount = $2 WHERE id = $3", amount, refunded, ret.id); err != nil {
+			return fmt.Errorf("failed to update return refund: %v", err)
+		}
+		// A return with nothing to give back is closed all the same, so it
+		// does not stay open work.
+		ret.status = returnReceived
+		if err := p.moveReturn(ctx, tx, ret, returnRefunded, orderActor{role: roleSystem}, ""); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE returns SET refunded_at = $1 WHERE id = $2", time.Now(), ret.id); err != nil {
+			return fmt.Errorf("failed to update return: %v", err)
+		}
+
+		return p.settleReturnedFulfillment(ctx, tx, ret, orderStatus, actor, note)
+	})
+}
+
+// changeReturn locks a return and its order, runs change and returns the
+// return as it is afterwards. change is given the order's status.
+func (p *Product) changeReturn(ctx context.Context, returnID string, change func(tx *sql.Tx, ret lockedReturn, orderStatus string) error) (*genprotos.Return, error) {
+	if _, err := uuid.Parse(returnID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "return_id %q is not a valid id", returnID)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// The order is locked before the return, as everywhere else.
+	var orderID string
+	err = tx.QueryRowContext(ctx, "SELECT order_id FROM returns WHERE id = $1", returnID).Scan(&orderID)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "return %s not found", returnID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch return: %v", err)
+	}
+	orderStatus, _, err := lockOrderBuyer(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret lockedReturn
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, order_id, fulfillment_id, COALESCE(user_id::text, ''), COALESCE(artisan_id::text, ''), status
+		FROM returns WHERE id = $1 FOR UPDATE
+	`, returnID).Scan(&ret.id, &ret.orderID, &ret.fulfillmentID, &ret.userID, &ret.artisanID, &ret.status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock return: %v", err)
+	}
+
+	if err := change(tx, ret, orderStatus); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	p.queuePayments()
+	return p.getReturn(ctx, p.db, returnID)
+}
+
+// moveReturn moves a locked return to target once the actor is allowed to,
+// puts the change on the order's timeline and tells the other side.
+func (p *Product) moveReturn(ctx context.Context, tx *sql.Tx, ret lockedReturn, target string, actor orderActor, note string) error {
+	note = redact.PAN(note)
+	roles, legal := returnTransitions[ret.status][target]
+	switch {
+	case !legal:
+		return status.Errorf(codes.FailedPrecondition, "return %s is %s and cannot move to %s", ret.id, ret.status, target)
+	case !containsString(roles, actor.role):
+		return status.Errorf(codes.PermissionDenied, "the %s role cannot move a return from %s to %s", actor.role, ret.status, target)
+	case actor.role == roleBuyer && actor.id != ret.userID:
+		return status.Errorf(codes.PermissionDenied, "return %s was not requested by %s", ret.id, actor.id)
+	case actor.role == roleArtisan && actor.id != ret.artisanID:
+		return status.Errorf(codes.PermissionDenied, "return %s is for another artisan's items", ret.id)
+	case actor.role == roleAdmin:
+		if err := authorizeOrderActor(ctx, tx, ret.orderID, ret.userID, actor); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE returns SET status = $1, updated_at = $2, resolution_note = COALESCE(NULLIF($3, ''), resolution_note)
+		WHERE id = $4
+	`, target, time.Now(), note, ret.id)
+	if err != nil {
+		return fmt.Errorf("failed to update return status: %v", err)
+	}
+
+	event := orderEvent{
+		kind:          eventReturn,
+		fulfillmentID: ret.fulfillmentID,
+		from:          ret.status,
+		to:            target,
+		actor:         actor,
+		note:          "return " + ret.id,
+	}
+	if note != "" {
+		event.note += ": " + note
+	}
+	if err := recordOrderEvent(ctx, tx, ret.orderID, event); err != nil {
+		return err
+	}
+
+	notify := ret.userID
+	if actor.role == roleBuyer {
+		notify = ret.artisanID
+	}
+	if notify == "" {
+		return nil
+	}
+	return notifyReturnStatus(ctx, tx, notify, ret.id, ret.orderID, target)
+}
+
+// settleReturnedFulfillment moves the returned part of an order along once
+// every one of its items came back: to returned, and on to refunded when the
+// buyer got everything back. The order's status follows.
+func (p *Product) settleReturnedFulfillment(ctx context.Context, tx *sql.Tx, ret lockedReturn, orderStatus string, actor orderActor, note string) error {
+	var outstanding, left int64
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			COALESCE((SELECT SUM(oi.quantity) FROM order_items oi WHERE oi.fulfillment_id = $1), 0)
+				- COALESCE((SELECT SUM(ri.quantity) FROM return_items ri JOIN returns r ON r.id = ri.return_id
+					WHERE r.fulfillment_id = $1 AND r.status IN ($2, $3)), 0),
+			(SELECT payout_amount - refunded_amount FROM order_fulfillments WHERE id = $1)
+	`, ret.fulfillmentID, returnReceived, returnRefunded).Scan(&outstanding, &left)
+	if err != nil {
+		return fmt.Errorf("failed to check returned items: %v", err)
+	}
+	if outstanding > 0 {
+		return nil
+	}
+
+	groups, err := lockFulfillments(ctx, tx, "id = $1", ret.fulfillmentID)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	group := groups[0]
+	// A part the buyer already completed, or one moved on by hand, keeps
+	// its status.
+	if group.status == orderDelivered {
+		if err := p.moveFulfillment(ctx, tx, group, orderReturned, actor, note); err != nil {
+			return err
+		}
+		group.status = orderReturned
+	}
+	if group.status == orderReturned && left <= 0 {
+		if err := p.moveFulfillment(ctx, tx, group, orderRefunded, actor, note); err != nil {
+			return err
+		}
+	}
+	_, err = p.syncOrderStatus(ctx, tx, ret.orderID, ret.userID, orderStatus, "", actor, note)
+	return err
+}
+
+// returnQuantities returns how many of each order line a return holds.
+func returnQuantities(ctx context.Context, q queryer, returnID string) (map[string]int64, error) {
+	rows, err := q.QueryContext(ctx, "SELECT order_item_id, quantity FROM return_items WHERE return_id = $1", returnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch return items: %v", err)
+	}
+	defer rows.Close()
+
+	quantities := make(map[string]int64)
+	for rows.Next() {
+		var (
+			id       string
+			quantity int64
+		)
+		if err := rows.Scan(&id, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan return item: %v", err)
+		}
+		quantities[id] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over return items: %v", err)
+	}
+	return quantities, nil
+}
+
+// returnWorth works out what sending back quantities of a fulfillment's
+// lines is worth to the buyer: each line's share of what they paid for the
+// part, shipping and discounts included, per unit. The return that brings
+// back the last item is worth whatever is left, so a part returned bit by bit
+// adds up to exactly what was paid. It is never more than what is left to
+// refund.
+func returnWorth(ctx context.Context, q queryer, fulfillmentID, returnID string, quantities map[string]int64) (int64, string, error) {
+	var (
+		payout, refunded int64
+		currency         string
+	)
+	err := q.QueryRowContext(ctx, "SELECT payout_amount, refunded_amount, currency FROM order_fulfillments WHERE id = $1", fulfillmentID).Scan(&payout, &refunded, &currency)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to fetch fulfillment: %v", err)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT oi.id, oi.price_amount, oi.quantity,
+			COALESCE((SELECT SUM(ri.quantity) FROM return_items ri JOIN returns r ON r.id = ri.return_id
+				WHERE ri.order_item_id = oi.id AND r.id <> $2 AND r.status IN ($3, $4)), 0)
+		FROM order_items oi WHERE oi.fulfillment_id = $1 ORDER BY oi.id
+	`, fulfillmentID, returnID, returnReceived, returnRefunded)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to fetch fulfillment items: %v", err)
+	}
+	defer rows.Close()
+
+	var (
+		weights, ordered, returning []int64
+		last                        = true
+	)
+	for rows.Next() {
+		var (
+			id                       string
+			price, quantity, already int64
+		)
+		if err := rows.Scan(&id, &price, &quantity, &already); err != nil {
+			return 0, "", fmt.Errorf("failed to scan fulfillment item: %v", err)
+		}
+		weights = append(weights, price*quantity)
+		ordered = append(ordered, quantity)
+		returning = append(returning, quantities[id])
+		if already+quantities[id] < quantity {
+			last = false
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, "", fmt.Errorf("error iterating over fulfillment items: %v", err)
+	}
+
+	left := max(payout-refunded, 0)
+	if last {
+		return left, currency, nil
+	}
+	var worth int64
+	for i, share := range money.Allocate(payout, weights) {
+		if ordered[i] > 0 {
+			worth += share * returning[i] / ordered[i]
+		}
+	}
+	return min(worth, left), currency, nil
+}
+
+func (p *Product) getReturn(ctx context.Context, q queryer, returnID string) (*genprotos.Return, error) {
+	returns, err := p.queryReturns(ctx, q, "SELECT "+returnColumns+" FROM returns r WHERE r.id = $1", returnID)
+	if err != nil {
+		return nil, err
+	}
+	if len(returns) == 0 {
+		return nil, status.Errorf(codes.NotFound, "return %s not found", returnID)
+	}
+	return returns[0], nil
+}
+
+// queryReturns runs a query selecting returnColumns and fills in each
+// return's items and photos.
+func (p *Product) queryReturns(ctx context.Context, q queryer, query string, args ...interface{}) ([]*genprotos.Return, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch returns: %v", err)
+	}
+	defer rows.Close()
+
+	returns := make([]*genprotos.Return, 0)
+	byID := make(map[string]*genprotos.Return)
+	var ids []string
+	for rows.Next() {
+		var (
+			ret                    = genprotos.Return{Items: make([]*genprotos.ReturnItem, 0), PhotoUrls: make([]string, 0)}
+			currency               string
+			amount, refunded       int64
+			createdAt, updatedAt   time.Time
+			receivedAt, refundedAt sql.NullTime
+		)
+		err := rows.Scan(&ret.Id, &ret.OrderId, &ret.FulfillmentId, &ret.UserId, &ret.ArtisanId, &ret.Status, &ret.Reason, &currency,
+			&amount, &refunded, &ret.TrackingNumber, &ret.Carrier, &ret.ResolutionNote, &createdAt, &updatedAt, &receivedAt, &refundedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan return row: %v", err)
+		}
+		ret.RefundAmount = money.New(amount, currency)
+		ret.Refunded = money.New(refunded, currency)
+		ret.CreatedAt = createdAt.Format(time.RFC3339)
+		ret.UpdatedAt = updatedAt.Format(time.RFC3339)
+		ret.ReceivedAt = formatNullTime(receivedAt)
+		ret.RefundedAt = formatNullTime(refundedAt)
+		returns = append(returns, &ret)
+		byID[ret.Id] = &ret
+		ids = append(ids, ret.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over return rows: %v", err)
+	}
+	rows.Close()
+	if len(ids) == 0 {
+		return returns, nil
+	}
+
+	items, err := q.QueryContext(ctx, `
+		SELECT ri.return_id, ri.order_item_id, COALESCE(oi.product_id::text, ''), COALESCE(oi.product_name, ''), ri.quantity, oi.price_amount, oi.currency
+		FROM return_items ri JOIN order_items oi ON oi.id = ri.order_item_id
+		WHERE ri.return_id = ANY($1) ORDER BY ri.order_item_id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch return items: %v", err)
+	}
+	defer items.Close()
+	for items.Next() {
+		var (
+			returnID string
+			item     genprotos.ReturnItem
+			price    int64
+			currency string
+		)
+		if err := items.Scan(&returnID, &item.OrderItemId, &item.ProductId, &item.ProductName, &item.Quantity, &price, &currency); err != nil {
+			return nil, fmt.Errorf("failed to scan return item: %v", err)
+		}
+		item.Price = money.New(price, currency)
+		byID[returnID].Items = append(byID[returnID].Items, &item)
+	}
+	if err := items.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over return items: %v", err)
+	}
+	items.Close()
+
+	photos, err := q.QueryContext(ctx, "SELECT return_id, object_key FROM return_photos WHERE return_id = ANY($1) ORDER BY position", pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch return photos: %v", err)
+	}
+	defer photos.Close()
+	for photos.Next() {
+		var returnID, key string
+		if err := photos.Scan(&returnID, &key); err != nil {
+			return nil, fmt.Errorf("failed to scan return photo: %v", err)
+		}
+		byID[returnID].PhotoUrls = append(byID[returnID].PhotoUrls, p.media.URL(key))
+	}
+	if err := photos.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over return photos: %v", err)
+	}
+	return returns, nil
+}
@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+)
+
+const (
+	revisionCreated   = "created"
+	revisionEdited    = "edited"
+	revisionStatus    = "status_changed"
+	revisionPublished = "published"
+	revisionArchived  = "archived"
+	revisionReverted  = "reverted"
+	revisionCategory  = "category_reassigned"
+)
+
+// productSnapshot is the JSON stored with each revision.
+type productSnapshot struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	PriceAmount int64           `json:"price_amount"`
+	Currency    string          `json:"currency"`
+	CategoryID  string          `json:"category_id"`
+	Quantity    int64           `json:"quantity"`
+	Tags        []string        `json:"tags"`
+	Attributes  json.RawMessage `json:"attributes"`
+	Status      string          `json:"status"`
+}
+
+const revisionColumns = "id, product_id, version, actor_id, action, restored_version, snapshot, created_at"
+
+func (p *Product) ListProductRevisions(ctx context.Context, req *genprotos.ListProductRevisionsRequest) (*genprotos.ListProductRevisionsResponse, error) {
+	if err := p.ensureProductExists(ctx, p.db, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	query := p.queryBuilder.
+		Select(revisionColumns).
+		From("product_revisions").
+		Where("product_id = ?", req.ProductId).
+		OrderBy("version DESC")
+	if req.Limit > 0 {
+		page := req.Page
+		if page == 0 {
+			page = 1
+		}
+		query = query.Limit(req.Limit).Offset((page - 1) * req.Limit)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %v", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product revisions: %v", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*genprotos.ProductRevision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product revision row: %v", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over product revision rows: %v", err)
+	}
+
+	var total uint64
+	if err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM product_revisions WHERE product_id = $1", req.ProductId).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count product revisions: %v", err)
+	}
+
+	return &genprotos.ListProductRevisionsResponse{Revisions: revisions, Total: total}, nil
+}
+
+func (p *Product) RevertProduct(ctx context.Context, req *genprotos.RevertProductRequest) (*genprotos.ProductRevision, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		version     int64
+		oldPrice    int64
+		oldCurrency string
+	)
+	err = tx.QueryRowContext(ctx, "SELECT version, price_amount, currency FROM products WHERE id = $1 FOR UPDATE", req.ProductId).Scan(&version, &oldPrice, &oldCurrency)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product: %v", err)
+	}
+	if req.ExpectedVersion != 0 && req.ExpectedVersion != version {
+		return nil, status.Errorf(codes.Aborted, "product %s was modified by someone else (version %d, revert is based on %d)", req.ProductId, version, req.ExpectedVersion)
+	}
+	if req.Version == version {
+		return nil, status.Errorf(codes.FailedPrecondition, "product %s is already at version %d", req.ProductId, version)
+	}
+
+	var document []byte
+	err = tx.QueryRowContext(ctx, "SELECT snapshot FROM product_revisions WHERE product_id = $1 AND version = $2", req.ProductId, req.Version).Scan(&document)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s has no version %d", req.ProductId, req.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product revision: %v", err)
+	}
+
+	var snapshot productSnapshot
+	if err := json.Unmarshal(document, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode product revision: %v", err)
+	}
+
+	var categoryExists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM product_categories WHERE id::text = $1)", snapshot.CategoryID).Scan(&categoryExists); err != nil {
+		return nil, fmt.Errorf("failed to verify category: %v", err)
+	}
+	if !categoryExists {
+		return nil, status.Errorf(codes.FailedPrecondition, "category %s of version %d no longer exists", snapshot.CategoryID, req.Version)
+	}
+
+	attributes := string(snapshot.Attributes)
+	if attributes == "" || attributes == "null" {
+		attributes = "{}"
+	}
+	if snapshot.Tags == nil {
+		snapshot.Tags = []string{}
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE products
+		SET name = $1, description = $2, price_amount = $3, currency = $4, category_id = $5,
+			tags = $6, attributes = $7, version = version + 1, updated_at = $8
+		WHERE id = $9
+	`, snapshot.Name, snapshot.Description, snapshot.PriceAmount, snapshot.Currency, snapshot.CategoryID,
+		pq.Array(snapshot.Tags), attributes, time.Now(), req.ProductId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert product: %v", err)
+	}
+	if err := p.notifyPriceDrop(ctx, tx, req.ProductId, oldPrice, oldCurrency); err != nil {
+		return nil, err
+	}
+
+	revision, err := p.recordRevision(ctx, tx, req.ProductId, req.ActorId, revisionReverted, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return revision, nil
+}
+
+// recordRevision snapshots the product as it is now, inside the caller's
+// transaction. Every mutation of a product's catalog fields bumps its
+// version and then records one revision for the new version. Stock level
+// changes are tracked by inventory instead and do not create revisions.
+func (p *Product) recordRevision(ctx context.Context, q queryer, productID, actorID, action string, restoredVersion int64) (*genprotos.ProductRevision, error) {
+	var actor sql.NullString
+	if actorID != "" {
+		if _, err := uuid.Parse(actorID); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "actor_id %q is not a valid id", actorID)
+		}
+		actor = sql.NullString{String: actorID, Valid: true}
+	}
+	restored := sql.NullInt64{Int64: restoredVersion, Valid: restoredVersion > 0}
+
+	var (
+		snapshot productSnapshot
+		version  int64
+		tags     pq.StringArray
+	)
+	err := q.QueryRowContext(ctx, `
+		SELECT name, COALESCE(description, ''), price_amount, currency, COALESCE(category_id::text, ''),
+			quantity, tags, attributes, status, version
+		FROM products WHERE id = $1
+	`, productID).Scan(&snapshot.Name, &snapshot.Description, &snapshot.PriceAmount, &snapshot.Currency, &snapshot.CategoryID,
+		&snapshot.Quantity, &tags, &snapshot.Attributes, &snapshot.Status, &version)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", productID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot product: %v", err)
+	}
+	snapshot.Tags = tags
+
+	document, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode product snapshot: %v", err)
+	}
+
+	revision, err := scanRevision(q.QueryRowContext(ctx, `
+		INSERT INTO product_revisions (id, product_id, version, actor_id, action, restored_version, snapshot, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+revisionColumns,
+		uuid.NewString(), productID, version, actor, action, restored, document, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record product revision: %v", err)
+	}
+	return revision, nil
+}
+
+// orderLines loads an order's items together with the product revision each
+// one was bought at.
+func (p *Product) orderLines(ctx context.Context, q queryer, orderID string) ([]*genprotos.OrderLine, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT oi.id, oi.product_id, oi.quantity, oi.price_amount, oi.currency,
+			COALESCE(oi.base_price_amount, oi.price_amount), COALESCE(oi.base_currency, oi.currency),
+			oi.exchange_rate, oi.product_revision, pr.snapshot, COALESCE(oi.fulfillment_id::text, ''),
+			COALESCE(oi.product_name, pr.snapshot->>'name', ''), COALESCE(oi.image_key, '')
+		FROM order_items oi
+		LEFT JOIN product_revisions pr ON pr.product_id = oi.product_id AND pr.version = oi.product_revision
+		WHERE oi.order_id = $1
+		ORDER BY oi.id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items: %v", err)
+	}
+	defer rows.Close()
+
+	lines := make([]*genprotos.OrderLine, 0)
+	for rows.Next() {
+		var (
+			line                    genprotos.OrderLine
+			priceAmount, baseAmount int64
+			currency, baseCurrency  string
+			rate                    string
+			revision                sql.NullInt64
+			document                []byte
+			imageKey                string
+		)
+		err := rows.Scan(&line.Id, &line.ProductId, &line.Quantity, &priceAmount, &currency, &baseAmount, &baseCurrency, &rate, &revision, &document, &line.FulfillmentId, &line.ProductName, &imageKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order item row: %v", err)
+		}
+		line.Price = money.New(priceAmount, currency)
+		line.BasePrice = money.New(baseAmount, baseCurrency)
+		if parsed, err := money.ParseRate(rate); err == nil {
+			line.ExchangeRate = money.FormatRate(parsed)
+		}
+		line.ProductRevision = revision.Int64
+		if imageKey != "" {
+			line.ImageUrl = p.media.URL(imageKey)
+		}
+		if document != nil {
+			if line.Product, err = decodeSnapshot(document); err != nil {
+				return nil, err
+			}
+		}
+		lines = append(lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over order items rows: %v", err)
+	}
+	return lines, nil
+}
+
+func scanRevision(row rowScanner) (*genprotos.ProductRevision, error) {
+	var (
+		revision  genprotos.ProductRevision
+		actor     sql.NullString
+		restored  sql.NullInt64
+		document  []byte
+		createdAt time.Time
+	)
+	err := row.Scan(&revision.Id, &revision.ProductId, &revision.Version, &actor, &revision.Action, &restored, &document, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	revision.ActorId = actor.String
+	revision.RestoredVersion = restored.Int64
+	revision.CreatedAt = createdAt.Format(time.RFC3339)
+
+	revision.Snapshot, err = decodeSnapshot(document)
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func decodeSnapshot(document []byte) (*genprotos.ProductSnapshot, error) {
+	var snapshot productSnapshot
+	if err := json.Unmarshal(document, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode product snapshot: %v", err)
+	}
+	attributes, err := decodeAttributes(snapshot.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &genprotos.ProductSnapshot{
+		Name:        snapshot.Name,
+		Description: snapshot.Description,
+		Price:       money.New(snapshot.PriceAmount, snapshot.Currency),
+		CategoryId:  snapshot.CategoryID,
+		Quantity:    snapshot.Quantity,
+		Tags:        snapshot.Tags,
+		Attributes:  attributes,
+		Status:      snapshot.Status,
+	}, nil
+}
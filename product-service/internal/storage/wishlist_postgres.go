@@ -0,0 +1,404 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"armiya/equipment-service/genprotos"
+	"armiya/equipment-service/internal/money"
+)
+
+// defaultWishlistName is the list products are saved to when no list is
+// named.
+const defaultWishlistName = "Saved for later"
+
+func (p *Product) CreateWishlist(ctx context.Context, req *genprotos.CreateWishlistRequest) (*genprotos.Wishlist, error) {
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", req.UserId)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, status.Error(codes.InvalidArgument, "name must be between 1 and 100 characters")
+	}
+
+	id := uuid.NewString()
+	_, err := p.db.ExecContext(ctx, "INSERT INTO wishlists (id, user_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)", id, req.UserId, name, time.Now())
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, status.Errorf(codes.AlreadyExists, "you already have a list named %q", name)
+		}
+		return nil, fmt.Errorf("failed to create wishlist: %v", err)
+	}
+
+	return p.loadWishlist(ctx, p.db, id, "", false)
+}
+
+func (p *Product) ListWishlists(ctx context.Context, req *genprotos.ListWishlistsRequest) (*genprotos.ListWishlistsResponse, error) {
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", req.UserId)
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT w.id, w.user_id, w.name, COALESCE(w.share_token, ''), w.created_at, w.updated_at, COUNT(wi.product_id)
+		FROM wishlists w
+		LEFT JOIN wishlist_items wi ON wi.wishlist_id = w.id
+		WHERE w.user_id = $1
+		GROUP BY w.id
+		ORDER BY w.created_at, w.id
+	`, req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wishlists: %v", err)
+	}
+	defer rows.Close()
+
+	wishlists := make([]*genprotos.Wishlist, 0)
+	for rows.Next() {
+		var (
+			wishlist             genprotos.Wishlist
+			createdAt, updatedAt time.Time
+		)
+		if err := rows.Scan(&wishlist.Id, &wishlist.UserId, &wishlist.Name, &wishlist.ShareToken, &createdAt, &updatedAt, &wishlist.ItemCount); err != nil {
+			return nil, fmt.Errorf("failed to scan wishlist row: %v", err)
+		}
+		wishlist.CreatedAt = createdAt.Format(time.RFC3339)
+		wishlist.UpdatedAt = updatedAt.Format(time.RFC3339)
+		wishlists = append(wishlists, &wishlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over wishlist rows: %v", err)
+	}
+
+	return &genprotos.ListWishlistsResponse{Wishlists: wishlists}, nil
+}
+
+func (p *Product) GetWishlist(ctx context.Context, req *genprotos.GetWishlistRequest) (*genprotos.Wishlist, error) {
+	if req.ShareToken != "" {
+		var id string
+		err := p.db.QueryRowContext(ctx, "SELECT id FROM wishlists WHERE share_token = $1", req.ShareToken).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, status.Error(codes.NotFound, "this wishlist is not shared")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch wishlist: %v", err)
+		}
+		return p.loadWishlist(ctx, p.db, id, req.Currency, true)
+	}
+
+	if err := ownWishlist(ctx, p.db, req.Id, req.UserId); err != nil {
+		return nil, err
+	}
+	return p.loadWishlist(ctx, p.db, req.Id, req.Currency, false)
+}
+
+func (p *Product) DeleteWishlist(ctx context.Context, req *genprotos.DeleteWishlistRequest) (*genprotos.Message, error) {
+	if err := ownWishlist(ctx, p.db, req.Id, req.UserId); err != nil {
+		return nil, err
+	}
+	if _, err := p.db.ExecContext(ctx, "DELETE FROM wishlists WHERE id = $1", req.Id); err != nil {
+		return nil, fmt.Errorf("failed to delete wishlist: %v", err)
+	}
+	return &genprotos.Message{Message: "Wishlist deleted successfully"}, nil
+}
+
+func (p *Product) AddWishlistItem(ctx context.Context, req *genprotos.WishlistItemRequest) (*genprotos.Wishlist, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	wishlistID := req.WishlistId
+	if wishlistID == "" {
+		if wishlistID, err = defaultWishlist(ctx, tx, req.UserId); err != nil {
+			return nil, err
+		}
+	} else if err := ownWishlist(ctx, tx, wishlistID, req.UserId); err != nil {
+		return nil, err
+	}
+
+	price, currency, err := shoppableProductPrice(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	// Saving a product twice keeps the price it was first saved at.
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO wishlist_items (wishlist_id, product_id, price_amount, currency, added_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wishlist_id, product_id) DO NOTHING
+	`, wishlistID, req.ProductId, price, currency, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save product: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE wishlists SET updated_at = $1 WHERE id = $2", now, wishlistID); err != nil {
+		return nil, fmt.Errorf("failed to update wishlist: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return p.loadWishlist(ctx, p.db, wishlistID, req.Currency, false)
+}
+
+func (p *Product) RemoveWishlistItem(ctx context.Context, req *genprotos.WishlistItemRequest) (*genprotos.Wishlist, error) {
+	if err := ownWishlist(ctx, p.db, req.WishlistId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.db.ExecContext(ctx, "DELETE FROM wishlist_items WHERE wishlist_id = $1 AND product_id = $2", req.WishlistId, req.ProductId); err != nil {
+		return nil, fmt.Errorf("failed to remove saved product: %v", err)
+	}
+	if _, err := p.db.ExecContext(ctx, "UPDATE wishlists SET updated_at = $1 WHERE id = $2", time.Now(), req.WishlistId); err != nil {
+		return nil, fmt.Errorf("failed to update wishlist: %v", err)
+	}
+
+	return p.loadWishlist(ctx, p.db, req.WishlistId, req.Currency, false)
+}
+
+func (p *Product) ShareWishlist(ctx context.Context, req *genprotos.ShareWishlistRequest) (*genprotos.Wishlist, error) {
+	if err := ownWishlist(ctx, p.db, req.Id, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if req.Shared {
+		token, err := shareToken()
+		if err != nil {
+			return nil, err
+		}
+		// Sharing again keeps the link that is already out there.
+		if _, err := p.db.ExecContext(ctx, "UPDATE wishlists SET share_token = COALESCE(share_token, $1), updated_at = $2 WHERE id = $3", token, time.Now(), req.Id); err != nil {
+			return nil, fmt.Errorf("failed to share wishlist: %v", err)
+		}
+	} else if _, err := p.db.ExecContext(ctx, "UPDATE wishlists SET share_token = NULL, updated_at = $1 WHERE id = $2", time.Now(), req.Id); err != nil {
+		return nil, fmt.Errorf("failed to stop sharing wishlist: %v", err)
+	}
+
+	return p.loadWishlist(ctx, p.db, req.Id, "", false)
+}
+
+// MoveWishlistItemToCart takes a saved product off the list and puts it in
+// the user's cart.
+func (p *Product) MoveWishlistItemToCart(ctx context.Context, req *genprotos.MoveWishlistItemRequest) (*genprotos.Cart, error) {
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity > maxCartQuantity {
+		return nil, status.Error(codes.InvalidArgument, "quantity is too large")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := ownWishlist(ctx, tx, req.WishlistId, req.UserId); err != nil {
+		return nil, err
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM wishlist_items WHERE wishlist_id = $1 AND product_id = $2", req.WishlistId, req.ProductId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove saved product: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to remove saved product: %v", err)
+	} else if affected == 0 {
+		return nil, status.Errorf(codes.NotFound, "product %s is not in the wishlist", req.ProductId)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE wishlists SET updated_at = $1 WHERE id = $2", time.Now(), req.WishlistId); err != nil {
+		return nil, fmt.Errorf("failed to update wishlist: %v", err)
+	}
+
+	cartID, err := p.resolveCart(ctx, tx, "", req.UserId, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := addToCart(ctx, tx, cartID, req.ProductId, quantity); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return p.loadCart(ctx, p.db, cartID, req.UserId, req.Currency)
+}
+
+// GetProductSaveCounts tells an artisan how many shoppers saved each of
+// their products, most saved first.
+func (p *Product) GetProductSaveCounts(ctx context.Context, req *genprotos.GetProductSaveCountsRequest) (*genprotos.GetProductSaveCountsResponse, error) {
+	if req.ArtisanId == "" {
+		return nil, status.Error(codes.InvalidArgument, "artisan_id is required")
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT p.id, p.name, COUNT(DISTINCT w.user_id), COUNT(w.id)
+		FROM products p
+		LEFT JOIN wishlist_items wi ON wi.product_id = p.id
+		LEFT JOIN wishlists w ON w.id = wi.wishlist_id
+		WHERE p.artisan_id::text = $1 AND ($2 = '' OR p.id::text = $2)
+		GROUP BY p.id, p.name
+		ORDER BY COUNT(DISTINCT w.user_id) DESC, p.name
+	`, req.ArtisanId, req.ProductId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count product saves: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make([]*genprotos.ProductSaveCount, 0)
+	for rows.Next() {
+		var count genprotos.ProductSaveCount
+		if err := rows.Scan(&count.ProductId, &count.Name, &count.Users, &count.Wishlists); err != nil {
+			return nil, fmt.Errorf("failed to scan product save count: %v", err)
+		}
+		counts = append(counts, &count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over product save counts: %v", err)
+	}
+	if req.ProductId != "" && len(counts) == 0 {
+		return nil, status.Errorf(codes.NotFound, "product %s not found among the artisan's products", req.ProductId)
+	}
+
+	return &genprotos.GetProductSaveCountsResponse{Counts: counts}, nil
+}
+
+// loadWishlist reads a list with its products priced in the requested
+// currency. Shared views leave out who owns the list.
+func (p *Product) loadWishlist(ctx context.Context, q queryer, id, requested string, shared bool) (*genprotos.Wishlist, error) {
+	currency, err := displayCurrency(requested)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = p.currency.Default
+	}
+
+	var (
+		wishlist             genprotos.Wishlist
+		createdAt, updatedAt time.Time
+	)
+	err = q.QueryRowContext(ctx, "SELECT id, user_id, name, COALESCE(share_token, ''), created_at, updated_at FROM wishlists WHERE id = $1", id).
+		Scan(&wishlist.Id, &wishlist.UserId, &wishlist.Name, &wishlist.ShareToken, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "wishlist %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wishlist: %v", err)
+	}
+	wishlist.CreatedAt = createdAt.Format(time.RFC3339)
+	wishlist.UpdatedAt = updatedAt.Format(time.RFC3339)
+	if shared {
+		wishlist.UserId = ""
+	}
+
+	rates, err := p.loadRateTable(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT wi.product_id, p.name, wi.price_amount, wi.currency, p.price_amount, p.currency, p.quantity, p.status, wi.added_at
+		FROM wishlist_items wi
+		JOIN products p ON p.id = wi.product_id
+		WHERE wi.wishlist_id = $1
+		ORDER BY wi.added_at DESC, wi.product_id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch saved products: %v", err)
+	}
+	defer rows.Close()
+
+	wishlist.Items = make([]*genprotos.WishlistItem, 0)
+	for rows.Next() {
+		var (
+			item                        genprotos.WishlistItem
+			savedAmount, priceAmount    int64
+			savedCurrency, baseCurrency string
+			productStatus               string
+			addedAt                     time.Time
+		)
+		err := rows.Scan(&item.ProductId, &item.Name, &savedAmount, &savedCurrency, &priceAmount, &baseCurrency, &item.InStock, &productStatus, &addedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved product: %v", err)
+		}
+
+		price, _, err := p.convertPrice(rates, priceAmount, baseCurrency, currency)
+		if err != nil {
+			return nil, err
+		}
+		saved, _, err := p.convertPrice(rates, savedAmount, savedCurrency, currency)
+		if err != nil {
+			return nil, err
+		}
+		item.Price = money.New(price, currency)
+		item.SavedPrice = money.New(saved, currency)
+		item.Available = productStatus == productPublished && item.InStock > 0
+		item.AddedAt = addedAt.Format(time.RFC3339)
+		wishlist.Items = append(wishlist.Items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over saved products: %v", err)
+	}
+	wishlist.ItemCount = uint64(len(wishlist.Items))
+
+	return &wishlist, nil
+}
+
+// ownWishlist checks that a list exists and belongs to userID. Someone
+// else's list is reported as missing.
+func ownWishlist(ctx context.Context, q queryer, id, userID string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return status.Errorf(codes.InvalidArgument, "wishlist id %q is not a valid id", id)
+	}
+	var owner string
+	err := q.QueryRowContext(ctx, "SELECT user_id FROM wishlists WHERE id = $1", id).Scan(&owner)
+	if err == sql.ErrNoRows || (err == nil && owner != userID) {
+		return status.Errorf(codes.NotFound, "wishlist %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch wishlist: %v", err)
+	}
+	return nil
+}
+
+// defaultWishlist returns the user's "Saved for later" list, creating it on
+// first use.
+func defaultWishlist(ctx context.Context, q queryer, userID string) (string, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "user_id %q is not a valid id", userID)
+	}
+
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO wishlists (id, user_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id, lower(name)) DO NOTHING
+	`, uuid.NewString(), userID, defaultWishlistName, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to create wishlist: %v", err)
+	}
+
+	var id string
+	if err := q.QueryRowContext(ctx, "SELECT id FROM wishlists WHERE user_id = $1 AND lower(name) = lower($2)", userID, defaultWishlistName).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to fetch wishlist: %v", err)
+	}
+	return id, nil
+}
+
+func shareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
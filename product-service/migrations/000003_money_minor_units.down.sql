@@ -0,0 +1,13 @@
+ALTER TABLE payments DROP COLUMN currency;
+ALTER TABLE payments ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+
+ALTER TABLE orders DROP COLUMN currency;
+ALTER TABLE orders ALTER COLUMN total_amount TYPE DECIMAL(10, 2) USING total_amount / 100.0;
+
+ALTER TABLE order_items DROP COLUMN currency;
+ALTER TABLE order_items RENAME COLUMN price_amount TO price;
+ALTER TABLE order_items ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0;
+
+ALTER TABLE products DROP COLUMN currency;
+ALTER TABLE products RENAME COLUMN price_amount TO price;
+ALTER TABLE products ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0;
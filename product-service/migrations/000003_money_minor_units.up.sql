@@ -0,0 +1,15 @@
+-- Money columns hold integer minor units (cents) plus an ISO 4217 currency.
+-- Existing rows were priced in USD.
+ALTER TABLE products ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100);
+ALTER TABLE products RENAME COLUMN price TO price_amount;
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE order_items ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100);
+ALTER TABLE order_items RENAME COLUMN price TO price_amount;
+ALTER TABLE order_items ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE orders ALTER COLUMN total_amount TYPE BIGINT USING ROUND(total_amount * 100);
+ALTER TABLE orders ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE payments ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+ALTER TABLE payments ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
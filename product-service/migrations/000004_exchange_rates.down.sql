@@ -0,0 +1,5 @@
+ALTER TABLE order_items DROP COLUMN exchange_rate;
+ALTER TABLE order_items DROP COLUMN base_currency;
+ALTER TABLE order_items DROP COLUMN base_price_amount;
+
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,16 @@
+CREATE TABLE exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency)
+);
+
+-- Order lines remember the product's own price and the rate that turned it
+-- into the order currency at checkout.
+ALTER TABLE order_items ADD COLUMN base_price_amount BIGINT;
+ALTER TABLE order_items ADD COLUMN base_currency CHAR(3);
+ALTER TABLE order_items ADD COLUMN exchange_rate NUMERIC(24, 12) NOT NULL DEFAULT 1;
+
+UPDATE order_items SET base_price_amount = price_amount, base_currency = currency;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS product_categories_parent_idx;
+DROP INDEX IF EXISTS product_categories_slug_idx;
+
+ALTER TABLE product_categories DROP COLUMN updated_at;
+ALTER TABLE product_categories DROP COLUMN position;
+ALTER TABLE product_categories DROP COLUMN slug;
+ALTER TABLE product_categories DROP COLUMN parent_id;
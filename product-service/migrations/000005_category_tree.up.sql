@@ -0,0 +1,21 @@
+ALTER TABLE product_categories ADD COLUMN parent_id UUID REFERENCES product_categories(id);
+ALTER TABLE product_categories ADD COLUMN slug VARCHAR(120);
+ALTER TABLE product_categories ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE product_categories ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+-- Derive slugs for existing categories, numbering any that collide.
+UPDATE product_categories
+SET slug = COALESCE(NULLIF(TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(name, '[^a-zA-Z0-9]+', '-', 'g'))), ''), 'category');
+
+UPDATE product_categories c
+SET slug = c.slug || '-' || d.n
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS n
+    FROM product_categories
+) d
+WHERE c.id = d.id AND d.n > 1;
+
+ALTER TABLE product_categories ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX product_categories_slug_idx ON product_categories (slug);
+CREATE INDEX product_categories_parent_idx ON product_categories (parent_id, position);
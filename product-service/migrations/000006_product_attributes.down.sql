@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS category_attributes;
+
+DROP INDEX IF EXISTS products_attributes_idx;
+DROP INDEX IF EXISTS products_tags_idx;
+
+ALTER TABLE products DROP COLUMN attributes;
+ALTER TABLE products DROP COLUMN tags;
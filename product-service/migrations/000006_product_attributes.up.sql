@@ -0,0 +1,19 @@
+ALTER TABLE products ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX products_tags_idx ON products USING GIN (tags);
+CREATE INDEX products_attributes_idx ON products USING GIN (attributes);
+
+-- Attribute schemas are defined per category and inherited by subcategories.
+CREATE TABLE category_attributes (
+    id UUID PRIMARY KEY,
+    category_id UUID NOT NULL REFERENCES product_categories(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('string', 'number', 'boolean', 'enum')),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (category_id, key)
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS products_publish_at_idx;
+DROP INDEX IF EXISTS products_status_idx;
+
+ALTER TABLE products DROP COLUMN archived_at;
+ALTER TABLE products DROP COLUMN published_at;
+ALTER TABLE products DROP COLUMN publish_at;
+ALTER TABLE products DROP COLUMN status;
@@ -0,0 +1,14 @@
+-- Products that already exist stay live; new ones start as drafts.
+ALTER TABLE products ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived', 'out_of_stock'));
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE products ADD COLUMN publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE products ADD COLUMN published_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE products ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE products SET published_at = created_at;
+UPDATE products SET status = 'out_of_stock' WHERE quantity <= 0;
+
+CREATE INDEX products_status_idx ON products (status);
+CREATE INDEX products_publish_at_idx ON products (publish_at) WHERE status = 'draft' AND publish_at IS NOT NULL;
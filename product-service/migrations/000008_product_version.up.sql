@@ -0,0 +1,2 @@
+-- Bumped on every edit so concurrent editors can detect stale writes.
+ALTER TABLE products ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+ALTER TABLE order_items DROP COLUMN product_revision;
+
+DROP TABLE IF EXISTS product_revisions;
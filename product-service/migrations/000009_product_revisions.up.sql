@@ -0,0 +1,32 @@
+CREATE TABLE product_revisions (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    actor_id UUID,
+    action VARCHAR(30) NOT NULL,
+    restored_version BIGINT,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, version)
+);
+
+-- Start every existing product's history with its current state.
+INSERT INTO product_revisions (id, product_id, version, actor_id, action, snapshot, created_at)
+SELECT md5(p.id::text || p.version::text)::uuid, p.id, p.version, p.artisan_id, 'created',
+    jsonb_build_object(
+        'name', p.name,
+        'description', COALESCE(p.description, ''),
+        'price_amount', p.price_amount,
+        'currency', p.currency,
+        'category_id', COALESCE(p.category_id::text, ''),
+        'quantity', p.quantity,
+        'tags', to_jsonb(p.tags),
+        'attributes', p.attributes,
+        'status', p.status
+    ),
+    COALESCE(p.updated_at, CURRENT_TIMESTAMP)
+FROM products p;
+
+-- The product version each order line was bought at. Older lines predate
+-- revisions and stay NULL.
+ALTER TABLE order_items ADD COLUMN product_revision BIGINT;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS product_import_jobs;
+
+DROP INDEX IF EXISTS products_artisan_sku_idx;
+ALTER TABLE products DROP COLUMN sku;
@@ -0,0 +1,26 @@
+-- SKUs are the artisan's own product codes; bulk imports upsert by them.
+ALTER TABLE products ADD COLUMN sku VARCHAR(64);
+CREATE UNIQUE INDEX products_artisan_sku_idx ON products (artisan_id, sku) WHERE sku IS NOT NULL;
+
+CREATE TABLE product_import_jobs (
+    id UUID PRIMARY KEY,
+    artisan_id UUID NOT NULL,
+    actor_id UUID,
+    format VARCHAR(10) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    payload BYTEA,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    row_errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX product_import_jobs_pending_idx ON product_import_jobs (created_at) WHERE status = 'pending';
+CREATE INDEX product_import_jobs_artisan_idx ON product_import_jobs (artisan_id, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_quantity_nonnegative;
+
+ALTER TABLE orders DROP COLUMN reserved_until;
+
+DROP TABLE IF EXISTS stock_reservations;
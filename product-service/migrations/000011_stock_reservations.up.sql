@@ -0,0 +1,23 @@
+-- Stock taken by an order is held against it until the order is paid
+-- (committed) or cancelled, refunded or left unpaid past expires_at
+-- (released, and the quantity goes back on the product).
+CREATE TABLE stock_reservations (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'held'
+        CHECK (status IN ('held', 'committed', 'released')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX stock_reservations_order_idx ON stock_reservations (order_id);
+CREATE INDEX stock_reservations_expiry_idx ON stock_reservations (expires_at) WHERE status = 'held';
+
+ALTER TABLE orders ADD COLUMN reserved_until TIMESTAMP WITH TIME ZONE;
+
+-- NOT VALID keeps the migration from failing on rows that are already
+-- negative; new writes are still checked.
+ALTER TABLE products ADD CONSTRAINT products_quantity_nonnegative CHECK (quantity >= 0) NOT VALID;
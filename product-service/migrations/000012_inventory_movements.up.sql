@@ -0,0 +1,40 @@
+-- Every change to a product's quantity is recorded here. The sum of a
+-- product's changes is its stock; quantity on products is kept alongside so
+-- reads stay cheap, and reconciliation compares the two.
+CREATE TABLE inventory_movements (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    change INTEGER NOT NULL CHECK (change <> 0),
+    quantity_after INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL
+        CHECK (reason IN ('initial', 'sale', 'cancellation', 'return', 'adjustment', 'import', 'correction')),
+    actor_id UUID,
+    reference VARCHAR(100),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX inventory_movements_product_idx ON inventory_movements (product_id, created_at DESC);
+
+-- Existing stock becomes each product's opening balance.
+INSERT INTO inventory_movements (id, product_id, change, quantity_after, reason, note, created_at)
+SELECT md5(id::text || 'opening')::uuid, id, quantity, quantity, 'initial', 'opening balance', CURRENT_TIMESTAMP
+FROM products
+WHERE quantity <> 0;
+
+ALTER TABLE products ADD COLUMN low_stock_threshold INTEGER CHECK (low_stock_threshold > 0);
+
+-- One open alert per product while its stock is at or below its threshold.
+CREATE TABLE low_stock_alerts (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    artisan_id UUID NOT NULL,
+    threshold INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX low_stock_alerts_open_idx ON low_stock_alerts (product_id) WHERE resolved_at IS NULL;
+CREATE INDEX low_stock_alerts_artisan_idx ON low_stock_alerts (artisan_id, created_at DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notifications;
+
+DROP TABLE IF EXISTS wishlist_items;
+
+DROP TABLE IF EXISTS wishlists;
@@ -0,0 +1,11 @@
+ALTER TABLE carts DROP COLUMN IF EXISTS coupon_codes;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal_amount;
+
+DROP TABLE IF EXISTS order_discounts;
+
+DROP TABLE IF EXISTS promotion_redemptions;
+
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,62 @@
+-- Discount rules. Rules without a code apply automatically; coded rules
+-- (coupons) only when the buyer enters the code. A rule can be limited to
+-- one artisan's products and/or one category and the categories below it.
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(50) UNIQUE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('percentage', 'fixed', 'free_shipping', 'buy_x_get_y')),
+    percent INTEGER NOT NULL DEFAULT 0 CHECK (percent BETWEEN 0 AND 100),
+    amount BIGINT NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    currency CHAR(3),
+    buy_quantity INTEGER NOT NULL DEFAULT 0 CHECK (buy_quantity >= 0),
+    get_quantity INTEGER NOT NULL DEFAULT 0 CHECK (get_quantity >= 0),
+    artisan_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES product_categories(id) ON DELETE CASCADE,
+    min_subtotal_amount BIGINT NOT NULL DEFAULT 0 CHECK (min_subtotal_amount >= 0),
+    min_subtotal_currency CHAR(3),
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    usage_limit INTEGER NOT NULL DEFAULT 0 CHECK (usage_limit >= 0),
+    per_user_limit INTEGER NOT NULL DEFAULT 0 CHECK (per_user_limit >= 0),
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per order a promotion was used on. Rows of cancelled or expired
+-- orders are deleted so the use counts towards the limits again.
+CREATE TABLE promotion_redemptions (
+    promotion_id UUID NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (promotion_id, order_id)
+);
+
+CREATE INDEX promotion_redemptions_user_idx ON promotion_redemptions (promotion_id, user_id);
+
+-- Discounts as they were applied to an order, kept even if the promotion
+-- is changed or removed later.
+CREATE TABLE order_discounts (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id UUID REFERENCES promotions(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(50),
+    kind VARCHAR(20) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX order_discounts_order_idx ON order_discounts (order_id, position);
+
+-- total_amount = subtotal_amount + shipping_amount - discount_amount.
+ALTER TABLE orders ADD COLUMN subtotal_amount BIGINT;
+ALTER TABLE orders ADD COLUMN shipping_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN discount_amount BIGINT NOT NULL DEFAULT 0;
+UPDATE orders SET subtotal_amount = total_amount;
+ALTER TABLE orders ALTER COLUMN subtotal_amount SET NOT NULL;
+
+ALTER TABLE carts ADD COLUMN coupon_codes TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+-- Order statuses are a fixed set now. Orders written before may hold other
+-- values, so the check only applies to new and updated rows.
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('pending', 'paid', 'processing', 'shipped', 'delivered', 'completed', 'cancelled', 'refunded', 'returned'))
+    NOT VALID;
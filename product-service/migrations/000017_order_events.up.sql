@@ -0,0 +1,27 @@
+-- Everything that happened to an order, oldest first: when it was placed,
+-- each status change, payments and shipping updates. orders keeps only the
+-- current state.
+CREATE TABLE order_events (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('placed', 'status', 'payment', 'shipping')),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20),
+    actor_id UUID,
+    actor_role VARCHAR(20),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX order_events_order_idx ON order_events (order_id, created_at, id);
+
+-- Existing orders start their timeline when they were placed and, if they
+-- moved since, with the status they are in now.
+INSERT INTO order_events (id, order_id, kind, to_status, actor_id, actor_role, created_at)
+SELECT md5(id::text || 'placed')::uuid, id, 'placed', 'pending', user_id, 'buyer', created_at
+FROM orders;
+
+INSERT INTO order_events (id, order_id, kind, to_status, note, created_at)
+SELECT md5(id::text || 'backfill')::uuid, id, 'status', status, 'recorded before the timeline existed', COALESCE(updated_at, created_at)
+FROM orders
+WHERE status <> 'pending';
@@ -0,0 +1,5 @@
+ALTER TABLE order_events DROP COLUMN IF EXISTS fulfillment_id;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS fulfillment_id;
+
+DROP TABLE IF EXISTS order_fulfillments;
@@ -0,0 +1,68 @@
+-- Each artisan ships their part of an order on their own. An order holds one
+-- fulfillment per artisan with its own status, shipping details and payout;
+-- the order's status follows from them.
+CREATE TABLE order_fulfillments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    artisan_id UUID REFERENCES users(id),
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('pending', 'paid', 'processing', 'shipped', 'delivered', 'completed', 'cancelled', 'refunded', 'returned')),
+    currency CHAR(3) NOT NULL,
+    subtotal_amount BIGINT NOT NULL,
+    shipping_amount BIGINT NOT NULL DEFAULT 0,
+    discount_amount BIGINT NOT NULL DEFAULT 0,
+    -- What the artisan is owed: the items after discounts plus the share of
+    -- shipping. refunded_amount is what went back to the buyer.
+    payout_amount BIGINT NOT NULL,
+    refunded_amount BIGINT NOT NULL DEFAULT 0,
+    tracking_number VARCHAR(100),
+    carrier VARCHAR(100),
+    estimated_delivery_date VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX order_fulfillments_order_idx ON order_fulfillments (order_id);
+CREATE INDEX order_fulfillments_artisan_idx ON order_fulfillments (artisan_id, created_at DESC);
+
+ALTER TABLE order_items ADD COLUMN fulfillment_id UUID REFERENCES order_fulfillments(id) ON DELETE CASCADE;
+ALTER TABLE order_events ADD COLUMN fulfillment_id UUID REFERENCES order_fulfillments(id) ON DELETE CASCADE;
+
+-- Existing orders get one fulfillment per artisan in the order's status.
+-- Shipping and discounts are split by subtotal, the largest group taking
+-- what rounding leaves over.
+WITH groups AS (
+    SELECT oi.order_id, p.artisan_id, SUM(oi.price_amount * oi.quantity) AS subtotal
+    FROM order_items oi
+    LEFT JOIN products p ON p.id = oi.product_id
+    GROUP BY oi.order_id, p.artisan_id
+), shares AS (
+    SELECT g.order_id, g.artisan_id, g.subtotal, o.status, o.currency, o.shipping_amount AS order_shipping, o.discount_amount AS order_discount,
+        o.shipping_address, o.created_at, o.updated_at,
+        ROW_NUMBER() OVER (PARTITION BY g.order_id ORDER BY g.subtotal DESC, g.artisan_id) AS rank,
+        CASE WHEN SUM(g.subtotal) OVER w = 0 THEN 0 ELSE o.shipping_amount * g.subtotal / SUM(g.subtotal) OVER w END AS shipping,
+        CASE WHEN SUM(g.subtotal) OVER w = 0 THEN 0 ELSE o.discount_amount * g.subtotal / SUM(g.subtotal) OVER w END AS discount
+    FROM groups g
+    JOIN orders o ON o.id = g.order_id
+    WINDOW w AS (PARTITION BY g.order_id)
+), settled AS (
+    SELECT s.*,
+        s.shipping + CASE WHEN s.rank = 1 THEN s.order_shipping - SUM(s.shipping) OVER (PARTITION BY s.order_id) ELSE 0 END AS shipping_share,
+        s.discount + CASE WHEN s.rank = 1 THEN s.order_discount - SUM(s.discount) OVER (PARTITION BY s.order_id) ELSE 0 END AS discount_share
+    FROM shares s
+)
+INSERT INTO order_fulfillments (id, order_id, artisan_id, status, currency, subtotal_amount, shipping_amount, discount_amount, payout_amount,
+    refunded_amount, tracking_number, carrier, estimated_delivery_date, created_at, updated_at)
+SELECT md5(order_id::text || COALESCE(artisan_id::text, 'none'))::uuid, order_id, artisan_id,
+    CASE WHEN status IN ('pending', 'paid', 'processing', 'shipped', 'delivered', 'completed', 'cancelled', 'refunded', 'returned') THEN status ELSE 'pending' END,
+    currency, subtotal, shipping_share, discount_share, subtotal + shipping_share - discount_share,
+    CASE WHEN status = 'refunded' OR EXISTS (SELECT 1 FROM payments WHERE order_id = settled.order_id AND status = 'refunded')
+        THEN subtotal + shipping_share - discount_share ELSE 0 END,
+    shipping_address->>'tracking_number', shipping_address->>'carrier', shipping_address->>'estimated_delivery_date',
+    created_at, updated_at
+FROM settled;
+
+UPDATE order_items oi SET fulfillment_id = f.id
+FROM order_fulfillments f
+WHERE f.order_id = oi.order_id
+  AND f.artisan_id IS NOT DISTINCT FROM (SELECT artisan_id FROM products WHERE id = oi.product_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS orders_user_created_idx;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS image_key;
+ALTER TABLE order_items DROP COLUMN IF EXISTS product_name;
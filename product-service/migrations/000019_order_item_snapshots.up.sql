@@ -0,0 +1,15 @@
+-- Order items keep the name and picture the buyer saw, so order history
+-- still shows them after the product is renamed, re-photographed or
+-- archived.
+ALTER TABLE order_items ADD COLUMN product_name VARCHAR(100);
+ALTER TABLE order_items ADD COLUMN image_key TEXT;
+
+UPDATE order_items oi SET product_name = COALESCE(
+    (SELECT pr.snapshot->>'name' FROM product_revisions pr WHERE pr.product_id = oi.product_id AND pr.version = oi.product_revision),
+    (SELECT p.name FROM products p WHERE p.id = oi.product_id)
+);
+UPDATE order_items oi SET image_key = pi.object_key
+FROM product_images pi
+WHERE pi.product_id = oi.product_id AND pi.is_primary;
+
+CREATE INDEX orders_user_created_idx ON orders (user_id, created_at DESC, id DESC);
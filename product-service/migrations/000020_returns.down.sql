@@ -0,0 +1,13 @@
+DELETE FROM order_events WHERE kind IN ('return', 'refund');
+ALTER TABLE order_events DROP CONSTRAINT order_events_kind_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_kind_check
+    CHECK (kind IN ('placed', 'status', 'payment', 'shipping'));
+
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS return_photos;
+DROP TABLE IF EXISTS return_items;
+DROP TABLE IF EXISTS returns;
+
+UPDATE payments SET status = 'paid' WHERE status = 'partially_refunded';
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_amount;
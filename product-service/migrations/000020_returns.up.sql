@@ -0,0 +1,78 @@
+-- Payments can be given back in part. refunded_amount is how much of a
+-- payment went back to the buyer so far; each refund is kept in refunds.
+ALTER TABLE payments ADD COLUMN refunded_amount BIGINT NOT NULL DEFAULT 0;
+UPDATE payments SET refunded_amount = amount WHERE status = 'refunded';
+
+-- NOT VALID keeps the migration from failing on rows with other statuses;
+-- new writes are still checked.
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('paid', 'partially_refunded', 'refunded')) NOT VALID;
+
+-- A buyer asks to send back some of the items of one artisan's part of a
+-- delivered order. The artisan approves or rejects it; an approved return
+-- is shipped back, received and refunded.
+CREATE TABLE returns (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    fulfillment_id UUID NOT NULL REFERENCES order_fulfillments(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id),
+    artisan_id UUID REFERENCES users(id),
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('requested', 'approved', 'rejected', 'cancelled', 'shipped', 'received', 'refunded')),
+    reason TEXT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    -- What the returned items are worth to the buyer until the return is
+    -- received, then what the artisan agreed to give back.
+    refund_amount BIGINT NOT NULL DEFAULT 0,
+    refunded_amount BIGINT NOT NULL DEFAULT 0,
+    tracking_number VARCHAR(100),
+    carrier VARCHAR(100),
+    resolution_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    received_at TIMESTAMP WITH TIME ZONE,
+    refunded_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX returns_order_idx ON returns (order_id);
+CREATE INDEX returns_user_idx ON returns (user_id, created_at DESC);
+CREATE INDEX returns_artisan_idx ON returns (artisan_id, created_at DESC);
+
+CREATE TABLE return_items (
+    return_id UUID NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (return_id, order_item_id)
+);
+
+CREATE INDEX return_items_order_item_idx ON return_items (order_item_id);
+
+CREATE TABLE return_photos (
+    id UUID PRIMARY KEY,
+    return_id UUID NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX return_photos_return_idx ON return_photos (return_id, position);
+
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY,
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    fulfillment_id UUID REFERENCES order_fulfillments(id) ON DELETE SET NULL,
+    return_id UUID REFERENCES returns(id) ON DELETE SET NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refunds_payment_idx ON refunds (payment_id);
+CREATE INDEX refunds_order_idx ON refunds (order_id);
+
+ALTER TABLE order_events DROP CONSTRAINT order_events_kind_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_kind_check
+    CHECK (kind IN ('placed', 'status', 'payment', 'shipping', 'return', 'refund'));
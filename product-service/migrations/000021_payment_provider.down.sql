@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS payments_order_idx;
+
+DELETE FROM payments WHERE status NOT IN ('paid', 'partially_refunded', 'refunded');
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('paid', 'partially_refunded', 'refunded')) NOT VALID;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS challenge_url;
+ALTER TABLE payments DROP COLUMN IF EXISTS failure_message;
+ALTER TABLE payments DROP COLUMN IF EXISTS failure_code;
+ALTER TABLE payments DROP COLUMN IF EXISTS provider_status;
+ALTER TABLE payments DROP COLUMN IF EXISTS provider;
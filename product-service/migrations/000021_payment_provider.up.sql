@@ -0,0 +1,16 @@
+-- Payments are made through a payment provider. Each attempt is kept, with
+-- the provider's transaction and what it said; only a captured payment
+-- counts as paid. Payments from before keep a NULL provider.
+ALTER TABLE payments ADD COLUMN provider VARCHAR(20);
+ALTER TABLE payments ADD COLUMN provider_status VARCHAR(30);
+ALTER TABLE payments ADD COLUMN failure_code VARCHAR(50);
+ALTER TABLE payments ADD COLUMN failure_message TEXT;
+ALTER TABLE payments ADD COLUMN challenge_url TEXT;
+ALTER TABLE payments ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE;
+UPDATE payments SET updated_at = created_at;
+
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('requires_action', 'authorized', 'declined', 'failed', 'voided', 'paid', 'partially_refunded', 'refunded')) NOT VALID;
+
+CREATE INDEX payments_order_idx ON payments (order_id, created_at DESC);
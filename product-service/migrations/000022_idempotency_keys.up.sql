@@ -0,0 +1,14 @@
+-- Idempotency keys let clients retry order creation and payments safely.
+-- The first request under a key stores its response, which later requests
+-- with the same key get back; the hash catches a key reused for different
+-- input. Keys are forgotten after a day.
+CREATE TABLE idempotency_keys (
+    operation VARCHAR(20) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (operation, key)
+);
+
+CREATE INDEX idempotency_keys_created_idx ON idempotency_keys (created_at);
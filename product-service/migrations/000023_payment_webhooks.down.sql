@@ -0,0 +1,7 @@
+UPDATE payments SET status = 'refunded' WHERE status = 'charged_back';
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('requires_action', 'authorized', 'declined', 'failed', 'voided', 'paid', 'partially_refunded', 'refunded')) NOT VALID;
+
+DROP INDEX IF EXISTS payments_transaction_idx;
+DROP TABLE IF EXISTS payment_events;
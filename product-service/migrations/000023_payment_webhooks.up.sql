@@ -0,0 +1,18 @@
+-- Payment providers confirm captures, refunds and chargebacks by webhook.
+-- Events are kept by their id so one delivered twice is applied once.
+CREATE TABLE payment_events (
+    id VARCHAR(100) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(100) NOT NULL,
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX payment_events_payment_idx ON payment_events (payment_id);
+CREATE INDEX payments_transaction_idx ON payments (transaction_id);
+
+-- The card's bank can take a payment back.
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('requires_action', 'authorized', 'declined', 'failed', 'voided', 'paid', 'partially_refunded', 'refunded', 'charged_back')) NOT VALID;
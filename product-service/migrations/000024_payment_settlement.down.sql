@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS payments_void_due_idx;
+ALTER TABLE payments DROP COLUMN IF EXISTS void_due_at;
+
+DROP INDEX IF EXISTS refunds_pending_idx;
+ALTER TABLE refunds DROP COLUMN IF EXISTS settled_at;
+ALTER TABLE refunds DROP COLUMN IF EXISTS due_at;
+ALTER TABLE refunds DROP COLUMN IF EXISTS last_error;
+ALTER TABLE refunds DROP COLUMN IF EXISTS attempts;
+ALTER TABLE refunds DROP COLUMN IF EXISTS status;
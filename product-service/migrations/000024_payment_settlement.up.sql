@@ -0,0 +1,16 @@
+-- Refunds and voids reach the payment provider only after the change that
+-- asked for them is committed, so a rolled back change never moves money.
+-- Until then a refund is pending and a payment to void has void_due_at set;
+-- both are retried at their due time when the provider cannot be reached.
+ALTER TABLE refunds ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'succeeded'
+    CHECK (status IN ('pending', 'succeeded', 'failed'));
+ALTER TABLE refunds ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE refunds ADD COLUMN last_error TEXT;
+ALTER TABLE refunds ADD COLUMN due_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE refunds ADD COLUMN settled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX refunds_pending_idx ON refunds (due_at) WHERE status = 'pending';
+
+ALTER TABLE payments ADD COLUMN void_due_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX payments_void_due_idx ON payments (void_due_at) WHERE void_due_at IS NOT NULL;
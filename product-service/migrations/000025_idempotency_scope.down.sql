@@ -0,0 +1,6 @@
+DELETE FROM idempotency_keys a USING idempotency_keys b
+WHERE a.operation = b.operation AND a.key = b.key AND a.scope > b.scope;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (operation, key);
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS scope;
@@ -0,0 +1,7 @@
+-- Idempotency keys are chosen by clients, so two callers can pick the same
+-- one. Each key now belongs to a scope, the user or order it was sent for,
+-- and is only looked up within it.
+ALTER TABLE idempotency_keys ADD COLUMN scope TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (operation, scope, key);
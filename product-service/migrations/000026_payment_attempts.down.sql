@@ -0,0 +1,6 @@
+UPDATE payments SET status = 'failed', failure_code = 'timeout' WHERE status = 'pending';
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('requires_action', 'authorized', 'declined', 'failed', 'voided', 'paid', 'partially_refunded', 'refunded', 'charged_back')) NOT VALID;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS payment_token;
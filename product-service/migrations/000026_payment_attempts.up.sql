@@ -0,0 +1,9 @@
+-- A payment is recorded as pending before the provider is asked to
+-- authorize it, and stays pending while the provider's answer is unknown.
+-- Its card token is kept until then, so the authorization can be sent again
+-- under the same idempotency key.
+ALTER TABLE payments ADD COLUMN payment_token TEXT;
+
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'requires_action', 'authorized', 'declined', 'failed', 'voided', 'paid', 'partially_refunded', 'refunded', 'charged_back')) NOT VALID;